@@ -3,6 +3,7 @@ package abi
 import (
 	"fmt"
 	"reflect"
+	"sync"
 	"unicode"
 
 	"github.com/defiweb/go-anymapper"
@@ -24,13 +25,27 @@ var Default = NewABI()
 // The package provides default ABI instance that is used by the package-level
 // functions. It is possible to create custom ABI instances and use them
 // instead of the default one.
+//
+// The ABI methods are safe for concurrent use. Custom types should be added
+// using the RegisterType method, which may be called concurrently with
+// parsing. The Types map may be modified directly only before the ABI
+// instance is used by other goroutines.
 type ABI struct {
 	// Types is a map of known ABI types.
 	// The key is the name of the type, and the value is the type.
+	//
+	// To add types after the ABI instance is in use, use RegisterType
+	// instead of modifying the map directly.
 	Types map[string]Type
 
 	// Mapper is used to map values to and from ABI types.
 	Mapper Mapper
+
+	mu sync.RWMutex // Guards Types.
+
+	cacheMu  sync.Mutex             // Guards cacheGen and cache.
+	cacheGen uint64                 // Incremented every time the cache is reset.
+	cache    map[parseCacheKey]Type // Cache of parsed types.
 }
 
 // Mapper used to map values to and from ABI types.
@@ -175,6 +190,93 @@ func NewABI() *ABI {
 	}
 }
 
+// RegisterType adds a custom type to the ABI. If a type with the same name
+// already exists, it will be overwritten.
+//
+// It is safe to call RegisterType concurrently with other ABI methods.
+func (a *ABI) RegisterType(name string, typ Type) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.Types == nil {
+		a.Types = make(map[string]Type)
+	}
+	a.Types[name] = typ
+	a.resetParseCache()
+}
+
+// SetFieldMapper sets the function used to map Go struct field names to ABI
+// argument names. The function is used only for fields that do not have
+// the "abi" tag. If fn is nil, DefaultFieldMapper is used.
+//
+// SetFieldMapper replaces the Mapper with a copy that uses the given
+// function, so it works only with the mapper created by NewABI. It must be
+// called before the ABI instance is used by other goroutines.
+func (a *ABI) SetFieldMapper(fn func(field string) string) error {
+	m, ok := a.Mapper.(*anymapper.Mapper)
+	if !ok {
+		return fmt.Errorf("abi: unable to set field mapper for mapper of type %T", a.Mapper)
+	}
+	if fn == nil {
+		fn = DefaultFieldMapper
+	}
+	cpy := m.Copy()
+	cpy.Context.FieldMapper = fn
+	a.Mapper = cpy
+	return nil
+}
+
+// lookupType returns the type with the given name or nil if the type is not
+// known.
+func (a *ABI) lookupType(name string) Type {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return a.Types[name]
+}
+
+// parseCacheKey is a key of the parse cache. The same string may be parsed
+// differently, or not at all, by different parsers, so each parser uses its
+// own namespace.
+type parseCacheKey struct {
+	parser    string // parser is the name of the parser, "type" or "struct".
+	signature string
+}
+
+// cachedType returns a type previously parsed by the given parser for the
+// given signature. It also returns the current cache generation, which must
+// be passed to cacheType after parsing the type.
+func (a *ABI) cachedType(parser, signature string) (Type, uint64, bool) {
+	a.cacheMu.Lock()
+	defer a.cacheMu.Unlock()
+	typ, ok := a.cache[parseCacheKey{parser: parser, signature: signature}]
+	return typ, a.cacheGen, ok
+}
+
+// cacheType stores a type parsed by the given parser for the given
+// signature. The type is not stored if the cache was reset after the given
+// generation was obtained, because the type may have been parsed using the
+// old types.
+func (a *ABI) cacheType(parser, signature string, typ Type, gen uint64) {
+	a.cacheMu.Lock()
+	defer a.cacheMu.Unlock()
+	if gen != a.cacheGen {
+		return
+	}
+	if a.cache == nil {
+		a.cache = make(map[parseCacheKey]Type)
+	}
+	a.cache[parseCacheKey{parser: parser, signature: signature}] = typ
+}
+
+// resetParseCache removes all cached types. It must be called every time
+// the Types map is modified, because cached types may refer to the old
+// types.
+func (a *ABI) resetParseCache() {
+	a.cacheMu.Lock()
+	defer a.cacheMu.Unlock()
+	a.cacheGen++
+	a.cache = nil
+}
+
 // fieldMapper lowercase the first letter of the field name. If the field name
 // starts with an acronym, it will lowercase the whole acronym. For example:
 //   - "User" will be mapped to "user"
@@ -199,6 +301,16 @@ var fieldMapper = func(field string) string {
 	return string(runes)
 }
 
+// DefaultFieldMapper is the function used by default to map Go struct field
+// names to ABI argument names. See SetFieldMapper.
+//
+// It lowercases the first letter of the field name, or the whole acronym if
+// the field name starts with one, e.g. "UserID" is mapped to "userID" and
+// "DAPPName" to "dappName".
+func DefaultFieldMapper(field string) string {
+	return fieldMapper(field)
+}
+
 func addr(v reflect.Value) reflect.Value {
 	if v.Kind() == reflect.Ptr {
 		return v
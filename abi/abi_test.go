@@ -1,13 +1,18 @@
 package abi
 
 import (
+	"bytes"
+	"encoding/json"
+	"fmt"
 	"math/big"
 	"reflect"
+	"sync"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/defiweb/go-eth/hexutil"
 	"github.com/defiweb/go-eth/types"
 )
 
@@ -1079,11 +1084,11 @@ func Test_mappingRules(t *testing.T) {
 			wantEncErr: true,
 		},
 		{
-			name:       "types.Hash<=>int#decode-error",
-			goTyp:      new(types.Hash),
-			solTyp:     "int",
-			src:        1,
-			wantDecErr: true,
+			name:    "types.Hash<=>int#decode",
+			goTyp:   new(types.Hash),
+			solTyp:  "int",
+			src:     1,
+			wantDst: types.MustHashFromHex("0x01", types.PadLeft),
 		},
 
 		// types.Hash <=> uintX
@@ -1095,11 +1100,11 @@ func Test_mappingRules(t *testing.T) {
 			wantEncErr: true,
 		},
 		{
-			name:       "types.Hash<=>uint#decode-error",
-			goTyp:      new(types.Hash),
-			solTyp:     "uint",
-			src:        1,
-			wantDecErr: true,
+			name:    "types.Hash<=>uint#decode",
+			goTyp:   new(types.Hash),
+			solTyp:  "uint",
+			src:     1,
+			wantDst: types.MustHashFromHex("0x01", types.PadLeft),
 		},
 
 		// types.Hash <=> bool
@@ -1515,6 +1520,20 @@ func Test_mappingRules(t *testing.T) {
 			src:        types.BlockNumberFromBigInt(big.NewInt(0x1234)),
 			wantEncErr: true,
 		},
+		{
+			name:       "types.BlockNumber<=>uint#Latest",
+			goTyp:      new(types.BlockNumber),
+			solTyp:     "uint",
+			src:        types.LatestBlockNumber,
+			wantEncErr: true,
+		},
+		{
+			name:       "types.BlockNumber<=>uint#Finalized",
+			goTyp:      new(types.BlockNumber),
+			solTyp:     "uint",
+			src:        types.FinalizedBlockNumber,
+			wantEncErr: true,
+		},
 
 		// types.BlockNumber <=> bool
 		{
@@ -1734,6 +1753,322 @@ func TestABI_decodeToMap(t *testing.T) {
 	assert.Equal(t, int64(1), dst["bigInt"].(*big.Int).Int64())
 }
 
+func TestABI_decodeDynamicArrays(t *testing.T) {
+	type tuple struct {
+		A []string `abi:"a"`
+		B [][]byte `abi:"b"`
+		C uint64   `abi:"c"`
+	}
+	tests := []struct {
+		typ      string
+		abi      Words
+		dst      any
+		expected any
+	}{
+		{
+			typ:      "string[]",
+			abi:      Words{padL("0x00")},
+			dst:      &[]string{},
+			expected: &[]string{},
+		},
+		{
+			typ:      "bytes[]",
+			abi:      Words{padL("0x00")},
+			dst:      &[][]byte{},
+			expected: &[][]byte{},
+		},
+		{
+			// Example from the Solidity ABI specification.
+			typ: "string[]",
+			abi: Words{
+				padL("0x03"),
+				padL("0x60"),
+				padL("0xa0"),
+				padL("0xe0"),
+				padL("0x03"),
+				padR("0x6f6e65"),
+				padL("0x03"),
+				padR("0x74776f"),
+				padL("0x05"),
+				padR("0x7468726565"),
+			},
+			dst:      &[]string{},
+			expected: &[]string{"one", "two", "three"},
+		},
+		{
+			typ: "bytes[]",
+			abi: Words{
+				padL("0x03"),
+				padL("0x60"),
+				padL("0x80"),
+				padL("0xc0"),
+				padL("0x00"),
+				padL("0x01"),
+				padR("0x01"),
+				padL("0x21"),
+				padR("0x0202020202020202020202020202020202020202020202020202020202020202"),
+				padR("0x03"),
+			},
+			dst: &[][]byte{},
+			expected: &[][]byte{
+				{},
+				{0x01},
+				hexutil.MustHexToBytes("0x020202020202020202020202020202020202020202020202020202020202020203"),
+			},
+		},
+		{
+			typ: "(string[] a, bytes[] b, uint256 c)",
+			abi: Words{
+				padL("0x60"),
+				padL("0x0120"),
+				padL("0x2a"),
+				padL("0x02"),
+				padL("0x40"),
+				padL("0x80"),
+				padL("0x01"),
+				padR("0x61"),
+				padL("0x00"),
+				padL("0x01"),
+				padL("0x20"),
+				padL("0x02"),
+				padR("0x0102"),
+			},
+			dst:      &tuple{},
+			expected: &tuple{A: []string{"a", ""}, B: [][]byte{{0x01, 0x02}}, C: 42},
+		},
+	}
+	for n, tt := range tests {
+		t.Run(fmt.Sprintf("case-%d", n+1), func(t *testing.T) {
+			typ := MustParseType(tt.typ)
+			require.NoError(t, DecodeValue(typ, tt.abi.Bytes(), tt.dst))
+			assert.Equal(t, tt.expected, tt.dst)
+
+			// Encoding the decoded value must produce the same data.
+			enc, err := EncodeValue(typ, tt.expected)
+			require.NoError(t, err)
+			assert.Equal(t, tt.abi.Bytes(), enc)
+		})
+	}
+}
+
+func TestABI_decodeNestedTupleArrays(t *testing.T) {
+	type populatedTick struct {
+		Tick           int32    `abi:"tick"`
+		LiquidityNet   *big.Int `abi:"liquidityNet"`
+		LiquidityGross *big.Int `abi:"liquidityGross"`
+	}
+	type pair struct {
+		A uint64 `abi:"a"`
+		B string `abi:"b"`
+	}
+	tests := []struct {
+		typ      string
+		abi      Words
+		dst      any
+		expected any
+	}{
+		{
+			// Return value of the Uniswap V3 TickLens getPopulatedTicksInWord.
+			typ: "(int24 tick, int128 liquidityNet, uint128 liquidityGross)[]",
+			abi: Words{
+				padL("0x02"),
+				padL("0x0a"),
+				padL("0xfffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffb"),
+				padL("0x05"),
+				padL("0xfffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff6"),
+				padL("0x05"),
+				padL("0x0f"),
+			},
+			dst: &[]populatedTick{},
+			expected: &[]populatedTick{
+				{Tick: 10, LiquidityNet: big.NewInt(-5), LiquidityGross: big.NewInt(5)},
+				{Tick: -10, LiquidityNet: big.NewInt(5), LiquidityGross: big.NewInt(15)},
+			},
+		},
+		{
+			typ: "(uint256 a, string b)[][]",
+			abi: Words{
+				padL("0x02"),   // outer length
+				padL("0x40"),   // offset of outer[0]
+				padL("0x0100"), // offset of outer[1]
+				padL("0x01"),   // outer[0] length
+				padL("0x20"),   // offset of outer[0][0]
+				padL("0x01"),   // outer[0][0].a
+				padL("0x40"),   // offset of outer[0][0].b
+				padL("0x01"),   // outer[0][0].b length
+				padR("0x61"),   // outer[0][0].b
+				padL("0x00"),   // outer[1] length
+			},
+			dst:      &[][]pair{},
+			expected: &[][]pair{{{A: 1, B: "a"}}, {}},
+		},
+	}
+	for n, tt := range tests {
+		t.Run(fmt.Sprintf("case-%d", n+1), func(t *testing.T) {
+			typ := MustParseType(tt.typ)
+			require.NoError(t, DecodeValue(typ, tt.abi.Bytes(), tt.dst))
+			assert.Equal(t, tt.expected, tt.dst)
+
+			enc, err := EncodeValue(typ, tt.expected)
+			require.NoError(t, err)
+			assert.Equal(t, tt.abi.Bytes(), enc)
+		})
+	}
+}
+
+func TestABI_decodeDeeplyNestedTuples(t *testing.T) {
+	type item struct {
+		Data   []byte     `abi:"data"`
+		Values []*big.Int `abi:"values"`
+	}
+	type group struct {
+		Name  string `abi:"name"`
+		Items []item `abi:"items"`
+	}
+	type root struct {
+		ID     *big.Int  `abi:"id"`
+		Groups []group   `abi:"groups"`
+		Tags   [2]string `abi:"tags"`
+	}
+	typ := MustParseType("(uint256 id, (string name, (bytes data, uint256[] values)[] items)[] groups, string[2] tags)")
+	val := root{
+		ID: big.NewInt(1),
+		Groups: []group{
+			{
+				Name: "first",
+				Items: []item{
+					{Data: []byte{0x01}, Values: []*big.Int{big.NewInt(1), big.NewInt(2)}},
+					{Data: bytes.Repeat([]byte{0x02}, 33), Values: []*big.Int{}},
+				},
+			},
+			{Name: "", Items: []item{}},
+			{
+				Name:  "third",
+				Items: []item{{Data: []byte{}, Values: []*big.Int{big.NewInt(3)}}},
+			},
+		},
+		Tags: [2]string{"x", "y"},
+	}
+	enc, err := EncodeValue(typ, val)
+	require.NoError(t, err)
+
+	var dec root
+	require.NoError(t, DecodeValue(typ, enc, &dec))
+	assert.Equal(t, val, dec)
+}
+
+func TestABI_decodeInvalidOffsets(t *testing.T) {
+	tests := []struct {
+		typ string
+		abi Words
+	}{
+		{
+			// Negative offset.
+			typ: "string[]",
+			abi: Words{padL("0x01"), padL("0xffffffe0")},
+		},
+		{
+			// Negative array size.
+			typ: "uint256[]",
+			abi: Words{padL("0xffffffff")},
+		},
+		{
+			// Negative bytes size.
+			typ: "bytes",
+			abi: Words{padL("0x80000000")},
+		},
+		{
+			// Offset that does not fit in 32 bits.
+			typ: "string[]",
+			abi: Words{padL("0x01"), padL("0xffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffe0")},
+		},
+		{
+			// Offset pointing outside the data of a nested array.
+			typ: "(uint256 a, string b)[][]",
+			abi: Words{padL("0x01"), padL("0x20"), padL("0x01"), padL("0x20"), padL("0x01"), padL("0x0200")},
+		},
+	}
+	for n, tt := range tests {
+		t.Run(fmt.Sprintf("case-%d", n+1), func(t *testing.T) {
+			var dst any
+			assert.Error(t, DecodeValue(MustParseType(tt.typ), tt.abi.Bytes(), &dst))
+		})
+	}
+}
+
+func TestABI_encodeFromMap(t *testing.T) {
+	type inner struct {
+		B types.Address `abi:"b"`
+		C []string      `abi:"c"`
+	}
+	type elem struct {
+		X uint8  `abi:"x"`
+		Y []byte `abi:"y"`
+	}
+	type str struct {
+		A *big.Int   `abi:"a"`
+		D inner      `abi:"d"`
+		E []elem     `abi:"e"`
+		F [2]uint64  `abi:"f"`
+		G bool       `abi:"g"`
+		H types.Hash `abi:"h"`
+		I int64      `abi:"i"`
+	}
+
+	typ := MustParseType("(uint256 a, (address b, string[] c) d, (uint8 x, bytes y)[] e, uint256[2] f, bool g, bytes32 h, int256 i)")
+
+	// The map has the same shape as the one produced by encoding/json.
+	var src map[string]any
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"a": "0x10",
+		"d": {"b": "0x1111111111111111111111111111111111111111", "c": ["foo", "bar"]},
+		"e": [{"x": 1, "y": "0x0102"}, {"x": 2, "y": "0x"}],
+		"f": [1, "2"],
+		"g": true,
+		"h": "0x0000000000000000000000000000000000000000000000000000000000000001",
+		"i": -5
+	}`), &src))
+
+	want, err := EncodeValue(typ, str{
+		A: big.NewInt(16),
+		D: inner{B: types.MustAddressFromHex("0x1111111111111111111111111111111111111111"), C: []string{"foo", "bar"}},
+		E: []elem{{X: 1, Y: []byte{1, 2}}, {X: 2, Y: []byte{}}},
+		F: [2]uint64{1, 2},
+		G: true,
+		H: types.MustHashFromHex("0x01", types.PadLeft),
+		I: -5,
+	})
+	require.NoError(t, err)
+
+	got, err := EncodeValue(typ, src)
+	require.NoError(t, err)
+	assert.Equal(t, want, got)
+}
+
+func TestABI_encodeFromMapErrors(t *testing.T) {
+	tests := []struct {
+		typ string
+		src string
+	}{
+		{typ: "(uint256 a)", src: `{"a": 1.5}`},
+		{typ: "(uint256 a)", src: `{"a": -1}`},
+		{typ: "(uint8 a)", src: `{"a": 256}`},
+		{typ: "(int8 a)", src: `{"a": -129}`},
+		{typ: "((uint8 b) a)", src: `{"a": {"b": 1e10}}`},
+		{typ: "(uint256 a)", src: `{"a": 1000000000000000001}`},
+		{typ: "(int256 a)", src: `{"a": -9007199254740995}`},
+		{typ: "((uint8 b)[] a)", src: `{"a": [{"b": "foo"}]}`},
+	}
+	for n, tt := range tests {
+		t.Run(fmt.Sprintf("case-%d", n+1), func(t *testing.T) {
+			var src map[string]any
+			require.NoError(t, json.Unmarshal([]byte(tt.src), &src))
+			_, err := EncodeValue(MustParseType(tt.typ), src)
+			assert.Error(t, err)
+		})
+	}
+}
+
 func TestABI_decodeToNil(t *testing.T) {
 	typ := MustParseType("(uint256 bigInt)")
 	abi := Words{padL("0x01")}
@@ -1780,3 +2115,102 @@ func Test_fieldMapper(t *testing.T) {
 		})
 	}
 }
+
+func TestABI_SetFieldMapper(t *testing.T) {
+	type data struct {
+		UserID *big.Int
+		Amount *big.Int `abi:"value"`
+	}
+	typ := MustParseType("(uint256 user_id, uint256 value)")
+	enc := Words{padL("0x01"), padL("0x02")}.Bytes()
+
+	a := NewABI()
+	require.NoError(t, a.SetFieldMapper(func(field string) string {
+		if field == "UserID" {
+			return "user_id"
+		}
+		return field
+	}))
+
+	var d data
+	require.NoError(t, a.DecodeValue(typ, enc, &d))
+	assert.Equal(t, big.NewInt(1), d.UserID)
+	assert.Equal(t, big.NewInt(2), d.Amount)
+
+	// The default ABI instance must not be affected.
+	var d2 data
+	require.NoError(t, Default.DecodeValue(typ, enc, &d2))
+	assert.Nil(t, d2.UserID)
+
+	// Restore the default mapper.
+	require.NoError(t, a.SetFieldMapper(nil))
+	var d3 data
+	require.NoError(t, a.DecodeValue(MustParseType("(uint256 userID)"), enc[:32], &d3))
+	assert.Equal(t, big.NewInt(1), d3.UserID)
+
+	a.Mapper = nil
+	assert.Error(t, a.SetFieldMapper(DefaultFieldMapper))
+}
+
+func TestABI_RegisterType(t *testing.T) {
+	a := NewABI()
+
+	_, err := a.ParseType("Point")
+	require.Error(t, err)
+
+	a.RegisterType("Point", a.MustParseStruct("struct {int256 x; int256 y;}"))
+	typ, err := a.ParseType("Point[]")
+	require.NoError(t, err)
+	assert.Equal(t, "(int256,int256)[]", typ.CanonicalType())
+
+	// Parsed types must be cached.
+	typ2, err := a.ParseType("Point[]")
+	require.NoError(t, err)
+	assert.Same(t, typ, typ2)
+
+	// Registering a type must invalidate the cache.
+	a.RegisterType("Point", a.MustParseStruct("struct {uint256 x; uint256 y;}"))
+	typ3, err := a.ParseType("Point[]")
+	require.NoError(t, err)
+	assert.Equal(t, "(uint256,uint256)[]", typ3.CanonicalType())
+
+	// A type parsed before the cache was reset must not be cached.
+	_, gen, _ := a.cachedType("type", "Point")
+	stale := a.MustParseType("Point")
+	a.RegisterType("Point", a.MustParseStruct("struct {int8 x; int8 y;}"))
+	a.cacheType("type", "Point", stale, gen)
+	typ4, err := a.ParseType("Point")
+	require.NoError(t, err)
+	assert.Equal(t, "(int8,int8)", typ4.CanonicalType())
+
+	// Types and structs are cached separately.
+	b := NewABI()
+	_, err = b.ParseStruct("uint256")
+	require.Error(t, err)
+	_, err = b.ParseType("uint256")
+	require.NoError(t, err)
+	_, err = b.ParseStruct("uint256")
+	require.Error(t, err)
+}
+
+func TestABI_ConcurrentAccess(t *testing.T) {
+	a := NewABI()
+	wg := sync.WaitGroup{}
+	for i := 0; i < 10; i++ {
+		wg.Add(2)
+		go func(i int) {
+			defer wg.Done()
+			a.RegisterType(fmt.Sprintf("Custom%d", i), NewUintType(256))
+		}(i)
+		go func() {
+			defer wg.Done()
+			_, err := a.ParseType("(uint256 a, bytes32 b)[]")
+			assert.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+	for i := 0; i < 10; i++ {
+		_, err := a.ParseType(fmt.Sprintf("Custom%d", i))
+		assert.NoError(t, err)
+	}
+}
@@ -0,0 +1,140 @@
+package abi
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/defiweb/go-eth/hexutil"
+	"github.com/defiweb/go-eth/types"
+)
+
+// ParseStringArg converts a string into a value that can be encoded as the
+// given type. It is useful for tools that accept arguments from the command
+// line.
+//
+// The following formats are supported:
+//   - intX, uintX: decimal or 0x-prefixed hex numbers, optionally negative
+//   - bool: "true", "false", "1", "0" and other values accepted by
+//     strconv.ParseBool
+//   - address: 0x-prefixed hex address
+//   - bytes, bytesX: 0x-prefixed hex data
+//   - string: used as-is
+//   - arrays and tuples: JSON arrays, whose elements are either JSON strings
+//     in one of the formats above or bare JSON values, e.g. [1,"0x2"] or
+//     [["0x1111111111111111111111111111111111111111",true]]
+func ParseStringArg(t Type, s string) (any, error) {
+	switch typ := t.(type) {
+	case *AliasType:
+		return ParseStringArg(typ.Type(), s)
+	case *UintType, *IntType:
+		return parseStringInt(t, s)
+	case *BoolType:
+		b, err := strconv.ParseBool(strings.TrimSpace(s))
+		if err != nil {
+			return nil, fmt.Errorf("abi: cannot parse %q as bool", s)
+		}
+		return b, nil
+	case *AddressType:
+		a, err := types.AddressFromHex(strings.TrimSpace(s))
+		if err != nil {
+			return nil, fmt.Errorf("abi: cannot parse %q as address: %w", s, err)
+		}
+		return a, nil
+	case *BytesType, *FixedBytesType:
+		b, err := hexutil.HexToBytes(strings.TrimSpace(s))
+		if err != nil {
+			return nil, fmt.Errorf("abi: cannot parse %q as %s: %w", s, t.CanonicalType(), err)
+		}
+		return b, nil
+	case *StringType:
+		return s, nil
+	case *ArrayType:
+		return parseStringArray(typ.ElementType(), -1, s)
+	case *FixedArrayType:
+		return parseStringArray(typ.ElementType(), typ.Size(), s)
+	case *TupleType:
+		elems, err := splitStringArray(s)
+		if err != nil {
+			return nil, fmt.Errorf("abi: cannot parse %q as %s: %w", s, t.CanonicalType(), err)
+		}
+		if len(elems) != typ.Size() {
+			return nil, fmt.Errorf("abi: cannot parse %q as %s: expected %d elements, got %d", s, t.CanonicalType(), typ.Size(), len(elems))
+		}
+		res := make(map[string]any, len(elems))
+		for i, elem := range typ.Elements() {
+			v, err := ParseStringArg(elem.Type, elems[i])
+			if err != nil {
+				return nil, err
+			}
+			name := elem.Name
+			if len(name) == 0 {
+				name = fmt.Sprintf("arg%d", i)
+			}
+			res[name] = v
+		}
+		return res, nil
+	default:
+		return nil, fmt.Errorf("abi: cannot parse string as %s", t.CanonicalType())
+	}
+}
+
+// parseStringInt parses a decimal or a hex number.
+func parseStringInt(t Type, s string) (*big.Int, error) {
+	s = strings.TrimSpace(s)
+	if hexutil.Has0xPrefix(strings.TrimPrefix(s, "-")) {
+		x, err := hexutil.HexToBigInt(s)
+		if err != nil {
+			return nil, fmt.Errorf("abi: cannot parse %q as %s: %w", s, t.CanonicalType(), err)
+		}
+		return x, nil
+	}
+	x, ok := new(big.Int).SetString(s, 10)
+	if !ok {
+		return nil, fmt.Errorf("abi: cannot parse %q as %s", s, t.CanonicalType())
+	}
+	return x, nil
+}
+
+// parseStringArray parses a JSON array. If size is not negative, the array
+// must have exactly size elements.
+func parseStringArray(t Type, size int, s string) ([]any, error) {
+	elems, err := splitStringArray(s)
+	if err != nil {
+		return nil, fmt.Errorf("abi: cannot parse %q as %s[]: %w", s, t.CanonicalType(), err)
+	}
+	if size >= 0 && len(elems) != size {
+		return nil, fmt.Errorf("abi: cannot parse %q as %s[%d]: expected %d elements, got %d", s, t.CanonicalType(), size, size, len(elems))
+	}
+	res := make([]any, len(elems))
+	for i, elem := range elems {
+		v, err := ParseStringArg(t, elem)
+		if err != nil {
+			return nil, err
+		}
+		res[i] = v
+	}
+	return res, nil
+}
+
+// splitStringArray splits a JSON array into its elements. String elements
+// are unquoted, other elements are returned as-is.
+func splitStringArray(s string) ([]string, error) {
+	var raw []json.RawMessage
+	if err := json.Unmarshal([]byte(s), &raw); err != nil {
+		return nil, err
+	}
+	res := make([]string, len(raw))
+	for i, r := range raw {
+		if len(r) > 0 && r[0] == '"' {
+			if err := json.Unmarshal(r, &res[i]); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		res[i] = string(r)
+	}
+	return res, nil
+}
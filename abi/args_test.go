@@ -0,0 +1,56 @@
+package abi
+
+import (
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/defiweb/go-eth/types"
+)
+
+func TestParseStringArg(t *testing.T) {
+	tests := []struct {
+		typ     string
+		arg     string
+		want    any
+		wantErr bool
+	}{
+		{typ: "uint256", arg: "1000000000000000000", want: big.NewInt(1000000000000000000)},
+		{typ: "uint256", arg: "0x2a", want: big.NewInt(42)},
+		{typ: "uint256", arg: "010", want: big.NewInt(10)},
+		{typ: "int8", arg: "-0x2a", want: big.NewInt(-42)},
+		{typ: "int8", arg: "-42", want: big.NewInt(-42)},
+		{typ: "uint256", arg: "abc", wantErr: true},
+		{typ: "bool", arg: "true", want: true},
+		{typ: "bool", arg: "0", want: false},
+		{typ: "bool", arg: "yes", wantErr: true},
+		{typ: "address", arg: "0x1111111111111111111111111111111111111111", want: types.MustAddressFromHex("0x1111111111111111111111111111111111111111")},
+		{typ: "address", arg: "0x11", wantErr: true},
+		{typ: "bytes", arg: "0x0102", want: []byte{0x01, 0x02}},
+		{typ: "bytes4", arg: "0x01020304", want: []byte{0x01, 0x02, 0x03, 0x04}},
+		{typ: "bytes", arg: "xyz", wantErr: true},
+		{typ: "string", arg: " foo ", want: " foo "},
+		{typ: "uint256[]", arg: `[1,"0x2"]`, want: []any{big.NewInt(1), big.NewInt(2)}},
+		{typ: "uint256[]", arg: `[]`, want: []any{}},
+		{typ: "uint256[2]", arg: `[1]`, wantErr: true},
+		{typ: "uint256[][]", arg: `[[1],[]]`, want: []any{[]any{big.NewInt(1)}, []any{}}},
+		{typ: "(address a, bool b)", arg: `["0x1111111111111111111111111111111111111111", true]`, want: map[string]any{"a": types.MustAddressFromHex("0x1111111111111111111111111111111111111111"), "b": true}},
+		{typ: "(uint8,string)", arg: `[1,"x"]`, want: map[string]any{"arg0": big.NewInt(1), "arg1": "x"}},
+		{typ: "(uint8,string)", arg: `[1]`, wantErr: true},
+		{typ: "uint256[]", arg: `1,2`, wantErr: true},
+	}
+	for n, tt := range tests {
+		t.Run(fmt.Sprintf("case-%d", n+1), func(t *testing.T) {
+			v, err := ParseStringArg(MustParseType(tt.typ), tt.arg)
+			if tt.wantErr {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tt.want, v)
+			}
+		})
+	}
+}
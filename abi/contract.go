@@ -5,10 +5,14 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"sort"
 	"strconv"
 	"strings"
 
 	"github.com/defiweb/go-sigparser"
+
+	"github.com/defiweb/go-eth/hexutil"
+	"github.com/defiweb/go-eth/types"
 )
 
 // Contract provides a high-level API for interacting with a contract. It can
@@ -21,6 +25,13 @@ type Contract struct {
 	Events             map[string]*Event
 	Errors             map[string]*Error
 	Types              map[string]Type // Types defined in the ABI (structs, enums and user-defined Value Types)
+
+	// Bytecode and DeployedBytecode are only available if the contract was
+	// parsed from a Foundry or Hardhat artifact. They are nil if the
+	// artifact does not contain the bytecode or if the bytecode contains
+	// unlinked library references.
+	Bytecode         []byte
+	DeployedBytecode []byte
 }
 
 // IsError returns true if the given error data, returned by a contract call,
@@ -76,6 +87,163 @@ func (c *Contract) HandleError(err error) error {
 	return err
 }
 
+// DecodeInput finds the method whose selector matches the given calldata and
+// decodes its arguments. It can be used to decode the input of transactions
+// sent to the contract.
+//
+// The arguments are returned in the same order as they are defined in the
+// method.
+func (c *Contract) DecodeInput(input []byte) (*Method, []any, error) {
+	if len(input) < 4 {
+		return nil, nil, fmt.Errorf("abi: calldata too short to contain a method selector")
+	}
+	for _, m := range c.Methods {
+		if !m.FourBytes().Match(input[:4]) {
+			continue
+		}
+		args, err := decodeArgsToAny(m, input)
+		if err != nil {
+			return nil, nil, err
+		}
+		return m, args, nil
+	}
+	return nil, nil, fmt.Errorf("abi: no method found for selector 0x%x", input[:4])
+}
+
+// DecodeCalldataWith decodes the given calldata using the first contract from
+// the candidates list that has a method with a matching selector and whose
+// arguments can be decoded from the calldata. It is useful for tools that
+// have a library of known contracts and need to decode the input of
+// arbitrary transactions.
+//
+// If the selector matches a method but the arguments cannot be decoded, for
+// example because of a selector collision, the remaining candidates are
+// tried. The returned error is the last decoding error, if any.
+func DecodeCalldataWith(input []byte, candidates []*Contract) (*Method, []any, error) {
+	if len(input) < 4 {
+		return nil, nil, fmt.Errorf("abi: calldata too short to contain a method selector")
+	}
+	var lastErr error
+	for _, c := range candidates {
+		if c == nil {
+			continue
+		}
+		m, args, err := c.DecodeInput(input)
+		if err == nil {
+			return m, args, nil
+		}
+		if c.hasSelector(input[:4]) {
+			lastErr = err
+		}
+	}
+	if lastErr != nil {
+		return nil, nil, lastErr
+	}
+	return nil, nil, fmt.Errorf("abi: no method found for selector 0x%x", input[:4])
+}
+
+// DecodeCalldataBySignature parses the given method signature and decodes
+// the arguments from the calldata. It returns an error if the selector of the
+// calldata does not match the method signature.
+//
+// It is useful for one-off decoding when the method signature is known, but
+// no contract ABI is available. See ParseMethod for the signature format.
+//
+// The arguments are returned in the same order as they are defined in the
+// method.
+func DecodeCalldataBySignature(signature string, input []byte) ([]any, error) {
+	m, err := ParseMethod(signature)
+	if err != nil {
+		return nil, err
+	}
+	return decodeArgsToAny(m, input)
+}
+
+// decodeArgsToAny decodes the method arguments from the calldata into a
+// slice of values.
+func decodeArgsToAny(m *Method, input []byte) ([]any, error) {
+	args := make([]any, m.Inputs().Size())
+	ptrs := make([]any, len(args))
+	for i := range args {
+		ptrs[i] = &args[i]
+	}
+	if err := m.DecodeArgs(input, ptrs...); err != nil {
+		return nil, err
+	}
+	return args, nil
+}
+
+// hasSelector returns true if the contract has a method with the given
+// selector.
+func (c *Contract) hasSelector(sel []byte) bool {
+	for _, m := range c.Methods {
+		if m.FourBytes().Match(sel) {
+			return true
+		}
+	}
+	return false
+}
+
+// DecodedLog is a log together with the event it was decoded with and its
+// decoded arguments.
+type DecodedLog struct {
+	types.Log
+
+	// Event is the matched event.
+	Event *Event
+
+	// Values contains the decoded event arguments, as returned by
+	// Event.DecodeLogToMap.
+	Values map[string]any
+}
+
+// DecodeLogs decodes the given logs, for example the logs of a transaction
+// receipt, using the events of the contract.
+//
+// Every log is matched to an event by its topic0 and number of topics,
+// regardless of the address of the contract that emitted it, so logs of
+// other contracts that emit the same events are decoded as well. Logs that
+// do not match any event are skipped. Anonymous events are never matched,
+// because they cannot be recognized by their topics.
+func (c *Contract) DecodeLogs(logs []types.Log) ([]DecodedLog, error) {
+	names := make([]string, 0, len(c.Events))
+	for name, event := range c.Events {
+		if !event.IsAnonymous() {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	var res []DecodedLog
+	for i, log := range logs {
+		event := c.matchEvent(names, log)
+		if event == nil {
+			continue
+		}
+		values, err := event.DecodeLogToMap(log)
+		if err != nil {
+			return nil, fmt.Errorf("abi: cannot decode log %d as %s: %w", i, event.Name(), err)
+		}
+		res = append(res, DecodedLog{Log: log, Event: event, Values: values})
+	}
+	return res, nil
+}
+
+// matchEvent returns the first event from the given list of event names
+// that matches the topic0 and the number of topics of the log, or nil if
+// there is no such event.
+func (c *Contract) matchEvent(names []string, log types.Log) *Event {
+	if len(log.Topics) == 0 {
+		return nil
+	}
+	for _, name := range names {
+		event := c.Events[name]
+		if event.Topic0() == log.Topics[0] && event.Inputs().IndexedSize()+1 == len(log.Topics) {
+			return event
+		}
+	}
+	return nil
+}
+
 // RegisterTypes registers types defined in the contract to the given ABI
 // instance. This enables the use of types defined in the contract in all
 // Parse* methods.
@@ -83,12 +251,13 @@ func (c *Contract) HandleError(err error) error {
 // If the type name already exists, it will be overwritten.
 func (c *Contract) RegisterTypes(a *ABI) {
 	for n, t := range c.Types {
-		a.Types[n] = t
+		a.RegisterType(n, t)
 	}
 }
 
 // LoadJSON loads the ABI from the given JSON file and returns a Contract
-// instance.
+// instance. The file may contain a bare ABI array or a Foundry or Hardhat
+// artifact.
 func LoadJSON(path string) (*Contract, error) {
 	return Default.LoadJSON(path)
 }
@@ -99,6 +268,9 @@ func MustLoadJSON(path string) *Contract {
 }
 
 // ParseJSON parses the given ABI JSON and returns a Contract instance.
+//
+// The JSON may be either a bare ABI array or a Foundry or Hardhat artifact
+// object with the "abi" field.
 func ParseJSON(data []byte) (*Contract, error) {
 	return Default.ParseJSON(data)
 }
@@ -142,10 +314,28 @@ func (a *ABI) MustLoadJSON(path string) *Contract {
 }
 
 // ParseJSON parses the given ABI JSON and returns a Contract instance.
+//
+// The JSON may be either a bare ABI array or a Foundry or Hardhat artifact
+// object. In the latter case, the ABI is read from the "abi" field and the
+// bytecode from the "bytecode" and "deployedBytecode" fields.
 func (a *ABI) ParseJSON(data []byte) (*Contract, error) {
-	var fields []jsonField
-	if err := json.Unmarshal(data, &fields); err != nil {
-		return nil, err
+	var (
+		fields   []jsonField
+		artifact *jsonArtifact
+	)
+	if isJSONObject(data) {
+		artifact = &jsonArtifact{}
+		if err := json.Unmarshal(data, artifact); err != nil {
+			return nil, err
+		}
+		if artifact.ABI == nil {
+			return nil, errors.New("abi: artifact does not contain the abi field")
+		}
+		fields = artifact.ABI
+	} else {
+		if err := json.Unmarshal(data, &fields); err != nil {
+			return nil, err
+		}
 	}
 	c := &Contract{
 		Methods:            make(map[string]*Method),
@@ -154,6 +344,10 @@ func (a *ABI) ParseJSON(data []byte) (*Contract, error) {
 		Errors:             make(map[string]*Error),
 		Types:              make(map[string]Type),
 	}
+	if artifact != nil {
+		c.Bytecode = artifact.Bytecode
+		c.DeployedBytecode = artifact.DeployedBytecode
+	}
 	for _, f := range fields {
 		inputs, err := f.Inputs.toTypes(a)
 		if err != nil {
@@ -309,6 +503,59 @@ func (a *ABI) MustParseSignatures(signatures ...string) *Contract {
 	return c
 }
 
+// jsonArtifact is a build artifact emitted by Foundry or Hardhat.
+type jsonArtifact struct {
+	ABI              []jsonField      `json:"abi"`
+	Bytecode         jsonArtifactCode `json:"bytecode"`
+	DeployedBytecode jsonArtifactCode `json:"deployedBytecode"`
+}
+
+// jsonArtifactCode is the bytecode stored in an artifact. Hardhat stores it
+// as a hex string, while Foundry stores it as an object with the "object"
+// field.
+type jsonArtifactCode []byte
+
+func (b *jsonArtifactCode) UnmarshalJSON(data []byte) error {
+	var code string
+	if isJSONObject(data) {
+		var obj struct {
+			Object string `json:"object"`
+		}
+		if err := json.Unmarshal(data, &obj); err != nil {
+			return err
+		}
+		code = obj.Object
+	} else if err := json.Unmarshal(data, &code); err != nil {
+		return err
+	}
+	if code == "" || code == "0x" || strings.Contains(code, "__") {
+		// Empty bytecode or bytecode with unlinked library references.
+		*b = nil
+		return nil
+	}
+	bin, err := hexutil.HexToBytes(code)
+	if err != nil {
+		return fmt.Errorf("abi: invalid bytecode: %w", err)
+	}
+	*b = bin
+	return nil
+}
+
+// isJSONObject returns true if the given JSON data is an object.
+func isJSONObject(data []byte) bool {
+	for _, c := range data {
+		switch c {
+		case ' ', '\t', '\n', '\r':
+			continue
+		case '{':
+			return true
+		default:
+			return false
+		}
+	}
+	return false
+}
+
 type jsonField struct {
 	Type            string         `json:"type"`
 	Name            string         `json:"name"`
@@ -408,7 +655,7 @@ func (a jsonParameter) toType(abi *ABI) (typ jsonABIType, err error) {
 			typ.typ = NewAliasType(intName, typ.typ)
 		}
 	default:
-		typ.typ = abi.Types[baseTyp]
+		typ.typ = abi.lookupType(baseTyp)
 		if typ.typ == nil {
 			return jsonABIType{}, fmt.Errorf("abi: unknown type %q", a.Type)
 		}
@@ -3,12 +3,14 @@ package abi
 import (
 	"errors"
 	"fmt"
+	"math/big"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
 	"github.com/defiweb/go-eth/hexutil"
+	"github.com/defiweb/go-eth/types"
 )
 
 func TestABI_LoadJSON(t *testing.T) {
@@ -47,6 +49,67 @@ func TestABI_LoadJSON(t *testing.T) {
 	assert.Equal(t, "uint256", abi.Types["CustomUint"].CanonicalType())
 }
 
+func TestABI_LoadJSON_Artifact(t *testing.T) {
+	tests := []struct {
+		path string
+	}{
+		{path: "testdata/foundry_artifact.json"},
+		{path: "testdata/hardhat_artifact.json"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			abi, err := LoadJSON(tt.path)
+			require.NoError(t, err)
+
+			require.NotNil(t, abi.Methods["foo"])
+			assert.Equal(t, "function foo(uint256 a) pure returns (uint256)", abi.Methods["foo"].String())
+			assert.Equal(t, hexutil.MustHexToBytes("0x6080604052"), abi.Bytecode)
+			assert.Equal(t, hexutil.MustHexToBytes("0x60806040"), abi.DeployedBytecode)
+		})
+	}
+}
+
+func TestABI_ParseJSON_Artifact(t *testing.T) {
+	tests := []struct {
+		name         string
+		json         string
+		wantBytecode []byte
+		wantErr      bool
+	}{
+		{
+			name:         "without bytecode",
+			json:         `{"abi": []}`,
+			wantBytecode: nil,
+		},
+		{
+			name:         "unlinked bytecode",
+			json:         `{"abi": [], "bytecode": "0x6080__$1234567890$__6040"}`,
+			wantBytecode: nil,
+		},
+		{
+			name:    "missing abi",
+			json:    `{"bytecode": "0x6080"}`,
+			wantErr: true,
+		},
+		{
+			name:    "invalid bytecode",
+			json:    `{"abi": [], "bytecode": "0xzz"}`,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			abi, err := ParseJSON([]byte(tt.json))
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantBytecode, abi.Bytecode)
+		})
+	}
+}
+
 func TestABI_ParseSignatures(t *testing.T) {
 	abi, err := ParseSignatures(
 		`uint8 Status`,
@@ -135,6 +198,105 @@ func TestContract_ToError(t *testing.T) {
 	})
 }
 
+func TestContract_DecodeLogs(t *testing.T) {
+	c := MustParseSignatures(
+		"event Transfer(address indexed from, address indexed to, uint256 value)",
+		"event Approval(address indexed owner, address indexed spender, uint256 value)",
+	)
+	var (
+		transferTopic = types.MustHashFromHex("0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef", types.PadNone)
+		addr2         = types.MustHashFromHex("0x2222222222222222222222222222222222222222", types.PadLeft)
+		addr3         = types.MustHashFromHex("0x3333333333333333333333333333333333333333", types.PadLeft)
+		word42        = types.MustHashFromHex("0x2a", types.PadLeft)
+		otherTopic    = types.MustHashFromHex("0x4444444444444444444444444444444444444444444444444444444444444444", types.PadNone)
+	)
+	logs := []types.Log{
+		{Topics: []types.Hash{transferTopic, addr3, addr2}, Data: word42.Bytes()},
+		{Topics: []types.Hash{otherTopic}},
+		{Topics: []types.Hash{transferTopic, addr3, addr2, word42}},
+		{},
+	}
+
+	res, err := c.DecodeLogs(logs)
+	require.NoError(t, err)
+	require.Len(t, res, 1)
+	assert.Same(t, c.Events["Transfer"], res[0].Event)
+	assert.Equal(t, logs[0], res[0].Log)
+	assert.Equal(t, types.MustAddressFromHex("0x3333333333333333333333333333333333333333"), res[0].Values["from"])
+	assert.Equal(t, big.NewInt(42), res[0].Values["value"])
+
+	// Malformed data of a matched event.
+	_, err = c.DecodeLogs([]types.Log{{Topics: []types.Hash{transferTopic, addr3, addr2}}})
+	assert.Error(t, err)
+}
+
+func TestContract_DecodeInput(t *testing.T) {
+	c, err := ParseSignatures(
+		"function foo(uint256 a, address b)",
+		"function bar(string s)",
+	)
+	require.NoError(t, err)
+
+	input := c.Methods["foo"].MustEncodeArgs(1, "0x1111111111111111111111111111111111111111")
+	m, args, err := c.DecodeInput(input)
+	require.NoError(t, err)
+	assert.Equal(t, "foo", m.Name())
+	assert.Equal(t, []any{big.NewInt(1), types.MustAddressFromHex("0x1111111111111111111111111111111111111111")}, args)
+
+	_, _, err = c.DecodeInput(hexutil.MustHexToBytes("0xaabbccdd"))
+	assert.Error(t, err)
+
+	_, _, err = c.DecodeInput(hexutil.MustHexToBytes("0xaabb"))
+	assert.Error(t, err)
+}
+
+func TestDecodeCalldataWith(t *testing.T) {
+	erc20 := MustParseSignatures(
+		"function transfer(address to, uint256 amount)",
+		"function approve(address spender, uint256 amount)",
+	)
+	other := MustParseSignatures("function bar(string s)")
+
+	input := erc20.Methods["approve"].MustEncodeArgs("0x1111111111111111111111111111111111111111", 2)
+	m, args, err := DecodeCalldataWith(input, []*Contract{other, nil, erc20})
+	require.NoError(t, err)
+	assert.Equal(t, "approve", m.Name())
+	assert.Equal(t, []any{types.MustAddressFromHex("0x1111111111111111111111111111111111111111"), big.NewInt(2)}, args)
+
+	// Matching selector with invalid arguments:
+	_, _, err = DecodeCalldataWith(input[:20], []*Contract{erc20})
+	assert.ErrorContains(t, err, "not enough data")
+
+	// Unknown selector:
+	_, _, err = DecodeCalldataWith(hexutil.MustHexToBytes("0xaabbccdd"), []*Contract{erc20, other})
+	assert.ErrorContains(t, err, "no method found")
+
+	// Too short:
+	_, _, err = DecodeCalldataWith(hexutil.MustHexToBytes("0xaabb"), []*Contract{erc20})
+	assert.Error(t, err)
+}
+
+func TestDecodeCalldataBySignature(t *testing.T) {
+	const sig = "transfer(address to, uint256 amount)"
+	input := MustParseMethod(sig).MustEncodeArgs("0x1111111111111111111111111111111111111111", 2)
+
+	args, err := DecodeCalldataBySignature(sig, input)
+	require.NoError(t, err)
+	assert.Equal(t, []any{types.MustAddressFromHex("0x1111111111111111111111111111111111111111"), big.NewInt(2)}, args)
+
+	// Selector mismatch:
+	_, err = DecodeCalldataBySignature("approve(address,uint256)", input)
+	assert.Error(t, err)
+
+	// Invalid signature:
+	_, err = DecodeCalldataBySignature("transfer(address", input)
+	assert.Error(t, err)
+
+	// Too short:
+	_, err = DecodeCalldataBySignature(sig, input[:2])
+	assert.Error(t, err)
+}
+
 func TestContract_HandleError(t *testing.T) {
 	c, err := ParseSignatures("error foo(uint256)")
 	require.NoError(t, err)
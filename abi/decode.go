@@ -32,6 +32,9 @@ func MustDecodeValues(t Type, abi []byte, vals ...any) {
 // DecodeValue decodes the given ABI-encoded data into the given value.
 // Value must be a pointer to a struct or a map.
 func (a *ABI) DecodeValue(t Type, abi []byte, val any) error {
+	if err := checkDataSize(t, abi); err != nil {
+		return err
+	}
 	v := t.Value()
 	if _, err := v.DecodeABI(BytesToWords(abi)); err != nil {
 		return err
@@ -56,6 +59,9 @@ func (a *ABI) DecodeValues(t Type, abi []byte, vals ...any) error {
 	if len(*v) != len(vals) {
 		return fmt.Errorf("abi: cannot decode tuple, expected %d values, got %d", len(*v), len(vals))
 	}
+	if err := checkDataSize(t, abi); err != nil {
+		return err
+	}
 	if _, err := v.DecodeABI(BytesToWords(abi)); err != nil {
 		return err
 	}
@@ -77,6 +83,70 @@ func (a *ABI) MustDecodeValues(t Type, abi []byte, vals ...any) {
 	}
 }
 
+// checkDataSize returns an error if the given ABI-encoded data is too short
+// to contain a value of the given type. Only the static part of the encoding
+// is checked, data of dynamic values may still be truncated.
+//
+// The most common case is an empty result of a call to an address that
+// has no code.
+func checkDataSize(t Type, abi []byte) error {
+	n := 0
+	if tt, ok := t.(*TupleType); ok {
+		// Dynamic elements of the top-level tuple take a single word for
+		// the offset, so the size of the head is the sum of the elements.
+		for _, elem := range tt.Elements() {
+			n += headWords(elem.Type.Value())
+		}
+	} else {
+		n = headWords(t.Value())
+	}
+	if n *= WordLength; len(abi) < n {
+		return fmt.Errorf("abi: not enough data to decode: got %d bytes, need at least %d", len(abi), n)
+	}
+	return nil
+}
+
+// valueToAny converts the given value into a structure that consists of
+// maps, slices and basic Go types. Tuples are converted to map[string]any,
+// arrays to []any and other values are mapped to their default Go types.
+func valueToAny(m Mapper, v Value) (any, error) {
+	switch v := v.(type) {
+	case *TupleValue:
+		res := make(map[string]any, len(*v))
+		for _, elem := range *v {
+			e, err := valueToAny(m, elem.Value)
+			if err != nil {
+				return nil, err
+			}
+			res[elem.Name] = e
+		}
+		return res, nil
+	case *ArrayValue:
+		return valuesToAny(m, v.Elems)
+	case *FixedArrayValue:
+		return valuesToAny(m, *v)
+	default:
+		var res any
+		if err := m.Map(v, &res); err != nil {
+			return nil, err
+		}
+		return res, nil
+	}
+}
+
+// valuesToAny converts the given values using valueToAny.
+func valuesToAny(m Mapper, vs []Value) ([]any, error) {
+	res := make([]any, len(vs))
+	for i, v := range vs {
+		e, err := valueToAny(m, v)
+		if err != nil {
+			return nil, err
+		}
+		res[i] = e
+	}
+	return res, nil
+}
+
 // decodeTuple decodes a tuple from the given words and stores the result in the
 // given tuple. The tuple must contain the correct number of elements.
 func decodeTuple(t *[]Value, w Words) (int, error) {
@@ -85,10 +155,10 @@ func decodeTuple(t *[]Value, w Words) (int, error) {
 		wordsRead int
 	)
 	for _, e := range *t {
-		if wordIdx >= len(w) {
-			return 0, fmt.Errorf("abi: cannot decode tuple, unexpected end of data")
-		}
 		if e.IsDynamic() {
+			if wordIdx >= len(w) {
+				return 0, fmt.Errorf("abi: cannot decode tuple, unexpected end of data")
+			}
 			offset, err := readInt(&w[wordIdx])
 			if err != nil {
 				return 0, fmt.Errorf("abi: cannot decode tuple, invalid offset: %v", err)
@@ -132,29 +202,38 @@ func decodeArray(a *[]Value, w Words, t Type) (int, error) {
 	if err != nil {
 		return 0, err
 	}
-	if size+1 > len(w) {
+	// Elements of zero-width types, such as empty tuples, are counted as one
+	// word. Otherwise, the size would not be limited by the data length and
+	// could be used to allocate an arbitrarily large array. As a result,
+	// only empty arrays of such types can be decoded. Solidity does not
+	// allow empty structs nor zero-length arrays, so they do not occur in
+	// practice.
+	elemWords := headWords(t.Value())
+	if elemWords < 1 {
+		elemWords = 1
+	}
+	if size*elemWords+1 > len(w) {
 		return 0, fmt.Errorf("abi: cannot decode array, size exceeds data length")
 	}
 	*a = make([]Value, size)
 	for i := 0; i < size; i++ {
 		(*a)[i] = t.Value()
 	}
-	if _, err := decodeTuple(a, w[1:]); err != nil {
+	n, err := decodeTuple(a, w[1:])
+	if err != nil {
 		return 0, err
 	}
-	return size + 1, nil
+	return n + 1, nil
 }
 
 // decodeFixedArray decodes a fixed array from the given words into the values
 // in the given array.
 func decodeFixedArray(a *[]Value, w Words) (int, error) {
-	if len(w) == 0 {
-		return 0, fmt.Errorf("abi: cannot decode array[%d] from empty data", len(*a))
-	}
-	if _, err := decodeTuple(a, w); err != nil {
+	n, err := decodeTuple(a, w)
+	if err != nil {
 		return 0, err
 	}
-	return len(*a), nil
+	return n, nil
 }
 
 // decodeBytes decodes a dynamic byte array from the given words and stores the
@@ -172,7 +251,7 @@ func decodeBytes(b *[]byte, w Words) (int, error) {
 		return 0, fmt.Errorf("abi: cannot decode bytes, size exceeds data length")
 	}
 	*b = w[1 : l+1].Bytes()[0:size]
-	return size + 1, nil
+	return l + 1, nil
 }
 
 // decodeFixedBytes decodes a fixed byte of the given size from the given words
@@ -246,11 +325,34 @@ func decodeAddress(v *types.Address, w Words) (int, error) {
 	return 1, nil
 }
 
-// readInt reads an integer from the given word.
+// headWords returns the number of words the given value occupies in the
+// head of a tuple. Dynamic values occupy a single offset word, while static
+// values occupy as many words as their encoding, which may be zero for empty
+// tuples.
+func headWords(v Value) int {
+	if v.IsDynamic() {
+		return 1
+	}
+	w, err := v.EncodeABI()
+	if err != nil {
+		return 1
+	}
+	return len(w)
+}
+
+// readInt reads a non-negative integer, such as an offset or a length, from
+// the given word.
 func readInt(w *Word) (int, error) {
 	i32 := newIntX(32)
 	if err := i32.SetBytes(w.Bytes()); err != nil {
 		return 0, err
 	}
-	return i32.Int()
+	i, err := i32.Int()
+	if err != nil {
+		return 0, err
+	}
+	if i < 0 {
+		return 0, fmt.Errorf("abi: negative offset or length: %d", i)
+	}
+	return i, nil
 }
@@ -198,9 +198,9 @@ func encodeFixedBytes(b []byte, size int) (Words, error) {
 
 // encodeInt encodes an integer.
 //
-// The integer is encoded as two's complement integer. If the integer cannot
-// be represented in number of bits specified by the size argument, an error
-// is returned.
+// The integer is encoded as two's complement integer, sign-extended to the
+// full word. If the integer cannot be represented in number of bits specified
+// by the size argument, an error is returned.
 func encodeInt(v *big.Int, size int) (Words, error) {
 	w := Word{}
 	x := newIntX(size)
@@ -210,6 +210,11 @@ func encodeInt(v *big.Int, size int) (Words, error) {
 	if err := w.SetBytesPadLeft(x.Bytes()); err != nil {
 		return nil, err
 	}
+	if v.Sign() < 0 {
+		for i := 0; i < WordLength-size/8; i++ {
+			w[i] = 0xff
+		}
+	}
 	return Words{w}, nil
 }
 
@@ -3,6 +3,7 @@ package abi
 import (
 	"errors"
 	"fmt"
+	"sync"
 
 	"github.com/defiweb/go-eth/crypto"
 )
@@ -18,6 +19,72 @@ func (e CustomError) Error() string {
 	return fmt.Sprintf("error: %s", e.Type.Name())
 }
 
+// errorRegistry contains errors registered using RegisterError.
+var errorRegistry = struct {
+	mu     sync.RWMutex
+	errors map[FourBytes]*Error
+}{errors: make(map[FourBytes]*Error)}
+
+// RegisterError registers the given error globally, so it can be recognized
+// by the DecodeError function without knowing which contract returned it.
+// If an error with the same selector is already registered, it will be
+// overwritten.
+//
+// It is safe to call RegisterError concurrently.
+func RegisterError(err *Error) {
+	errorRegistry.mu.Lock()
+	defer errorRegistry.mu.Unlock()
+	errorRegistry.errors[err.FourBytes()] = err
+}
+
+// DecodeError decodes the error data returned by a contract call. The error
+// is recognized by its selector, using the built-in Error(string) and
+// Panic(uint256) errors and errors registered using RegisterError.
+//
+// It returns the error name and decoded arguments. Tuples are decoded into
+// map[string]any and arrays into []any.
+func DecodeError(data []byte) (name string, values []any, err error) {
+	if len(data) < 4 {
+		return "", nil, errors.New("abi: error data too short")
+	}
+	var sel FourBytes
+	copy(sel[:], data[:4])
+	e := lookupError(sel)
+	if e == nil {
+		return "", nil, fmt.Errorf("abi: unknown error selector %s", sel.Hex())
+	}
+	if err := checkDataSize(e.inputs, data[4:]); err != nil {
+		return "", nil, err
+	}
+	v := e.inputs.Value().(*TupleValue)
+	if _, err := v.DecodeABI(BytesToWords(data[4:])); err != nil {
+		return "", nil, err
+	}
+	elems := make([]Value, len(*v))
+	for i, elem := range *v {
+		elems[i] = elem.Value
+	}
+	values, err = valuesToAny(e.abi.Mapper, elems)
+	if err != nil {
+		return "", nil, err
+	}
+	return e.name, values, nil
+}
+
+// lookupError returns the built-in or registered error with the given
+// selector or nil if the error is not known.
+func lookupError(sel FourBytes) *Error {
+	switch sel {
+	case Revert.FourBytes():
+		return Revert
+	case Panic.FourBytes():
+		return Panic
+	}
+	errorRegistry.mu.RLock()
+	defer errorRegistry.mu.RUnlock()
+	return errorRegistry.errors[sel]
+}
+
 // Error represents an error in an ABI. The error can be used to decode errors
 // returned by a contract call.
 type Error struct {
@@ -3,12 +3,14 @@ package abi
 import (
 	"errors"
 	"fmt"
+	"math/big"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
 	"github.com/defiweb/go-eth/hexutil"
+	"github.com/defiweb/go-eth/types"
 )
 
 type mockError struct {
@@ -112,3 +114,49 @@ func TestError_HandleError(t *testing.T) {
 		require.Equal(t, errors.New("not a RPC call error"), e.HandleError(errors.New("not a RPC call error")))
 	})
 }
+
+func TestDecodeError(t *testing.T) {
+	custom := MustParseError("InsufficientBalance(uint256 available, (address owner, uint256[] amounts) info)")
+	RegisterError(custom)
+
+	t.Run("revert", func(t *testing.T) {
+		data := append(Revert.FourBytes().Bytes(), MustEncodeValues(Revert.Inputs(), "foo")...)
+		name, values, err := DecodeError(data)
+		require.NoError(t, err)
+		assert.Equal(t, "Error", name)
+		assert.Equal(t, []any{"foo"}, values)
+	})
+	t.Run("panic", func(t *testing.T) {
+		data := append(Panic.FourBytes().Bytes(), MustEncodeValues(Panic.Inputs(), big.NewInt(0x11))...)
+		name, values, err := DecodeError(data)
+		require.NoError(t, err)
+		assert.Equal(t, "Panic", name)
+		require.Len(t, values, 1)
+		assert.Equal(t, big.NewInt(0x11), values[0])
+	})
+	t.Run("registered", func(t *testing.T) {
+		owner := types.MustAddressFromHex("0x1111111111111111111111111111111111111111")
+		data := append(custom.FourBytes().Bytes(), MustEncodeValues(
+			custom.Inputs(),
+			big.NewInt(1),
+			map[string]any{"owner": owner, "amounts": []*big.Int{big.NewInt(2), big.NewInt(3)}},
+		)...)
+		name, values, err := DecodeError(data)
+		require.NoError(t, err)
+		assert.Equal(t, "InsufficientBalance", name)
+		require.Len(t, values, 2)
+		assert.Equal(t, big.NewInt(1), values[0])
+		assert.Equal(t, map[string]any{
+			"owner":   owner,
+			"amounts": []any{big.NewInt(2), big.NewInt(3)},
+		}, values[1])
+	})
+	t.Run("unknown", func(t *testing.T) {
+		_, _, err := DecodeError(hexutil.MustHexToBytes("0x01020304"))
+		assert.Error(t, err)
+	})
+	t.Run("too-short", func(t *testing.T) {
+		_, _, err := DecodeError([]byte{1, 2})
+		assert.Error(t, err)
+	})
+}
@@ -92,6 +92,12 @@ func (e *Event) Inputs() *EventTupleType {
 	return e.inputs
 }
 
+// IsAnonymous returns true if the event is anonymous. Anonymous events do
+// not have the topic0 in their logs.
+func (e *Event) IsAnonymous() bool {
+	return e.anonymous
+}
+
 // Topic0 returns the first topic of the event, that is, the Keccak256 hash of
 // the event signature.
 func (e *Event) Topic0() types.Hash {
@@ -107,19 +113,14 @@ func (e *Event) Signature() string {
 // DecodeValue decodes the event into a map or structure. If a structure is
 // given, it must have fields with the same names as the event arguments.
 func (e *Event) DecodeValue(topics []types.Hash, data []byte, val any) error {
-	if e.anonymous {
-		return e.abi.DecodeValue(e.inputs, data, val)
-	}
-	if len(topics) != e.inputs.IndexedSize()+1 {
-		return fmt.Errorf("abi: wrong number of topics for event %s", e.name)
-	}
-	if topics[0] != e.topic0 {
-		return fmt.Errorf("abi: topic0 mismatch for event %s", e.name)
+	topics, err := e.indexedTopics(topics)
+	if err != nil {
+		return err
 	}
 	// The anymapper package does not zero out values before decoding into
 	// it, therefore we can decode topics and data into the same value.
-	if len(topics) > 1 {
-		if err := e.abi.DecodeValue(e.inputs.TopicsTuple(), hashSliceToBytes(topics[1:]), val); err != nil {
+	if len(topics) > 0 {
+		if err := e.abi.DecodeValue(e.inputs.TopicsTuple(), hashSliceToBytes(topics), val); err != nil {
 			return err
 		}
 	}
@@ -131,6 +132,56 @@ func (e *Event) DecodeValue(topics []types.Hash, data []byte, val any) error {
 	return nil
 }
 
+// FilterTopics returns topics that can be used in a log filter query to
+// match the event. The indexed arguments are used to filter by the indexed
+// event arguments, in the order they appear in the event. A nil argument
+// matches any value.
+//
+// Value types are encoded as a single ABI word, while string and bytes
+// arguments are hashed as defined in the Solidity ABI specification. For
+// other dynamic types, the topic hash must be given as types.Hash.
+//
+// For anonymous events, topic0 is omitted.
+func (e *Event) FilterTopics(indexed ...any) ([][]types.Hash, error) {
+	if len(indexed) > e.inputs.IndexedSize() {
+		return nil, fmt.Errorf("abi: too many indexed arguments for event %s", e.name)
+	}
+	var topics [][]types.Hash
+	if !e.anonymous {
+		topics = append(topics, []types.Hash{e.topic0})
+	}
+	i := 0
+	for _, elem := range e.inputs.elems {
+		if !elem.Indexed {
+			continue
+		}
+		if i >= len(indexed) {
+			break
+		}
+		arg := indexed[i]
+		i++
+		if arg == nil {
+			topics = append(topics, nil)
+			continue
+		}
+		topic, err := e.encodeTopic(elem.Type, arg)
+		if err != nil {
+			return nil, fmt.Errorf("abi: cannot encode topic %d for event %s: %w", i, e.name, err)
+		}
+		topics = append(topics, []types.Hash{topic})
+	}
+	return topics, nil
+}
+
+// MustFilterTopics is like FilterTopics but panics on error.
+func (e *Event) MustFilterTopics(indexed ...any) [][]types.Hash {
+	topics, err := e.FilterTopics(indexed...)
+	if err != nil {
+		panic(err)
+	}
+	return topics
+}
+
 // MustDecodeValue is like DecodeValue but panics on error.
 func (e *Event) MustDecodeValue(topics []types.Hash, data []byte, val any) {
 	err := e.DecodeValue(topics, data, val)
@@ -142,14 +193,9 @@ func (e *Event) MustDecodeValue(topics []types.Hash, data []byte, val any) {
 // DecodeValues decodes the event into a map or structure. If a structure is
 // given, it must have fields with the same names as the event arguments.
 func (e *Event) DecodeValues(topics []types.Hash, data []byte, vals ...any) error {
-	if e.anonymous {
-		return e.abi.DecodeValues(e.inputs, data, vals...)
-	}
-	if len(topics) != e.inputs.IndexedSize()+1 {
-		return fmt.Errorf("abi: wrong number of topics for event %s", e.name)
-	}
-	if topics[0] != e.topic0 {
-		return fmt.Errorf("abi: topic0 mismatch for event %s", e.name)
+	topics, err := e.indexedTopics(topics)
+	if err != nil {
+		return err
 	}
 	indexedVals := make([]any, 0, e.inputs.IndexedSize())
 	dataVals := make([]any, 0, e.inputs.DataSize())
@@ -165,8 +211,8 @@ func (e *Event) DecodeValues(topics []types.Hash, data []byte, vals ...any) erro
 	}
 	// The anymapper package does not zero out values before decoding into
 	// it, therefore we can decode topics and data into the same value.
-	if len(topics) > 1 {
-		if err := e.abi.DecodeValues(e.inputs.TopicsTuple(), hashSliceToBytes(topics[1:]), indexedVals...); err != nil {
+	if len(topics) > 0 {
+		if err := e.abi.DecodeValues(e.inputs.TopicsTuple(), hashSliceToBytes(topics), indexedVals...); err != nil {
 			return err
 		}
 	}
@@ -186,6 +232,72 @@ func (e *Event) MustDecodeValues(topics []types.Hash, data []byte, vals ...any)
 	}
 }
 
+// DecodeLogToMap decodes all event arguments, both indexed and non-indexed,
+// into a map keyed by the argument names. Unnamed indexed arguments are keyed
+// by "topicN" and unnamed non-indexed arguments by "dataN".
+//
+// Indexed arguments of dynamic types are stored only as a hash in the log
+// topics, so they cannot be decoded and are returned as types.Hash. Tuples
+// are decoded into map[string]any and arrays into []any. It is useful for
+// generic event indexers that do not know the shape of the events in
+// advance.
+func (e *Event) DecodeLogToMap(log types.Log) (map[string]any, error) {
+	topics, err := e.indexedTopics(log.Topics)
+	if err != nil {
+		return nil, err
+	}
+	res := make(map[string]any, e.inputs.Size())
+	if len(topics) > 0 {
+		tt := e.inputs.TopicsTuple()
+		tv := tt.Value().(*TupleValue)
+		if _, err := tv.DecodeABI(BytesToWords(hashSliceToBytes(topics))); err != nil {
+			return nil, err
+		}
+		i := 0
+		for _, elem := range e.inputs.Elements() {
+			if !elem.Indexed {
+				continue
+			}
+			name := (*tv)[i].Name
+			if elem.Type.IsDynamic() {
+				res[name] = topics[i]
+			} else {
+				v, err := valueToAny(e.abi.Mapper, (*tv)[i].Value)
+				if err != nil {
+					return nil, err
+				}
+				res[name] = v
+			}
+			i++
+		}
+	}
+	dt := e.inputs.DataTuple()
+	if err := checkDataSize(dt, log.Data); err != nil {
+		return nil, err
+	}
+	dv := dt.Value().(*TupleValue)
+	if _, err := dv.DecodeABI(BytesToWords(log.Data)); err != nil {
+		return nil, err
+	}
+	for _, elem := range *dv {
+		v, err := valueToAny(e.abi.Mapper, elem.Value)
+		if err != nil {
+			return nil, err
+		}
+		res[elem.Name] = v
+	}
+	return res, nil
+}
+
+// MustDecodeLogToMap is like DecodeLogToMap but panics on error.
+func (e *Event) MustDecodeLogToMap(log types.Log) map[string]any {
+	res, err := e.DecodeLogToMap(log)
+	if err != nil {
+		panic(err)
+	}
+	return res
+}
+
 // String returns the human-readable signature of the event.
 func (e *Event) String() string {
 	var buf strings.Builder
@@ -198,6 +310,55 @@ func (e *Event) String() string {
 	return buf.String()
 }
 
+// indexedTopics verifies the topic0, the number of topics and the padding
+// of indexed value types, and returns the topics of the indexed arguments.
+// Anonymous events do not have the topic0, so all topics are returned.
+func (e *Event) indexedTopics(topics []types.Hash) ([]types.Hash, error) {
+	if !e.anonymous {
+		if len(topics) == 0 || topics[0] != e.topic0 {
+			return nil, fmt.Errorf("abi: topic0 mismatch for event %s", e.name)
+		}
+		topics = topics[1:]
+	}
+	if len(topics) != e.inputs.IndexedSize() {
+		return nil, fmt.Errorf("abi: wrong number of topics for event %s", e.name)
+	}
+	for i, elem := range e.inputs.TopicsTuple().Elements() {
+		if err := checkTopicPadding(elem.Type, topics[i]); err != nil {
+			return nil, fmt.Errorf("abi: invalid topic %d for event %s: %w", i+1, e.name, err)
+		}
+	}
+	return topics, nil
+}
+
+// encodeTopic encodes a single indexed argument as a topic.
+func (e *Event) encodeTopic(t Type, arg any) (types.Hash, error) {
+	if h, ok := arg.(types.Hash); ok {
+		return h, nil
+	}
+	v := t.Value()
+	if err := e.abi.Mapper.Map(arg, v); err != nil {
+		return types.Hash{}, err
+	}
+	switch v := v.(type) {
+	case *StringValue:
+		return crypto.Keccak256([]byte(*v)), nil
+	case *BytesValue:
+		return crypto.Keccak256(*v), nil
+	}
+	if v.IsDynamic() {
+		return types.Hash{}, fmt.Errorf("dynamic type %s must be given as a hash", t.CanonicalType())
+	}
+	words, err := v.EncodeABI()
+	if err != nil {
+		return types.Hash{}, err
+	}
+	if len(words) == 1 {
+		return types.Hash(words[0]), nil
+	}
+	return crypto.Keccak256(words.Bytes()), nil
+}
+
 func (e *Event) calculateTopic0() {
 	e.topic0 = crypto.Keccak256([]byte(e.signature))
 }
@@ -213,3 +374,48 @@ func hashSliceToBytes(hashes []types.Hash) []byte {
 	}
 	return buf
 }
+
+// checkTopicPadding verifies that the bytes of the topic that are not used
+// by the value of the given type are properly padded. Node software and
+// indexers treat topics as opaque hashes, so unlike the data part of a log,
+// a topic with dirty padding would otherwise decode into a value that does
+// not match the topic used in log filters.
+func checkTopicPadding(t Type, topic types.Hash) error {
+	switch t := t.(type) {
+	case *AliasType:
+		return checkTopicPadding(t.Type(), topic)
+	case *UintType:
+		if !isZeroBytes(topic[:WordLength-t.size/8]) {
+			return fmt.Errorf("value does not fit into uint%d", t.size)
+		}
+	case *IntType:
+		pad := byte(0)
+		if topic[WordLength-t.size/8]&0x80 != 0 {
+			pad = 0xff
+		}
+		for _, b := range topic[:WordLength-t.size/8] {
+			if b != pad {
+				return fmt.Errorf("value does not fit into int%d", t.size)
+			}
+		}
+	case *AddressType:
+		if !isZeroBytes(topic[:WordLength-types.AddressLength]) {
+			return fmt.Errorf("non-zero padding of address")
+		}
+	case *FixedBytesType:
+		if !isZeroBytes(topic[t.size:]) {
+			return fmt.Errorf("non-zero padding of bytes%d", t.size)
+		}
+	}
+	return nil
+}
+
+// isZeroBytes returns true if all bytes in b are zero.
+func isZeroBytes(b []byte) bool {
+	for _, c := range b {
+		if c != 0 {
+			return false
+		}
+	}
+	return true
+}
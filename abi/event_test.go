@@ -8,6 +8,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/defiweb/go-eth/crypto"
 	"github.com/defiweb/go-eth/hexutil"
 	"github.com/defiweb/go-eth/types"
 )
@@ -221,3 +222,247 @@ func TestEvent_DecodeValues(t *testing.T) {
 		})
 	}
 }
+
+func TestEvent_DecodeValues_IndexedTopics(t *testing.T) {
+	tests := []struct {
+		signature string
+		topic     string
+		arg       any
+		expected  any
+		wantErr   bool
+	}{
+		{
+			signature: "foo(address indexed a)",
+			topic:     "0x0000000000000000000000001f7acda376ef37ec371235a094113df9cb4efee1",
+			arg:       &types.Address{},
+			expected:  types.MustAddressFromHexPtr("0x1f7acda376ef37ec371235a094113df9cb4efee1"),
+		},
+		{
+			signature: "foo(uint8 indexed a)",
+			topic:     "0x00000000000000000000000000000000000000000000000000000000000000ff",
+			arg:       new(uint8),
+			expected:  func() *uint8 { v := uint8(255); return &v }(),
+		},
+		{
+			signature: "foo(uint256 indexed a)",
+			topic:     "0xffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff",
+			arg:       &big.Int{},
+			expected:  MaxUint[256],
+		},
+		{
+			signature: "foo(int32 indexed a)",
+			topic:     "0xfffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff6",
+			arg:       new(int32),
+			expected:  func() *int32 { v := int32(-10); return &v }(),
+		},
+		{
+			signature: "foo(bytes4 indexed a)",
+			topic:     "0xdeadbeef00000000000000000000000000000000000000000000000000000000",
+			arg:       &[4]byte{},
+			expected:  &[4]byte{0xde, 0xad, 0xbe, 0xef},
+		},
+		{
+			signature: "foo(bytes32 indexed a)",
+			topic:     "0x63cff3f05ab6a55e7e49095371098bf6455d27c6ed5b6e3c3178c661a821f729",
+			arg:       &types.Hash{},
+			expected:  types.MustHashFromHexPtr("0x63cff3f05ab6a55e7e49095371098bf6455d27c6ed5b6e3c3178c661a821f729", types.PadNone),
+		},
+		{
+			signature: "foo(bool indexed a)",
+			topic:     "0x0000000000000000000000000000000000000000000000000000000000000001",
+			arg:       new(bool),
+			expected:  func() *bool { v := true; return &v }(),
+		},
+		{
+			signature: "foo(string indexed a)",
+			topic:     "0x41b1a0649752af1b28b3dc29a1556eee781e4a4c3a1f7f53f90fa834de098c4d",
+			arg:       &types.Hash{},
+			expected:  types.MustHashFromHexPtr("0x41b1a0649752af1b28b3dc29a1556eee781e4a4c3a1f7f53f90fa834de098c4d", types.PadNone),
+		},
+		{
+			// Value does not fit into uint8.
+			signature: "foo(uint8 indexed a)",
+			topic:     "0x0000000000000000000000000000000000000000000000000000000000000100",
+			arg:       new(uint8),
+			wantErr:   true,
+		},
+		{
+			// Dirty higher bits of an address.
+			signature: "foo(address indexed a)",
+			topic:     "0xff00000000000000000000000000000000000000000000000000000000000000",
+			arg:       &types.Address{},
+			wantErr:   true,
+		},
+		{
+			// Invalid sign extension of int32.
+			signature: "foo(int32 indexed a)",
+			topic:     "0x00000000000000000000000000000000000000000000000000000000fffffff6",
+			arg:       new(int32),
+			wantErr:   true,
+		},
+		{
+			// Dirty lower bits of bytes4.
+			signature: "foo(bytes4 indexed a)",
+			topic:     "0xdeadbeef00000000000000000000000000000000000000000000000000000001",
+			arg:       &[4]byte{},
+			wantErr:   true,
+		},
+	}
+	for n, tt := range tests {
+		t.Run(fmt.Sprintf("case-%d", n+1), func(t *testing.T) {
+			e, err := ParseEvent(tt.signature)
+			require.NoError(t, err)
+			topics := []types.Hash{e.Topic0(), types.MustHashFromHex(tt.topic, types.PadLeft)}
+			err = e.DecodeValues(topics, nil, tt.arg)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, tt.arg)
+		})
+	}
+}
+
+func TestEvent_DecodeValues_DataPadding(t *testing.T) {
+	// Padding is only verified for topics, values in the data part are
+	// decoded as before.
+	e := MustParseEvent("event Foo(uint8 a)")
+	data := hexutil.MustHexToBytes("0x00000000000000000000000000000000000000000000000000000000000001ff")
+
+	var a uint8
+	require.NoError(t, e.DecodeValues([]types.Hash{e.Topic0()}, data, &a))
+	assert.Equal(t, uint8(255), a)
+}
+
+func TestEvent_DecodeValues_Anonymous(t *testing.T) {
+	e := MustParseEvent("event Foo(address indexed a, uint256 b) anonymous")
+	topics := []types.Hash{types.MustHashFromHex("0x0000000000000000000000001f7acda376ef37ec371235a094113df9cb4efee1", types.PadNone)}
+	data := hexutil.MustHexToBytes("0x000000000000000000000000000000000000000000000000000000000000002a")
+
+	var a types.Address
+	var b *big.Int
+	require.NoError(t, e.DecodeValues(topics, data, &a, &b))
+	assert.Equal(t, types.MustAddressFromHex("0x1f7acda376ef37ec371235a094113df9cb4efee1"), a)
+	assert.Equal(t, big.NewInt(42), b)
+
+	var m map[string]any
+	require.NoError(t, e.DecodeValue(topics, data, &m))
+	assert.Equal(t, types.MustAddressFromHex("0x1f7acda376ef37ec371235a094113df9cb4efee1"), m["a"])
+
+	assert.Error(t, e.DecodeValues(nil, data, &a, &b))
+}
+
+func TestEvent_DecodeLogToMap(t *testing.T) {
+	e := MustParseEvent("Foo(address indexed from, string indexed tag, uint256, (bool x, string y) data)")
+	addr := types.MustAddressFromHex("0x1111111111111111111111111111111111111111")
+	data, err := EncodeValues(e.Inputs().DataTuple(), 42, map[string]any{"x": true, "y": "a"})
+	require.NoError(t, err)
+	log := types.Log{
+		Topics: []types.Hash{
+			e.Topic0(),
+			types.MustHashFromBytes(addr.Bytes(), types.PadLeft),
+			crypto.Keccak256([]byte("abc")),
+		},
+		Data: data,
+	}
+
+	res, err := e.DecodeLogToMap(log)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{
+		"from":  addr,
+		"tag":   crypto.Keccak256([]byte("abc")),
+		"data0": big.NewInt(42),
+		"data":  map[string]any{"x": true, "y": "a"},
+	}, res)
+
+	// Anonymous event:
+	anon := MustParseEvent("event Bar(uint256 indexed a, uint256 b) anonymous")
+	res, err = anon.DecodeLogToMap(types.Log{
+		Topics: []types.Hash{types.MustHashFromHex("0x01", types.PadLeft)},
+		Data:   types.MustHashFromHex("0x02", types.PadLeft).Bytes(),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"a": big.NewInt(1), "b": big.NewInt(2)}, res)
+
+	// Invalid logs:
+	_, err = e.DecodeLogToMap(types.Log{Topics: log.Topics[:2], Data: data})
+	assert.Error(t, err)
+	_, err = e.DecodeLogToMap(types.Log{Topics: []types.Hash{anon.Topic0(), log.Topics[1], log.Topics[2]}, Data: data})
+	assert.Error(t, err)
+	_, err = e.DecodeLogToMap(types.Log{Topics: log.Topics, Data: data[:32]})
+	assert.Error(t, err)
+}
+
+func TestEvent_FilterTopics(t *testing.T) {
+	transfer := MustParseEvent("Transfer(address indexed from, address indexed to, uint256 value)")
+	foo := MustParseEvent("Foo(string indexed a, uint8 indexed b, uint256[] indexed c)")
+	bar := MustParseEvent("event Bar(uint256 indexed a) anonymous")
+	addr := types.MustAddressFromHex("0x1111111111111111111111111111111111111111")
+	tests := []struct {
+		event    *Event
+		args     []any
+		expected [][]types.Hash
+		wantErr  bool
+	}{
+		{
+			event:    transfer,
+			args:     nil,
+			expected: [][]types.Hash{{transfer.Topic0()}},
+		},
+		{
+			event: transfer,
+			args:  []any{nil, addr},
+			expected: [][]types.Hash{
+				{transfer.Topic0()},
+				nil,
+				{types.MustHashFromHex("0x0000000000000000000000001111111111111111111111111111111111111111", types.PadNone)},
+			},
+		},
+		{
+			event: foo,
+			args:  []any{"abc", 1},
+			expected: [][]types.Hash{
+				{foo.Topic0()},
+				{crypto.Keccak256([]byte("abc"))},
+				{types.MustHashFromHex("0x0000000000000000000000000000000000000000000000000000000000000001", types.PadNone)},
+			},
+		},
+		{
+			event: foo,
+			args:  []any{nil, nil, crypto.Keccak256([]byte("c"))},
+			expected: [][]types.Hash{
+				{foo.Topic0()},
+				nil,
+				nil,
+				{crypto.Keccak256([]byte("c"))},
+			},
+		},
+		{
+			event:    bar,
+			args:     []any{big.NewInt(2)},
+			expected: [][]types.Hash{{types.MustHashFromHex("0x0000000000000000000000000000000000000000000000000000000000000002", types.PadNone)}},
+		},
+		{
+			event:   foo,
+			args:    []any{nil, nil, []int{1}},
+			wantErr: true,
+		},
+		{
+			event:   transfer,
+			args:    []any{addr, addr, addr},
+			wantErr: true,
+		},
+	}
+	for n, tt := range tests {
+		t.Run(fmt.Sprintf("case-%d", n+1), func(t *testing.T) {
+			topics, err := tt.event.FilterTopics(tt.args...)
+			if tt.wantErr {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tt.expected, topics)
+			}
+		})
+	}
+}
@@ -210,6 +210,36 @@ func (m *Method) MustEncodeArgs(args ...any) []byte {
 	return encoded
 }
 
+// EncodeArgsFromStrings encodes arguments for a method call using a list of
+// arguments given as strings. Each string is converted into the type of the
+// corresponding method argument as described in ParseStringArg.
+//
+// The return value is a ABI-encoded data prefixed with the method selector.
+func (m *Method) EncodeArgsFromStrings(args ...string) ([]byte, error) {
+	elems := m.inputs.Elements()
+	if len(args) != len(elems) {
+		return nil, fmt.Errorf("abi: cannot encode arguments for method %s, expected %d arguments, got %d", m.name, len(elems), len(args))
+	}
+	vals := make([]any, len(args))
+	for i, arg := range args {
+		v, err := ParseStringArg(elems[i].Type, arg)
+		if err != nil {
+			return nil, err
+		}
+		vals[i] = v
+	}
+	return m.EncodeArgs(vals...)
+}
+
+// MustEncodeArgsFromStrings is like EncodeArgsFromStrings but panics on error.
+func (m *Method) MustEncodeArgsFromStrings(args ...string) []byte {
+	encoded, err := m.EncodeArgsFromStrings(args...)
+	if err != nil {
+		panic(err)
+	}
+	return encoded
+}
+
 // DecodeArg decodes an ABI-encoded data into a provided map or struct.
 //
 // Provided struct or map must have fields that match the names of the method's
@@ -217,6 +247,9 @@ func (m *Method) MustEncodeArgs(args ...any) []byte {
 //
 // Provided data must be prefixed with the method selector.
 func (m *Method) DecodeArg(data []byte, arg any) error {
+	if len(data) < 4 {
+		return fmt.Errorf("abi: not enough data to decode: got %d bytes, need at least 4", len(data))
+	}
 	if !m.fourBytes.Match(data[:4]) {
 		return fmt.Errorf(
 			"abi: calldata signature 0x%x do not match method signature %s",
@@ -238,6 +271,9 @@ func (m *Method) MustDecodeArg(data []byte, arg any) {
 //
 // Provided data must be prefixed with the method selector.
 func (m *Method) DecodeArgs(data []byte, args ...any) error {
+	if len(data) < 4 {
+		return fmt.Errorf("abi: not enough data to decode: got %d bytes, need at least 4", len(data))
+	}
 	if !m.fourBytes.Match(data[:4]) {
 		return fmt.Errorf(
 			"abi: calldata signature 0x%x do not match method signature %s",
@@ -272,6 +308,13 @@ func (m *Method) MustDecodeValue(data []byte, val any) {
 
 // DecodeValues decodes an ABI-encoded data into a provided list of return
 // variables.
+//
+// Arrays are decoded into slices of the element's Go type, so a method that
+// returns a single array, e.g. "(address[])" or "((int24, uint128)[])", can
+// be decoded directly into a *[]types.Address or a *[]SomeStruct.
+//
+// If the data is too short to contain the return values, for example, when
+// a call to an address without code returns empty data, an error is returned.
 func (m *Method) DecodeValues(data []byte, vals ...any) error {
 	return m.abi.DecodeValues(m.outputs, data, vals...)
 }
@@ -283,6 +326,36 @@ func (m *Method) MustDecodeValues(data []byte, vals ...any) {
 	}
 }
 
+// DecodeValuesToMap decodes an ABI-encoded data into a map of return values
+// keyed by their names. Unnamed return values are keyed by "argN", where N is
+// the index of the value.
+//
+// Nested tuples are decoded into nested maps, and arrays into []any slices.
+// It is useful for tools that do not know the shape of the data in advance.
+func (m *Method) DecodeValuesToMap(data []byte) (map[string]any, error) {
+	if err := checkDataSize(m.outputs, data); err != nil {
+		return nil, err
+	}
+	v := m.outputs.Value()
+	if _, err := v.DecodeABI(BytesToWords(data)); err != nil {
+		return nil, err
+	}
+	res, err := valueToAny(m.abi.Mapper, v)
+	if err != nil {
+		return nil, err
+	}
+	return res.(map[string]any), nil
+}
+
+// MustDecodeValuesToMap is like DecodeValuesToMap but panics on error.
+func (m *Method) MustDecodeValuesToMap(data []byte) map[string]any {
+	res, err := m.DecodeValuesToMap(data)
+	if err != nil {
+		panic(err)
+	}
+	return res
+}
+
 // String returns the human-readable signature of the method.
 func (m *Method) String() string {
 	var buf strings.Builder
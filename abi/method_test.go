@@ -10,6 +10,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/defiweb/go-eth/hexutil"
+	"github.com/defiweb/go-eth/types"
 )
 
 func TestParseMethod(t *testing.T) {
@@ -83,3 +84,284 @@ func TestMethod_DecodeArg(t *testing.T) {
 		})
 	}
 }
+
+func TestMethod_EncodeDecodeArgs_Empty(t *testing.T) {
+	type emptyStruct struct{}
+	type innerStruct struct {
+		X []*big.Int `abi:"x"`
+		Y []byte     `abi:"y"`
+	}
+	type pairStruct struct {
+		A *big.Int `abi:"a"`
+		B *big.Int `abi:"b"`
+	}
+	tests := []struct {
+		method   *Method
+		args     []any
+		expected string // encoded arguments, without the selector
+		decoded  []any  // pointers to values to decode into
+		want     []any  // expected decoded values
+	}{
+		// Empty dynamic array followed by a static value.
+		{
+			method: MustParseMethod("foo(uint256[] a, uint256 b)"),
+			args:   []any{[]*big.Int{}, big.NewInt(1)},
+			expected: "0000000000000000000000000000000000000000000000000000000000000040" +
+				"0000000000000000000000000000000000000000000000000000000000000001" +
+				"0000000000000000000000000000000000000000000000000000000000000000",
+			decoded: []any{new([]*big.Int), new(big.Int)},
+			want:    []any{&[]*big.Int{}, big.NewInt(1)},
+		},
+		// Empty array of dynamic tuples.
+		{
+			method: MustParseMethod("foo((uint256[] x, bytes y)[] a, uint256 b)"),
+			args:   []any{[]innerStruct{}, big.NewInt(1)},
+			expected: "0000000000000000000000000000000000000000000000000000000000000040" +
+				"0000000000000000000000000000000000000000000000000000000000000001" +
+				"0000000000000000000000000000000000000000000000000000000000000000",
+			decoded: []any{new([]innerStruct), new(big.Int)},
+			want:    []any{&[]innerStruct{}, big.NewInt(1)},
+		},
+		// Dynamic tuple with empty dynamic members.
+		{
+			method: MustParseMethod("foo((uint256[] x, bytes y) a, uint256 b)"),
+			args:   []any{innerStruct{X: []*big.Int{}, Y: []byte{}}, big.NewInt(1)},
+			expected: "0000000000000000000000000000000000000000000000000000000000000040" +
+				"0000000000000000000000000000000000000000000000000000000000000001" +
+				"0000000000000000000000000000000000000000000000000000000000000040" +
+				"0000000000000000000000000000000000000000000000000000000000000060" +
+				"0000000000000000000000000000000000000000000000000000000000000000" +
+				"0000000000000000000000000000000000000000000000000000000000000000",
+			decoded: []any{new(innerStruct), new(big.Int)},
+			want:    []any{&innerStruct{X: []*big.Int{}, Y: []byte{}}, big.NewInt(1)},
+		},
+		// Static fixed array of multi-word tuples followed by a static value.
+		{
+			method: MustParseMethod("foo((uint256 a, uint256 b)[2] a, uint256 b)"),
+			args: []any{
+				[2]pairStruct{{A: big.NewInt(1), B: big.NewInt(2)}, {A: big.NewInt(3), B: big.NewInt(4)}},
+				big.NewInt(5),
+			},
+			expected: "0000000000000000000000000000000000000000000000000000000000000001" +
+				"0000000000000000000000000000000000000000000000000000000000000002" +
+				"0000000000000000000000000000000000000000000000000000000000000003" +
+				"0000000000000000000000000000000000000000000000000000000000000004" +
+				"0000000000000000000000000000000000000000000000000000000000000005",
+			decoded: []any{new([2]pairStruct), new(big.Int)},
+			want: []any{
+				&[2]pairStruct{{A: big.NewInt(1), B: big.NewInt(2)}, {A: big.NewInt(3), B: big.NewInt(4)}},
+				big.NewInt(5),
+			},
+		},
+		// Empty tuples occupy no words. Arrays of empty tuples must be empty,
+		// see decodeArray.
+		{
+			method: NewMethod(
+				"foo",
+				NewTupleType(
+					TupleTypeElem{Name: "a", Type: NewTupleType()},
+					TupleTypeElem{Name: "b", Type: NewArrayType(NewTupleType())},
+					TupleTypeElem{Name: "c", Type: NewUintType(256)},
+				),
+				nil,
+				StateMutabilityNonPayable,
+			),
+			args: []any{emptyStruct{}, []emptyStruct{}, big.NewInt(1)},
+			expected: "0000000000000000000000000000000000000000000000000000000000000040" +
+				"0000000000000000000000000000000000000000000000000000000000000001" +
+				"0000000000000000000000000000000000000000000000000000000000000000",
+			decoded: []any{new(emptyStruct), new([]emptyStruct), new(big.Int)},
+			want:    []any{&emptyStruct{}, &[]emptyStruct{}, big.NewInt(1)},
+		},
+	}
+	for n, tt := range tests {
+		t.Run(fmt.Sprintf("case-%d", n+1), func(t *testing.T) {
+			enc, err := tt.method.EncodeArgs(tt.args...)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, hex.EncodeToString(enc[4:]))
+			require.NoError(t, tt.method.DecodeArgs(enc, tt.decoded...))
+			assert.Equal(t, tt.want, tt.decoded)
+		})
+	}
+}
+
+func TestMethod_DecodeValues_DynamicArrays(t *testing.T) {
+	m := MustParseMethod("tokens()(string[] names, bytes[] data)")
+	enc, err := m.Outputs().Value().(*TupleValue).EncodeABI()
+	require.NoError(t, err)
+
+	// Empty arrays.
+	var names []string
+	var data [][]byte
+	require.NoError(t, m.DecodeValues(enc.Bytes(), &names, &data))
+	assert.Equal(t, []string{}, names)
+	assert.Equal(t, [][]byte{}, data)
+
+	// Multi-element arrays.
+	out, err := EncodeValues(m.Outputs(), []string{"DAI", "USDC", ""}, [][]byte{{0x01}, {}})
+	require.NoError(t, err)
+	require.NoError(t, m.DecodeValues(out, &names, &data))
+	assert.Equal(t, []string{"DAI", "USDC", ""}, names)
+	assert.Equal(t, [][]byte{{0x01}, {}}, data)
+}
+
+func TestMethod_DecodeValues_TypedSlice(t *testing.T) {
+	t.Run("uint256[]", func(t *testing.T) {
+		m := MustParseMethod("balances()(uint256[])")
+		out, err := EncodeValues(m.Outputs(), []*big.Int{big.NewInt(1), big.NewInt(2), big.NewInt(3)})
+		require.NoError(t, err)
+		var res []*big.Int
+		require.NoError(t, m.DecodeValues(out, &res))
+		assert.Equal(t, []*big.Int{big.NewInt(1), big.NewInt(2), big.NewInt(3)}, res)
+	})
+	t.Run("int24[]", func(t *testing.T) {
+		m := MustParseMethod("ticks()(int24[])")
+		out, err := EncodeValues(m.Outputs(), []int32{-887272, 0, 887272})
+		require.NoError(t, err)
+		var res []int32
+		require.NoError(t, m.DecodeValues(out, &res))
+		assert.Equal(t, []int32{-887272, 0, 887272}, res)
+	})
+	t.Run("address[]", func(t *testing.T) {
+		m := MustParseMethod("owners()(address[])")
+		addrs := []types.Address{
+			types.MustAddressFromHex("0x1111111111111111111111111111111111111111"),
+			types.MustAddressFromHex("0x2222222222222222222222222222222222222222"),
+		}
+		out, err := EncodeValues(m.Outputs(), addrs)
+		require.NoError(t, err)
+		var res []types.Address
+		require.NoError(t, m.DecodeValues(out, &res))
+		assert.Equal(t, addrs, res)
+	})
+	t.Run("address[2]", func(t *testing.T) {
+		m := MustParseMethod("pair()(address[2])")
+		addrs := []types.Address{
+			types.MustAddressFromHex("0x1111111111111111111111111111111111111111"),
+			types.MustAddressFromHex("0x2222222222222222222222222222222222222222"),
+		}
+		out, err := EncodeValues(m.Outputs(), addrs)
+		require.NoError(t, err)
+		var res []types.Address
+		require.NoError(t, m.DecodeValues(out, &res))
+		assert.Equal(t, addrs, res)
+	})
+	t.Run("tuple[]", func(t *testing.T) {
+		type tickInfo struct {
+			Tick           int32    `abi:"tick"`
+			LiquidityNet   *big.Int `abi:"liquidityNet"`
+			LiquidityGross *big.Int `abi:"liquidityGross"`
+		}
+		m := MustParseMethod("getPopulatedTicksInWord(address pool, int16 tickBitmapIndex)((int24 tick, int128 liquidityNet, uint128 liquidityGross)[] populatedTicks)")
+		ticks := []tickInfo{
+			{Tick: -60, LiquidityNet: big.NewInt(-100), LiquidityGross: big.NewInt(100)},
+			{Tick: 60, LiquidityNet: big.NewInt(100), LiquidityGross: big.NewInt(100)},
+		}
+		out, err := EncodeValues(m.Outputs(), ticks)
+		require.NoError(t, err)
+		var res []tickInfo
+		require.NoError(t, m.DecodeValues(out, &res))
+		assert.Equal(t, ticks, res)
+	})
+	t.Run("empty", func(t *testing.T) {
+		m := MustParseMethod("owners()(address[])")
+		out, err := EncodeValues(m.Outputs(), []types.Address{})
+		require.NoError(t, err)
+		var res []types.Address
+		require.NoError(t, m.DecodeValues(out, &res))
+		assert.Equal(t, []types.Address{}, res)
+	})
+}
+
+func TestMethod_DecodeValues_NotEnoughData(t *testing.T) {
+	tests := []struct {
+		signature string
+		data      []byte
+		wantErr   string
+	}{
+		{signature: "foo()(uint256)", data: nil, wantErr: "abi: not enough data to decode: got 0 bytes, need at least 32"},
+		{signature: "foo()(uint256, string)", data: make([]byte, 32), wantErr: "abi: not enough data to decode: got 32 bytes, need at least 64"},
+		{signature: "foo()((uint256, address), bool)", data: make([]byte, 64), wantErr: "abi: not enough data to decode: got 64 bytes, need at least 96"},
+		{signature: "foo()(uint256[2])", data: make([]byte, 63), wantErr: "abi: not enough data to decode: got 63 bytes, need at least 64"},
+		{signature: "foo()()", data: nil},
+	}
+	for n, tt := range tests {
+		t.Run(fmt.Sprintf("case-%d", n+1), func(t *testing.T) {
+			m := MustParseMethod(tt.signature)
+			vals := make([]any, m.Outputs().Size())
+			err := m.DecodeValues(tt.data, vals...)
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.EqualError(t, err, tt.wantErr)
+			_, err = m.DecodeValuesToMap(tt.data)
+			assert.EqualError(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestMethod_DecodeArgs_NotEnoughData(t *testing.T) {
+	m := MustParseMethod("foo(uint256)")
+	assert.Error(t, m.DecodeArgs([]byte{0x01}, new(big.Int)))
+	assert.Error(t, m.DecodeArg(nil, map[string]any{}))
+	assert.EqualError(t, m.DecodeArgs(m.FourBytes().Bytes(), new(big.Int)), "abi: not enough data to decode: got 0 bytes, need at least 32")
+}
+
+func TestMethod_DecodeValuesToMap(t *testing.T) {
+	m := MustParseMethod("foo() returns (uint256 a, (bool x, string y)[] b, (address z, int8[2] w) c, bytes)")
+	addr := types.MustAddressFromHex("0x1111111111111111111111111111111111111111")
+	data, err := EncodeValues(
+		m.Outputs(),
+		1,
+		[]map[string]any{{"x": true, "y": "a"}, {"x": false, "y": "b"}},
+		map[string]any{"z": addr, "w": []int{-1, 2}},
+		[]byte{0x01},
+	)
+	require.NoError(t, err)
+
+	res, err := m.DecodeValuesToMap(data)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{
+		"a": big.NewInt(1),
+		"b": []any{
+			map[string]any{"x": true, "y": "a"},
+			map[string]any{"x": false, "y": "b"},
+		},
+		"c": map[string]any{
+			"z": addr,
+			"w": []any{big.NewInt(-1), big.NewInt(2)},
+		},
+		"arg3": []byte{0x01},
+	}, res)
+
+	_, err = m.DecodeValuesToMap([]byte{0x01})
+	assert.Error(t, err)
+}
+
+func TestMethod_EncodeArgsFromStrings(t *testing.T) {
+	m := MustParseMethod("foo(address a, uint256 b, bool c, bytes d, (uint8 x, string y)[] e)")
+	addr := types.MustAddressFromHex("0x1111111111111111111111111111111111111111")
+
+	expected, err := m.EncodeArgs(
+		addr,
+		big.NewInt(1000000000000000000),
+		true,
+		[]byte{0x01, 0x02},
+		[]map[string]any{{"x": 1, "y": "a"}, {"x": 2, "y": "b"}},
+	)
+	require.NoError(t, err)
+
+	enc, err := m.EncodeArgsFromStrings(
+		"0x1111111111111111111111111111111111111111",
+		"1000000000000000000",
+		"true",
+		"0x0102",
+		`[[1,"a"],["0x2","b"]]`,
+	)
+	require.NoError(t, err)
+	assert.Equal(t, expected, enc)
+
+	_, err = m.EncodeArgsFromStrings("0x1111111111111111111111111111111111111111")
+	assert.Error(t, err)
+}
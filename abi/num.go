@@ -244,6 +244,22 @@ func signedBitLen(x *big.Int) int {
 	return bitLen + 1
 }
 
+// maxSafeFloatInt is the largest integer up to which all integers can be
+// represented exactly as float64.
+const maxSafeFloatInt = 1 << 53
+
+// floatToBigInt converts a float to a big.Int. It returns false if the float
+// is not finite, has a fractional part or its magnitude exceeds 2^53. Larger
+// floats may have been rounded, e.g. when parsed from JSON, so they cannot be
+// converted reliably.
+func floatToBigInt(f float64) (*big.Int, bool) {
+	if math.IsInf(f, 0) || math.IsNaN(f) || f != math.Trunc(f) || math.Abs(f) > maxSafeFloatInt {
+		return nil, false
+	}
+	bn, _ := big.NewFloat(f).Int(nil)
+	return bn, true
+}
+
 func canSetInt(x int64, bitLen int) bool {
 	if bitLen >= 64 {
 		return true
@@ -27,6 +27,10 @@ func IsRevert(data []byte) bool {
 
 // DecodeRevert decodes the revert data returned by contract calls.
 // If the data is not a valid revert message, it returns an empty string.
+//
+// The function can also be used to decode the return data of failed
+// sub-calls, for example, the returnData of Multicall3 aggregate3 results
+// with allowFailure set.
 func DecodeRevert(data []byte) string {
 	// The code below is a slightly optimized version of
 	// Revert.DecodeValues(data).
@@ -49,3 +53,17 @@ func ToRevertError(data []byte) error {
 	}
 	return RevertError{Reason: DecodeRevert(data)}
 }
+
+// ToError converts the data returned by a failed contract call into a
+// RevertError or PanicError. If the data cannot be recognized as a revert or
+// panic, it returns nil.
+//
+// It is useful for calls that do not fail themselves but return the data of
+// failed sub-calls, such as Multicall3 aggregate3 with allowFailure set. To
+// also recognize custom errors, use Contract.ToError.
+func ToError(data []byte) error {
+	if err := ToRevertError(data); err != nil {
+		return err
+	}
+	return ToPanicError(data)
+}
@@ -2,6 +2,7 @@ package abi
 
 import (
 	"fmt"
+	"math/big"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -55,3 +56,31 @@ func TestToRevertError(t *testing.T) {
 	require.NotNil(t, revertErr)
 	assert.Equal(t, "revert: foo", revertErr.Error())
 }
+
+func TestToError_Aggregate3(t *testing.T) {
+	type result struct {
+		Success    bool   `abi:"success"`
+		ReturnData []byte `abi:"returnData"`
+	}
+	revertData := append(Revert.FourBytes().Bytes(), MustEncodeValues(Revert.Inputs(), "foo")...)
+	panicData := append(Panic.FourBytes().Bytes(), MustEncodeValues(Panic.Inputs(), big.NewInt(0x11))...)
+
+	// Multicall3 aggregate3 returns the revert data of failed sub-calls
+	// when allowFailure is set.
+	aggregate3 := MustParseMethod("aggregate3((address target, bool allowFailure, bytes callData)[] calls) returns ((bool success, bytes returnData)[] returnData)")
+	data, err := EncodeValues(aggregate3.Outputs(), []result{
+		{Success: true, ReturnData: hexutil.MustHexToBytes("0x01")},
+		{Success: false, ReturnData: revertData},
+		{Success: false, ReturnData: panicData},
+		{Success: false, ReturnData: nil},
+	})
+	require.NoError(t, err)
+
+	var results []result
+	require.NoError(t, aggregate3.DecodeValues(data, &results))
+	require.Len(t, results, 4)
+	assert.NoError(t, ToError(results[0].ReturnData))
+	assert.Equal(t, RevertError{Reason: "foo"}, ToError(results[1].ReturnData))
+	assert.Equal(t, PanicError{Code: big.NewInt(0x11)}, ToError(results[2].ReturnData))
+	assert.NoError(t, ToError(results[3].ReturnData))
+}
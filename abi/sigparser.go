@@ -214,7 +214,7 @@ func newTypeFromSig(abi *ABI, extraTypes map[string]Type, s sigparser.Parameter)
 		if typ = extraTypes[s.Type]; typ != nil {
 			return typ, nil
 		}
-		if typ = abi.Types[s.Type]; typ != nil {
+		if typ = abi.lookupType(s.Type); typ != nil {
 			return typ, nil
 		}
 		return nil, fmt.Errorf("abi: unknown type %q", s.Type)
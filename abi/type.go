@@ -3,6 +3,9 @@ package abi
 import (
 	"fmt"
 	"strings"
+
+	"github.com/defiweb/go-eth/crypto"
+	"github.com/defiweb/go-eth/types"
 )
 
 // Type is a representation of a type like uint256 or address. The type can be
@@ -29,6 +32,17 @@ type Type interface {
 	Value() Value
 }
 
+// TypeHash returns the Keccak256 hash of the canonical type of the given
+// type, e.g. keccak256("(uint256,address)") for a tuple of uint256 and
+// address.
+//
+// Note that the EIP-712 type hash is computed from the struct name and named
+// members, e.g. keccak256("Mail(address from,string contents)"), so it
+// differs from the hash returned by this function.
+func TypeHash(t Type) types.Hash {
+	return crypto.Keccak256([]byte(t.CanonicalType()))
+}
+
 // ParseType parses a type signature and returns a new Type.
 //
 // A type can be either an elementary type like uint256 or a tuple type. Tuple
@@ -38,8 +52,11 @@ type Type interface {
 // The generated types can be used to create new values, which can then be used
 // to encode or decode ABI data.
 //
-// Custom types may be added to the ABI.Types, this will allow the parser to
-// handle them.
+// Custom types may be added using the ABI.RegisterType method, this will allow
+// the parser to handle them.
+//
+// Parsed types are cached, so parsing the same signature multiple times is
+// cheap.
 //
 // The following examples are valid type signatures:
 //
@@ -80,7 +97,16 @@ func MustParseStruct(definition string) Type {
 //
 // See ParseType for more information.
 func (a *ABI) ParseType(signature string) (Type, error) {
-	return parseType(a, nil, signature)
+	typ, gen, ok := a.cachedType("type", signature)
+	if ok {
+		return typ, nil
+	}
+	typ, err := parseType(a, nil, signature)
+	if err != nil {
+		return nil, err
+	}
+	a.cacheType("type", signature, typ, gen)
+	return typ, nil
 }
 
 // MustParseType is like ParseType but panics on error.
@@ -96,7 +122,16 @@ func (a *ABI) MustParseType(signature string) Type {
 //
 // See ParseStruct for more information.
 func (a *ABI) ParseStruct(definition string) (Type, error) {
-	return parseStruct(a, nil, definition)
+	typ, gen, ok := a.cachedType("struct", definition)
+	if ok {
+		return typ, nil
+	}
+	typ, err := parseStruct(a, nil, definition)
+	if err != nil {
+		return nil, err
+	}
+	a.cacheType("struct", definition, typ, gen)
+	return typ, nil
 }
 
 // MustParseStruct is like ParseStruct but panics on error.
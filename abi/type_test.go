@@ -4,6 +4,8 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+
+	"github.com/defiweb/go-eth/crypto"
 )
 
 type nullType struct{}
@@ -19,6 +21,12 @@ func (n nullValue) EncodeABI() (Words, error)      { return nil, nil }
 func (n nullValue) DecodeABI(_ Words) (int, error) { return 0, nil }
 func (n dynamicNullType) IsDynamic() bool          { return true }
 
+func TestTypeHash(t *testing.T) {
+	assert.Equal(t, crypto.Keccak256([]byte("uint256")), TypeHash(MustParseType("uint256")))
+	assert.Equal(t, crypto.Keccak256([]byte("(address,uint256)")), TypeHash(MustParseType("(address to, uint256 value)")))
+	assert.Equal(t, crypto.Keccak256([]byte("(uint8,string)[2]")), TypeHash(MustParseType("(uint8 a, string b)[2]")))
+}
+
 func TestAliasType(t *testing.T) {
 	v := NewAliasType("alias", nullType{})
 	assert.Equal(t, &nullValue{}, v.Value())
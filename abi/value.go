@@ -82,10 +82,14 @@ func (t *TupleValue) DecodeABI(words Words) (int, error) {
 }
 
 // MapFrom implements the anymapper.MapFrom interface.
+//
+// The source values are mapped to the tuple elements by name. The order of
+// the elements is always the same as in the tuple type, regardless of the
+// source type.
 func (t *TupleValue) MapFrom(m Mapper, src any) error {
-	vals := make(map[string]Value, len(*t))
-	for _, elem := range *t {
-		vals[elem.Name] = elem.Value
+	vals, err := t.valuesByName()
+	if err != nil {
+		return fmt.Errorf("abi: cannot map tuple from %s: %w", reflect.TypeOf(src), err)
 	}
 	if err := m.Map(src, vals); err != nil {
 		return fmt.Errorf("abi: cannot map tuple from %s: %w", reflect.TypeOf(src), err)
@@ -95,9 +99,9 @@ func (t *TupleValue) MapFrom(m Mapper, src any) error {
 
 // MapTo implements the anymapper.MapTo interface.
 func (t *TupleValue) MapTo(m Mapper, dst any) error {
-	vals := make(map[string]Value, len(*t))
-	for _, elem := range *t {
-		vals[elem.Name] = elem.Value
+	vals, err := t.valuesByName()
+	if err != nil {
+		return fmt.Errorf("abi: cannot map tuple to %s: %w", reflect.TypeOf(dst), err)
 	}
 	if err := m.Map(vals, dst); err != nil {
 		return fmt.Errorf("abi: cannot map tuple to %s: %w", reflect.TypeOf(dst), err)
@@ -105,6 +109,19 @@ func (t *TupleValue) MapTo(m Mapper, dst any) error {
 	return nil
 }
 
+// valuesByName returns the tuple element values indexed by their names.
+// The values are not copied, so mapping into them modifies the tuple.
+func (t *TupleValue) valuesByName() (map[string]Value, error) {
+	vals := make(map[string]Value, len(*t))
+	for _, elem := range *t {
+		if _, ok := vals[elem.Name]; ok {
+			return nil, fmt.Errorf("duplicate element name %q", elem.Name)
+		}
+		vals[elem.Name] = elem.Value
+	}
+	return vals, nil
+}
+
 // ArrayValue is a value of array type.
 //
 // During encoding, the ArrayValue can be mapped from a slice or an array.
@@ -529,13 +546,19 @@ func (b FixedBytesValue) MapTo(_ Mapper, dst any) error {
 		if dstRef.Type().Elem().Kind() != reflect.Uint8 {
 			return fmt.Errorf("abi: cannot map bytes to %s", dstRef.Type())
 		}
-		dstRef.Set(reflect.ValueOf([]byte(b)))
+		// The element type may be a named byte type, so the slice cannot
+		// be assigned directly.
+		v := reflect.MakeSlice(dstRef.Type(), len(b), len(b))
+		for i := 0; i < len(b); i++ {
+			v.Index(i).SetUint(uint64(b[i]))
+		}
+		dstRef.Set(v)
 	case reflect.Array:
 		if dstRef.Type().Elem().Kind() != reflect.Uint8 {
 			return fmt.Errorf("abi: cannot map bytes to %s", dstRef.Type())
 		}
 		if dstRef.Len() != len(b) {
-			return fmt.Errorf("abi: cannot map bytes%d to %s: length mismatch", len(b), dstRef.Type())
+			return fmt.Errorf("abi: cannot map bytes%d to %s: length mismatch, expected %d bytes", len(b), dstRef.Type(), dstRef.Len())
 		}
 		for i := 0; i < dstRef.Len(); i++ {
 			dstRef.Index(i).SetUint(uint64(b[i]))
@@ -619,7 +642,9 @@ func (b FixedBytesValue) MapTo(_ Mapper, dst any) error {
 // rules described in the documentation of anymapper package.
 //
 // During decoding, the UintValue is mapped from the *big.Int type using the
-// rules described in the documentation of anymapper package.
+// rules described in the documentation of anymapper package. It may also be
+// decoded into a [32]byte array or types.Hash, in which case the value is
+// stored as a 32-byte big-endian number.
 type UintValue struct {
 	big.Int
 	Size int
@@ -656,7 +681,7 @@ func (u *UintValue) MapFrom(_ Mapper, src any) error {
 			return fmt.Errorf("abi: cannot map %s to uint%d: %v", srcRef.Type(), u.Size, err)
 		}
 		if bn.Sign() < 0 {
-			return fmt.Errorf("abi: cannot map %s to uint%d: negative value", srcRef.Type(), u.Size)
+			return fmt.Errorf("abi: cannot map negative %s to uint%d", srcRef.Type(), u.Size)
 		}
 		if bn.BitLen() > u.Size {
 			return fmt.Errorf("abi: cannot map %s to uint%d: value too large", srcRef.Type(), u.Size)
@@ -665,7 +690,7 @@ func (u *UintValue) MapFrom(_ Mapper, src any) error {
 	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
 		i64 := srcRef.Int()
 		if i64 < 0 {
-			return fmt.Errorf("abi: cannot map %s to uint%d: negative value", srcRef.Type(), u.Size)
+			return fmt.Errorf("abi: cannot map negative %s to uint%d", srcRef.Type(), u.Size)
 		}
 		if !canSetUint(uint64(i64), u.Size) {
 			return fmt.Errorf("abi: cannot map value to uint%d: value too large", u.Size)
@@ -676,11 +701,23 @@ func (u *UintValue) MapFrom(_ Mapper, src any) error {
 			return fmt.Errorf("abi: cannot map value to uint%d: value too large", u.Size)
 		}
 		u.Int.SetUint64(srcRef.Uint())
+	case reflect.Float32, reflect.Float64:
+		bn, ok := floatToBigInt(srcRef.Float())
+		if !ok {
+			return fmt.Errorf("abi: cannot map %s to uint%d: value is not an integer or exceeds 2^53", srcRef.Type(), u.Size)
+		}
+		if bn.Sign() < 0 {
+			return fmt.Errorf("abi: cannot map negative %s to uint%d", srcRef.Type(), u.Size)
+		}
+		if bn.BitLen() > u.Size {
+			return fmt.Errorf("abi: cannot map %s to uint%d: value too large", srcRef.Type(), u.Size)
+		}
+		u.Int = *bn
 	default:
 		switch srcTyp := srcRef.Interface().(type) {
 		case big.Int:
 			if srcTyp.Sign() < 0 {
-				return fmt.Errorf("abi: cannot map %s to uint%d: negative value", srcRef.Type(), u.Size)
+				return fmt.Errorf("abi: cannot map negative %s to uint%d", srcRef.Type(), u.Size)
 			}
 			if srcTyp.BitLen() > u.Size {
 				return fmt.Errorf("abi: cannot map %s to uint%d: value too large", srcRef.Type(), u.Size)
@@ -689,17 +726,17 @@ func (u *UintValue) MapFrom(_ Mapper, src any) error {
 		case types.Number:
 			bn := srcTyp.Big()
 			if bn.Sign() < 0 {
-				return fmt.Errorf("abi: cannot map %s to uint%d: negative value", srcRef.Type(), u.Size)
+				return fmt.Errorf("abi: cannot map negative %s to uint%d", srcRef.Type(), u.Size)
 			}
 			if bn.BitLen() > u.Size {
 				return fmt.Errorf("abi: cannot map %s to uint%d: value too large", srcRef.Type(), u.Size)
 			}
 			u.Int = *bn
 		case types.BlockNumber:
-			bn := srcTyp.Big()
-			if bn.Sign() < 0 {
-				return fmt.Errorf("abi: cannot map %s to uint%d: negative value", srcRef.Type(), u.Size)
+			if srcTyp.IsTag() {
+				return fmt.Errorf("abi: cannot map %s to uint%d: block tags are not supported", srcRef.Type(), u.Size)
 			}
+			bn := srcTyp.Big()
 			if bn.BitLen() > u.Size {
 				return fmt.Errorf("abi: cannot map %s to uint%d: value too large", srcRef.Type(), u.Size)
 			}
@@ -707,7 +744,7 @@ func (u *UintValue) MapFrom(_ Mapper, src any) error {
 		case time.Time:
 			bn := new(big.Int).SetInt64(srcTyp.Unix())
 			if bn.Sign() < 0 {
-				return fmt.Errorf("abi: cannot map %s to uint%d: negative value", srcRef.Type(), u.Size)
+				return fmt.Errorf("abi: cannot map negative %s to uint%d", srcRef.Type(), u.Size)
 			}
 			if bn.BitLen() > u.Size {
 				return fmt.Errorf("abi: cannot map %s to uint%d: value too large", srcRef.Type(), u.Size)
@@ -748,6 +785,12 @@ func (u *UintValue) MapTo(_ Mapper, dst any) error {
 			dstRef.Set(reflect.ValueOf(types.NumberFromBigInt(&u.Int)))
 		case types.BlockNumber:
 			dstRef.Set(reflect.ValueOf(types.BlockNumberFromBigInt(&u.Int)))
+		case [32]byte, types.Hash:
+			x := newUintX(256)
+			if err := x.SetBigInt(&u.Int); err != nil {
+				return fmt.Errorf("abi: cannot map uint%d to %s: %v", u.Size, dstRef.Type(), err)
+			}
+			reflect.Copy(dstRef, reflect.ValueOf(x.Bytes()))
 		default:
 			return fmt.Errorf("abi: cannot map uint%d to %s", u.Size, dstRef.Type())
 		}
@@ -761,7 +804,9 @@ func (u *UintValue) MapTo(_ Mapper, dst any) error {
 // rules described in the documentation of anymapper package.
 //
 // During decoding, the IntValue is mapped from the *big.Int type using the
-// rules described in the documentation of anymapper package.
+// rules described in the documentation of anymapper package. It may also be
+// decoded into a [32]byte array or types.Hash, in which case the value is
+// stored as a 32-byte big-endian two's complement number.
 type IntValue struct {
 	big.Int
 	Size int
@@ -815,36 +860,45 @@ func (i *IntValue) MapFrom(_ Mapper, src any) error {
 			return fmt.Errorf("abi: cannot map value to int%d: value too large", i.Size)
 		}
 		i.Int.SetUint64(u64)
+	case reflect.Float32, reflect.Float64:
+		bn, ok := floatToBigInt(srcRef.Float())
+		if !ok {
+			return fmt.Errorf("abi: cannot map %s to int%d: value is not an integer or exceeds 2^53", srcRef.Type(), i.Size)
+		}
+		if signedBitLen(bn) > i.Size {
+			return fmt.Errorf("abi: cannot map %s to int%d: value too large", srcRef.Type(), i.Size)
+		}
+		i.Int = *bn
 	default:
 		switch srcTyp := srcRef.Interface().(type) {
 		case big.Int:
 			if signedBitLen(&srcTyp) > i.Size {
-				return fmt.Errorf("abi: cannot map %s to uint%d: value too large", srcRef.Type(), i.Size)
+				return fmt.Errorf("abi: cannot map %s to int%d: value too large", srcRef.Type(), i.Size)
 			}
 			i.Int = srcTyp
 		case types.Number:
 			bn := srcTyp.Big()
 			if signedBitLen(bn) > i.Size {
-				return fmt.Errorf("abi: cannot map %s to uint%d: value too large", srcRef.Type(), i.Size)
+				return fmt.Errorf("abi: cannot map %s to int%d: value too large", srcRef.Type(), i.Size)
 			}
 			i.Int = *bn
 		case types.BlockNumber:
-			bn := srcTyp.Big()
-			if bn.Sign() < 0 {
-				return fmt.Errorf("abi: cannot map %s to uint%d: latest, earliest and pending are not supported", srcRef.Type(), i.Size)
+			if srcTyp.IsTag() {
+				return fmt.Errorf("abi: cannot map %s to int%d: block tags are not supported", srcRef.Type(), i.Size)
 			}
+			bn := srcTyp.Big()
 			if signedBitLen(bn) > i.Size {
-				return fmt.Errorf("abi: cannot map %s to uint%d: value too large", srcRef.Type(), i.Size)
+				return fmt.Errorf("abi: cannot map %s to int%d: value too large", srcRef.Type(), i.Size)
 			}
 			i.Int = *bn
 		case time.Time:
 			bn := new(big.Int).SetInt64(srcTyp.Unix())
 			if signedBitLen(bn) > i.Size {
-				return fmt.Errorf("abi: cannot map %s to uint%d: value too large", srcRef.Type(), i.Size)
+				return fmt.Errorf("abi: cannot map %s to int%d: value too large", srcRef.Type(), i.Size)
 			}
 			i.Int = *bn
 		default:
-			return fmt.Errorf("abi: cannot map %s to uint%d", srcRef.Type(), i.Size)
+			return fmt.Errorf("abi: cannot map %s to int%d", srcRef.Type(), i.Size)
 		}
 	}
 	return nil
@@ -862,12 +916,12 @@ func (i *IntValue) MapTo(_ Mapper, dst any) error {
 		}
 		dstRef.SetInt(i.Int64())
 	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if i.Sign() < 0 {
+			return fmt.Errorf("abi: cannot map negative int%d to %s", i.Size, dstRef.Type())
+		}
 		if i.Int.BitLen() > dstRef.Type().Bits() {
 			return fmt.Errorf("abi: cannot map int%d to %s: value too large", i.Size, dstRef.Type())
 		}
-		if i.Sign() < 0 {
-			return fmt.Errorf("abi: cannot map int%d to %s: value is negative", i.Size, dstRef.Type())
-		}
 		dstRef.SetUint(i.Uint64())
 	case reflect.Interface:
 		dstRef.Set(reflect.ValueOf(&i.Int))
@@ -881,9 +935,15 @@ func (i *IntValue) MapTo(_ Mapper, dst any) error {
 			dstRef.Set(reflect.ValueOf(types.NumberFromBigInt(&i.Int)))
 		case types.BlockNumber:
 			if i.Sign() < 0 {
-				return fmt.Errorf("abi: cannot map int%d to %s: value is negative", i.Size, dstRef.Type())
+				return fmt.Errorf("abi: cannot map negative int%d to %s", i.Size, dstRef.Type())
 			}
 			dstRef.Set(reflect.ValueOf(types.BlockNumberFromBigInt(&i.Int)))
+		case [32]byte, types.Hash:
+			x := newIntX(256)
+			if err := x.SetBigInt(&i.Int); err != nil {
+				return fmt.Errorf("abi: cannot map int%d to %s: %v", i.Size, dstRef.Type(), err)
+			}
+			reflect.Copy(dstRef, reflect.ValueOf(x.Bytes()))
 		default:
 			return fmt.Errorf("abi: cannot map int%d to %s", i.Size, dstRef.Type())
 		}
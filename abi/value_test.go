@@ -2,9 +2,11 @@ package abi
 
 import (
 	"bytes"
+	"fmt"
 	"math"
 	"math/big"
 	"net/url"
+	"reflect"
 	"strings"
 	"testing"
 	"time"
@@ -312,6 +314,18 @@ func TestEncodeABI(t *testing.T) {
 			arg:  big.NewInt(-128),
 			want: Words{padR("ffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff80")},
 		},
+		{
+			name: "int8#-1",
+			val:  &IntValue{Size: 8},
+			arg:  big.NewInt(-1),
+			want: Words{padR("ffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff")},
+		},
+		{
+			name: "int24#-10",
+			val:  &IntValue{Size: 24},
+			arg:  big.NewInt(-10),
+			want: Words{padR("fffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff6")},
+		},
 		{
 			name: "int256#time.Unix-0",
 			val:  &IntValue{Size: 256},
@@ -535,6 +549,14 @@ func TestDecodeABI(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "array#zero-width-elements",
+			abi: Words{
+				padL("7fffffff"), // array length
+			},
+			val:     &ArrayValue{Type: NewTupleType()},
+			wantErr: true,
+		},
 		// FixedArrayValue:
 		{
 			name: "fixed-array#empty",
@@ -1738,10 +1760,22 @@ func TestMapTo(t *testing.T) {
 			wantErr: true,
 		},
 		{
-			name:    "uint256->types.Hash",
-			arg:     new(types.Hash),
-			val:     func() Value { i := &UintValue{Size: 256}; i.SetUint64(42); return i }(),
-			wantErr: true,
+			name: "uint256->types.Hash",
+			arg:  new(types.Hash),
+			val:  func() Value { i := &UintValue{Size: 256}; i.SetUint64(42); return i }(),
+			want: types.MustHashFromHexPtr("0x2a", types.PadLeft),
+		},
+		{
+			name: "uint256->[32]byte",
+			arg:  new([32]byte),
+			val:  func() Value { i := &UintValue{Size: 256}; i.SetUint64(42); return i }(),
+			want: func() *[32]byte { b := [32]byte{31: 42}; return &b }(),
+		},
+		{
+			name: "uint256->[32]byte#max",
+			arg:  new([32]byte),
+			val:  func() Value { i := &UintValue{Size: 256}; i.Set(MaxUint[256]); return i }(),
+			want: func() *[32]byte { return (*[32]byte)(bytes.Repeat([]byte{0xff}, 32)) }(),
 		},
 		{
 			name: "uint256->types.Number",
@@ -1829,10 +1863,21 @@ func TestMapTo(t *testing.T) {
 			wantErr: true,
 		},
 		{
-			name:    "int256->types.Hash",
-			arg:     new(types.Hash),
-			val:     func() Value { i := &IntValue{Size: 256}; i.SetUint64(42); return i }(),
-			wantErr: true,
+			name: "int256->types.Hash",
+			arg:  new(types.Hash),
+			val:  func() Value { i := &IntValue{Size: 256}; i.SetUint64(42); return i }(),
+			want: types.MustHashFromHexPtr("0x2a", types.PadLeft),
+		},
+		{
+			name: "int8->[32]byte#negative",
+			arg:  new([32]byte),
+			val:  func() Value { i := &IntValue{Size: 8}; i.SetInt64(-2); return i }(),
+			want: func() *[32]byte {
+				b := [32]byte{}
+				copy(b[:], bytes.Repeat([]byte{0xff}, 32))
+				b[31] = 0xfe
+				return &b
+			}(),
 		},
 		{
 			name: "int256->types.Number",
@@ -1933,6 +1978,132 @@ func TestMapTo(t *testing.T) {
 	}
 }
 
+func TestTupleValue_MapFromMapOrder(t *testing.T) {
+	typ := NewTupleType(
+		TupleTypeElem{Name: "e", Type: NewUintType(256)},
+		TupleTypeElem{Name: "d", Type: NewStringType()},
+		TupleTypeElem{Name: "c", Type: NewUintType(8)},
+		TupleTypeElem{Name: "b", Type: NewBytesType()},
+		TupleTypeElem{Name: "a", Type: NewAddressType()},
+	)
+	src := map[string]any{
+		"a": "0x1111111111111111111111111111111111111111",
+		"b": []byte{1, 2, 3},
+		"c": 3,
+		"d": "foo",
+		"e": 5,
+	}
+	want := MustEncodeValues(typ, 5, "foo", 3, []byte{1, 2, 3}, "0x1111111111111111111111111111111111111111")
+
+	// Map iteration order is random, so the encoding is repeated to make
+	// sure that it does not affect the result.
+	for i := 0; i < 100; i++ {
+		got, err := EncodeValue(typ, src)
+		require.NoError(t, err)
+		require.Equal(t, want, got)
+	}
+}
+
+func TestTupleValue_MapDuplicateNames(t *testing.T) {
+	// The second element has no name, so it defaults to "arg1".
+	typ := NewTupleType(
+		TupleTypeElem{Name: "arg1", Type: NewUintType(256)},
+		TupleTypeElem{Type: NewUintType(256)},
+	)
+	_, err := EncodeValue(typ, map[string]any{"arg1": 1})
+	assert.Error(t, err)
+
+	var dst map[string]any
+	assert.Error(t, DecodeValue(typ, make([]byte, 64), &dst))
+}
+
+func TestMapNegativeToUnsigned(t *testing.T) {
+	t.Run("MapTo", func(t *testing.T) {
+		tests := []struct {
+			size    int
+			arg     any
+			wantErr string
+		}{
+			{size: 256, arg: new(uint), wantErr: "abi: cannot map negative int256 to uint"},
+			{size: 256, arg: new(uint8), wantErr: "abi: cannot map negative int256 to uint8"},
+			{size: 256, arg: new(uint16), wantErr: "abi: cannot map negative int256 to uint16"},
+			{size: 256, arg: new(uint32), wantErr: "abi: cannot map negative int256 to uint32"},
+			{size: 256, arg: new(uint64), wantErr: "abi: cannot map negative int256 to uint64"},
+			{size: 8, arg: new(uint64), wantErr: "abi: cannot map negative int8 to uint64"},
+			{size: 256, arg: new(types.BlockNumber), wantErr: "abi: cannot map negative int256 to types.BlockNumber"},
+		}
+		for _, tt := range tests {
+			t.Run(reflect.TypeOf(tt.arg).Elem().String(), func(t *testing.T) {
+				v := &IntValue{Size: tt.size}
+				v.SetInt64(-1)
+				assert.EqualError(t, Default.Mapper.Map(v, tt.arg), tt.wantErr)
+			})
+		}
+	})
+	t.Run("MapFrom", func(t *testing.T) {
+		tests := []struct {
+			size    int
+			data    any
+			wantErr string
+		}{
+			{size: 256, data: int(-1), wantErr: "abi: cannot map negative int to uint256"},
+			{size: 8, data: int8(-1), wantErr: "abi: cannot map negative int8 to uint8"},
+			{size: 256, data: int16(-1), wantErr: "abi: cannot map negative int16 to uint256"},
+			{size: 256, data: int32(-1), wantErr: "abi: cannot map negative int32 to uint256"},
+			{size: 64, data: int64(-1), wantErr: "abi: cannot map negative int64 to uint64"},
+			{size: 256, data: "-0x1", wantErr: "abi: cannot map negative string to uint256"},
+			{size: 256, data: big.NewInt(-1), wantErr: "abi: cannot map negative big.Int to uint256"},
+			{size: 256, data: types.MustNumberFromHex("-0x1"), wantErr: "abi: cannot map negative types.Number to uint256"},
+			{size: 256, data: time.Unix(-1, 0), wantErr: "abi: cannot map negative time.Time to uint256"},
+			{size: 256, data: float64(-1), wantErr: "abi: cannot map negative float64 to uint256"},
+			{size: 256, data: float32(1e18), wantErr: "abi: cannot map float32 to uint256: value is not an integer or exceeds 2^53"},
+		}
+		for _, tt := range tests {
+			t.Run(reflect.TypeOf(tt.data).String(), func(t *testing.T) {
+				assert.EqualError(t, Default.Mapper.Map(tt.data, &UintValue{Size: tt.size}), tt.wantErr)
+			})
+		}
+	})
+}
+
+func TestFixedBytesMapToArray(t *testing.T) {
+	type namedByte byte
+	for n := 1; n <= 32; n++ {
+		t.Run(fmt.Sprintf("bytes%d", n), func(t *testing.T) {
+			data := make([]byte, n)
+			named := make([]namedByte, n)
+			for i := range data {
+				data[i] = byte(i + 1)
+				named[i] = namedByte(i + 1)
+			}
+			typ := MustParseType(fmt.Sprintf("bytes%d", n))
+			enc, err := EncodeValue(typ, data)
+			require.NoError(t, err)
+
+			// Exactly-sized array.
+			dst := reflect.New(reflect.ArrayOf(n, reflect.TypeOf(byte(0))))
+			require.NoError(t, DecodeValue(typ, enc, dst.Interface()))
+			assert.Equal(t, data, dst.Elem().Slice(0, n).Bytes())
+
+			// Exactly-sized array of a named byte type.
+			dstNamed := reflect.New(reflect.ArrayOf(n, reflect.TypeOf(namedByte(0))))
+			require.NoError(t, DecodeValue(typ, enc, dstNamed.Interface()))
+			assert.Equal(t, named, dstNamed.Elem().Slice(0, n).Interface())
+
+			// Slice of a named byte type.
+			var dstSlice []namedByte
+			require.NoError(t, DecodeValue(typ, enc, &dstSlice))
+			assert.Equal(t, named, dstSlice)
+
+			// Array with a different size.
+			dstBad := reflect.New(reflect.ArrayOf(n+1, reflect.TypeOf(byte(0))))
+			err = DecodeValue(typ, enc, dstBad.Interface())
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), "length mismatch")
+		})
+	}
+}
+
 func padL(h string) (w Word) {
 	_ = (&w).SetBytesPadLeft(hexutil.MustHexToBytes(h))
 	return w
@@ -37,14 +37,37 @@ func AddMessagePrefix(data []byte) []byte {
 	return []byte(fmt.Sprintf("\x19Ethereum Signed Message:\n%d%s", len(data), data))
 }
 
+// AddValidatorPrefix adds the EIP-191 version 0x00 prefix, followed by the
+// intended validator address, to the given data.
+func AddValidatorPrefix(validator types.Address, data []byte) []byte {
+	b := make([]byte, 0, 2+types.AddressLength+len(data))
+	b = append(b, 0x19, 0x00)
+	b = append(b, validator.Bytes()...)
+	return append(b, data...)
+}
+
 // ECSigner returns a Signer implementation for ECDSA.
-func ECSigner(key *ecdsa.PrivateKey) Signer { return &ecSigner{key} }
+//
+// Legacy transactions are signed using EIP-155 replay protection if the
+// transaction chain ID is set and is not zero.
+func ECSigner(key *ecdsa.PrivateKey) Signer { return &ecSigner{key: key, eip155: true} }
+
+// ECSignerWithoutEIP155 returns a Signer implementation for ECDSA that signs
+// legacy transactions without EIP-155 replay protection, even if the
+// transaction chain ID is set. The V value of such signatures is 27 or 28.
+//
+// It should only be used for chains that do not support EIP-155. Other
+// transaction types are signed in the same way as by ECSigner.
+func ECSignerWithoutEIP155(key *ecdsa.PrivateKey) Signer { return &ecSigner{key: key} }
 
 // ECRecoverer is a Recoverer implementation for ECDSA.
 var ECRecoverer Recoverer = &ecRecoverer{}
 
 type (
-	ecSigner    struct{ key *ecdsa.PrivateKey }
+	ecSigner struct {
+		key    *ecdsa.PrivateKey
+		eip155 bool
+	}
 	ecRecoverer struct{}
 )
 
@@ -57,7 +80,7 @@ func (s *ecSigner) SignMessage(data []byte) (*types.Signature, error) {
 }
 
 func (s *ecSigner) SignTransaction(tx *types.Transaction) error {
-	return ecSignTransaction(s.key, tx)
+	return ecSignTransaction(s.key, tx, s.eip155)
 }
 
 func (r *ecRecoverer) RecoverHash(hash types.Hash, sig types.Signature) (*types.Address, error) {
@@ -56,7 +56,11 @@ func ecSignMessage(key *ecdsa.PrivateKey, data []byte) (*types.Signature, error)
 }
 
 // ecSignTransaction signs the given transaction with the given private key.
-func ecSignTransaction(key *ecdsa.PrivateKey, tx *types.Transaction) error {
+//
+// If eip155 is true, legacy transactions with a non-zero chain ID are signed
+// using EIP-155 replay protection. Otherwise, legacy transactions are signed
+// without it, and V is either 27 or 28.
+func ecSignTransaction(key *ecdsa.PrivateKey, tx *types.Transaction, eip155 bool) error {
 	if key == nil {
 		return fmt.Errorf("missing private key")
 	}
@@ -64,7 +68,8 @@ func ecSignTransaction(key *ecdsa.PrivateKey, tx *types.Transaction) error {
 	if tx.From != nil && *tx.From != from {
 		return fmt.Errorf("invalid signer address: %s", tx.From)
 	}
-	hash, err := signingHash(tx)
+	eip155 = eip155 && tx.ChainID != nil && *tx.ChainID != 0
+	hash, err := signingHash(withoutLegacyChainID(tx, eip155))
 	if err != nil {
 		return err
 	}
@@ -75,7 +80,7 @@ func ecSignTransaction(key *ecdsa.PrivateKey, tx *types.Transaction) error {
 	sv, sr, ss := sig.V, sig.R, sig.S
 	switch tx.Type {
 	case types.LegacyTxType:
-		if tx.ChainID != nil {
+		if eip155 {
 			sv = new(big.Int).Add(sv, new(big.Int).SetUint64(*tx.ChainID*2))
 			sv = new(big.Int).Add(sv, big.NewInt(35))
 		} else {
@@ -83,14 +88,27 @@ func ecSignTransaction(key *ecdsa.PrivateKey, tx *types.Transaction) error {
 		}
 	case types.AccessListTxType:
 	case types.DynamicFeeTxType:
+	case types.BlobTxType:
 	default:
-		return fmt.Errorf("unsupported transaction type: %d", tx.Type)
+		return &types.UnsupportedTxTypeError{Type: tx.Type}
 	}
 	tx.From = &from
 	tx.Signature = types.SignatureFromVRSPtr(sv, sr, ss)
 	return nil
 }
 
+// withoutLegacyChainID returns a copy of the given legacy transaction without
+// the chain ID, if eip155 is false. Signing hashes of such transactions must
+// not include the chain ID. Other transactions are returned as is.
+func withoutLegacyChainID(tx *types.Transaction, eip155 bool) *types.Transaction {
+	if tx.Type != types.LegacyTxType || eip155 || tx.ChainID == nil {
+		return tx
+	}
+	cpy := *tx
+	cpy.ChainID = nil
+	return &cpy
+}
+
 // ecRecoverHash recovers the Ethereum address from the given hash and signature.
 func ecRecoverHash(hash types.Hash, sig types.Signature) (*types.Address, error) {
 	if sig.V.BitLen() > 8 {
@@ -148,11 +166,16 @@ func ecRecoverTransaction(tx *types.Transaction) (*types.Address, error) {
 			sig.V = new(big.Int).Add(new(big.Int).Mod(x, big.NewInt(2)), big.NewInt(27))
 		} else {
 			sig.V = new(big.Int).Sub(sig.V, big.NewInt(27))
+
+			// Signatures without EIP-155 replay protection do not
+			// include the chain ID in the signing hash.
+			tx = withoutLegacyChainID(tx, false)
 		}
 	case types.AccessListTxType:
 	case types.DynamicFeeTxType:
+	case types.BlobTxType:
 	default:
-		return nil, fmt.Errorf("unsupported transaction type: %d", tx.Type)
+		return nil, &types.UnsupportedTxTypeError{Type: tx.Type}
 	}
 	hash, err := signingHash(tx)
 	if err != nil {
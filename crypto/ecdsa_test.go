@@ -2,6 +2,7 @@ package crypto
 
 import (
 	"bytes"
+	"encoding/json"
 	"math/big"
 	"testing"
 
@@ -46,7 +47,7 @@ func Test_ecSignTransaction(t *testing.T) {
 			SetGasPrice(big.NewInt(20000000000)).
 			SetNonce(9).
 			SetValue(big.NewInt(1000000000000000000))
-		err := ecSignTransaction(key.ToECDSA(), tx)
+		err := ecSignTransaction(key.ToECDSA(), tx, true)
 
 		require.NoError(t, err)
 		assert.Equal(t, "1b", tx.Signature.V.Text(16))
@@ -63,13 +64,53 @@ func Test_ecSignTransaction(t *testing.T) {
 			SetNonce(9).
 			SetValue(big.NewInt(1000000000000000000)).
 			SetChainID(1337)
-		err := ecSignTransaction(key.ToECDSA(), tx)
+		err := ecSignTransaction(key.ToECDSA(), tx, true)
 
 		require.NoError(t, err)
 		assert.Equal(t, "a95", tx.Signature.V.Text(16))
 		assert.Equal(t, "14702a15dd7739397f25e3902a0c2bf6989e93888201139aac2c67a8f33a2f3f", tx.Signature.R.Text(16))
 		assert.Equal(t, "4a10ba6cf47ace7e3c847e38583f5b1e1c7d8a862f4b43cd74480a03007363f7", tx.Signature.S.Text(16))
 	})
+	t.Run("legacy-eip155-disabled", func(t *testing.T) {
+		key, _ := btcec.PrivKeyFromBytes(bytes.Repeat([]byte{0x01}, 32))
+		tx := (&types.Transaction{}).
+			SetType(types.LegacyTxType).
+			SetTo(types.MustAddressFromHex("0x3535353535353535353535353535353535353535")).
+			SetGasLimit(21000).
+			SetGasPrice(big.NewInt(20000000000)).
+			SetNonce(9).
+			SetValue(big.NewInt(1000000000000000000)).
+			SetChainID(1337)
+		err := ecSignTransaction(key.ToECDSA(), tx, false)
+
+		// The signature must be the same as for a transaction without
+		// a chain ID.
+		require.NoError(t, err)
+		assert.Equal(t, "1b", tx.Signature.V.Text(16))
+		assert.Equal(t, "2bfad43ba1b40e7f3ffb6342b1a6eecc700dd344fb0aba543aed5c10fd1a9470", tx.Signature.R.Text(16))
+		assert.Equal(t, "615bff48c483d368ed4f6e327a6ddd8831e544d0ca08f1345433e4ed204f8537", tx.Signature.S.Text(16))
+
+		addr, err := ecRecoverTransaction(tx)
+		require.NoError(t, err)
+		assert.Equal(t, "0x1a642f0e3c3af545e7acbd38b07251b3990914f1", addr.String())
+	})
+	t.Run("legacy-zero-chain-id", func(t *testing.T) {
+		key, _ := btcec.PrivKeyFromBytes(bytes.Repeat([]byte{0x01}, 32))
+		tx := (&types.Transaction{}).
+			SetType(types.LegacyTxType).
+			SetTo(types.MustAddressFromHex("0x3535353535353535353535353535353535353535")).
+			SetGasLimit(21000).
+			SetGasPrice(big.NewInt(20000000000)).
+			SetNonce(9).
+			SetValue(big.NewInt(1000000000000000000)).
+			SetChainID(0)
+		err := ecSignTransaction(key.ToECDSA(), tx, true)
+
+		require.NoError(t, err)
+		assert.Equal(t, "1b", tx.Signature.V.Text(16))
+		assert.Equal(t, "2bfad43ba1b40e7f3ffb6342b1a6eecc700dd344fb0aba543aed5c10fd1a9470", tx.Signature.R.Text(16))
+		assert.Equal(t, "615bff48c483d368ed4f6e327a6ddd8831e544d0ca08f1345433e4ed204f8537", tx.Signature.S.Text(16))
+	})
 	t.Run("access-list", func(t *testing.T) {
 		key, _ := btcec.PrivKeyFromBytes(bytes.Repeat([]byte{0x01}, 32))
 		tx := (&types.Transaction{}).
@@ -79,7 +120,7 @@ func Test_ecSignTransaction(t *testing.T) {
 			SetGasPrice(big.NewInt(20000000000)).
 			SetNonce(9).
 			SetValue(big.NewInt(1000000000000000000))
-		err := ecSignTransaction(key.ToECDSA(), tx)
+		err := ecSignTransaction(key.ToECDSA(), tx, true)
 
 		require.NoError(t, err)
 		assert.Equal(t, "1", tx.Signature.V.Text(16))
@@ -96,7 +137,7 @@ func Test_ecSignTransaction(t *testing.T) {
 			SetMaxPriorityFeePerGas(big.NewInt(20000000000)).
 			SetNonce(9).
 			SetValue(big.NewInt(1000000000000000000))
-		err := ecSignTransaction(key.ToECDSA(), tx)
+		err := ecSignTransaction(key.ToECDSA(), tx, true)
 
 		require.NoError(t, err)
 		assert.Equal(t, "0", tx.Signature.V.Text(16))
@@ -105,6 +146,27 @@ func Test_ecSignTransaction(t *testing.T) {
 	})
 }
 
+func Test_ecSignTransaction_Blob(t *testing.T) {
+	key, _ := btcec.PrivKeyFromBytes(bytes.Repeat([]byte{0x01}, 32))
+	tx := (&types.Transaction{}).
+		SetType(types.BlobTxType).
+		SetTo(types.MustAddressFromHex("0x3535353535353535353535353535353535353535")).
+		SetGasLimit(21000).
+		SetMaxFeePerGas(big.NewInt(20000000000)).
+		SetMaxPriorityFeePerGas(big.NewInt(20000000000)).
+		SetMaxFeePerBlobGas(big.NewInt(1000000000)).
+		SetBlobVersionedHashes([]types.Hash{types.MustHashFromHex("0x0133333333333333333333333333333333333333333333333333333333333333", types.PadNone)}).
+		SetNonce(9).
+		SetValue(big.NewInt(1000000000000000000))
+	err := ecSignTransaction(key.ToECDSA(), tx, true)
+	require.NoError(t, err)
+	assert.True(t, tx.Signature.V.Cmp(big.NewInt(1)) <= 0)
+
+	addr, err := ecRecoverTransaction(tx)
+	require.NoError(t, err)
+	assert.Equal(t, "0x1a642f0e3c3af545e7acbd38b07251b3990914f1", addr.String())
+}
+
 func Test_ecRecoverHash(t *testing.T) {
 	addr, err := ecRecoverHash(
 		types.MustHashFromBytes(bytes.Repeat([]byte{0x02}, 32), types.PadNone),
@@ -171,6 +233,17 @@ func Test_ecRecoverTransaction(t *testing.T) {
 		require.NoError(t, err)
 		assert.Equal(t, "0x1a642f0e3c3af545e7acbd38b07251b3990914f1", addr.String())
 	})
+	t.Run("legacy-eip155-decoded", func(t *testing.T) {
+		// Signed transaction from the EIP-155 specification. The chain ID
+		// must be derived from the V value during decoding.
+		tx := new(types.Transaction)
+		_, err := tx.DecodeRLP(hexutil.MustHexToBytes("0xf86c098504a817c800825208943535353535353535353535353535353535353535880de0b6b3a76400008025a028ef61340bd939bc2195fe537567866003e1a15d3c71ff63e1590620aa636276a067cbe9d8997f761aecb703304b3800ccf555c9f3dc64214b297fb1966a3b6d83"))
+		require.NoError(t, err)
+		addr, err := ecRecoverTransaction(tx)
+
+		require.NoError(t, err)
+		assert.Equal(t, "0x9d8a62f656a8d1615c1294fd71e9cfb3e4855a4f", addr.String())
+	})
 	t.Run("access-list", func(t *testing.T) {
 		tx := (&types.Transaction{}).
 			SetType(types.AccessListTxType).
@@ -205,6 +278,48 @@ func Test_ecRecoverTransaction(t *testing.T) {
 			))
 		addr, err := ecRecoverTransaction(tx)
 
+		require.NoError(t, err)
+		assert.Equal(t, "0x1a642f0e3c3af545e7acbd38b07251b3990914f1", addr.String())
+	})
+	t.Run("dynamic-fee-json-yparity", func(t *testing.T) {
+		// Same transaction as above, as returned by a node that sets only
+		// the yParity field.
+		tx := new(types.OnChainTransaction)
+		require.NoError(t, json.Unmarshal([]byte(`{
+			"type": "0x2",
+			"to": "0x3535353535353535353535353535353535353535",
+			"gas": "0x5208",
+			"maxFeePerGas": "0x4a817c800",
+			"maxPriorityFeePerGas": "0x4a817c800",
+			"nonce": "0x9",
+			"value": "0xde0b6b3a7640000",
+			"yParity": "0x0",
+			"r": "0x62072d055f9ceb871a47f2d81aeb5aa34df50c625da16c6d0d57d232fa3cd152",
+			"s": "0x57fd88df7c85076f5729493be7e87f51b618a78bc89441ed741bdfdb9d1d5572"
+		}`), tx))
+		addr, err := ecRecoverTransaction(&tx.Transaction)
+
+		require.NoError(t, err)
+		assert.Equal(t, "0x1a642f0e3c3af545e7acbd38b07251b3990914f1", addr.String())
+	})
+	t.Run("dynamic-fee-json-round-trip", func(t *testing.T) {
+		key, _ := btcec.PrivKeyFromBytes(bytes.Repeat([]byte{0x01}, 32))
+		tx := (&types.Transaction{}).
+			SetType(types.DynamicFeeTxType).
+			SetChainID(1337).
+			SetTo(types.MustAddressFromHex("0x3535353535353535353535353535353535353535")).
+			SetGasLimit(21000).
+			SetMaxFeePerGas(big.NewInt(20000000000)).
+			SetMaxPriorityFeePerGas(big.NewInt(20000000000)).
+			SetNonce(9)
+		require.NoError(t, ecSignTransaction(key.ToECDSA(), tx, true))
+
+		j, err := json.Marshal(tx)
+		require.NoError(t, err)
+		decoded := new(types.Transaction)
+		require.NoError(t, json.Unmarshal(j, decoded))
+		addr, err := ecRecoverTransaction(decoded)
+
 		require.NoError(t, err)
 		assert.Equal(t, "0x1a642f0e3c3af545e7acbd38b07251b3990914f1", addr.String())
 	})
@@ -257,3 +372,23 @@ func Test_fuzzECSignMessage(t *testing.T) {
 		require.Equal(t, ECPublicKeyToAddress(key.PubKey().ToECDSA()), *addr)
 	}
 }
+
+func TestECRecoverer_AsMessage(t *testing.T) {
+	tx := (&types.Transaction{}).
+		SetType(types.LegacyTxType).
+		SetTo(types.MustAddressFromHex("0x3535353535353535353535353535353535353535")).
+		SetGasLimit(21000).
+		SetGasPrice(big.NewInt(20000000000)).
+		SetNonce(9).
+		SetValue(big.NewInt(1000000000000000000)).
+		SetSignature(types.SignatureFromVRS(
+			hexutil.MustHexToBigInt("1b"),
+			hexutil.MustHexToBigInt("2bfad43ba1b40e7f3ffb6342b1a6eecc700dd344fb0aba543aed5c10fd1a9470"),
+			hexutil.MustHexToBigInt("615bff48c483d368ed4f6e327a6ddd8831e544d0ca08f1345433e4ed204f8537"),
+		))
+	msg, err := tx.AsMessage(ECRecoverer, nil)
+
+	require.NoError(t, err)
+	assert.Equal(t, "0x1a642f0e3c3af545e7acbd38b07251b3990914f1", msg.From.String())
+	assert.Equal(t, big.NewInt(20000000000), msg.GasPrice)
+}
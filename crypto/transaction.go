@@ -1,7 +1,6 @@
 package crypto
 
 import (
-	"fmt"
 	"math/big"
 
 	"github.com/defiweb/go-rlp"
@@ -11,7 +10,7 @@ import (
 
 func signingHash(t *types.Transaction) (types.Hash, error) {
 	var (
-		chainID              = uint64(1)
+		chainID              = types.DefaultChainID
 		nonce                = uint64(0)
 		gasPrice             = big.NewInt(0)
 		gasLimit             = uint64(0)
@@ -20,6 +19,8 @@ func signingHash(t *types.Transaction) (types.Hash, error) {
 		to                   = ([]byte)(nil)
 		value                = big.NewInt(0)
 		accessList           = (types.AccessList)(nil)
+		maxFeePerBlobGas     = big.NewInt(0)
+		blobHashes           = types.BlobHashList(t.BlobVersionedHashes)
 	)
 	if t.ChainID != nil {
 		chainID = *t.ChainID
@@ -48,6 +49,9 @@ func signingHash(t *types.Transaction) (types.Hash, error) {
 	if t.AccessList != nil {
 		accessList = t.AccessList
 	}
+	if t.MaxFeePerBlobGas != nil {
+		maxFeePerBlobGas = t.MaxFeePerBlobGas
+	}
 	switch t.Type {
 	case types.LegacyTxType:
 		list := rlp.NewList(
@@ -103,7 +107,26 @@ func signingHash(t *types.Transaction) (types.Hash, error) {
 		}
 		bin = append([]byte{byte(t.Type)}, bin...)
 		return Keccak256(bin), nil
+	case types.BlobTxType:
+		bin, err := rlp.NewList(
+			rlp.NewUint(chainID),
+			rlp.NewUint(nonce),
+			rlp.NewBigInt(maxPriorityFeePerGas),
+			rlp.NewBigInt(maxFeePerGas),
+			rlp.NewUint(gasLimit),
+			rlp.NewBytes(to),
+			rlp.NewBigInt(value),
+			rlp.NewBytes(t.Input),
+			&accessList,
+			rlp.NewBigInt(maxFeePerBlobGas),
+			&blobHashes,
+		).EncodeRLP()
+		if err != nil {
+			return types.Hash{}, err
+		}
+		bin = append([]byte{byte(t.Type)}, bin...)
+		return Keccak256(bin), nil
 	default:
-		return types.Hash{}, fmt.Errorf("invalid transaction type: %d", t.Type)
+		return types.Hash{}, &types.UnsupportedTxTypeError{Type: t.Type}
 	}
 }
@@ -90,6 +90,17 @@ func main() {
 		timestamp uint64
 	)
 	multicall.Methods["aggregate3"].MustDecodeValues(b, &results)
+	for i, r := range results {
+		// If the AllowFailure flag is set, failed calls do not revert the
+		// whole aggregate3 call. Instead, the ReturnData contains the
+		// revert data of the failed call.
+		if !r.Success {
+			if err := abi.ToError(r.ReturnData); err != nil {
+				panic(fmt.Errorf("call %d failed: %w", i, err))
+			}
+			panic(fmt.Errorf("call %d failed", i))
+		}
+	}
 	multicall.Methods["getCurrentBlockGasLimit"].MustDecodeValues(results[0].ReturnData, &gasLimit)
 	multicall.Methods["getCurrentBlockTimestamp"].MustDecodeValues(results[1].ReturnData, &timestamp)
 
@@ -109,7 +109,7 @@ func (b *BoolFlagsValue) MapTo(_ abi.Mapper, dst any) error {
 
 func main() {
 	// Add custom type.
-	abi.Default.Types["BoolFlags"] = &BoolFlagsType{}
+	abi.Default.RegisterType("BoolFlags", &BoolFlagsType{})
 
 	// Generate calldata.
 	setFlags := abi.MustParseMethod("setFlags(BoolFlags flags)")
@@ -14,7 +14,7 @@ type Point struct {
 
 func main() {
 	// Add custom type.
-	abi.Default.Types["Point"] = abi.MustParseStruct("struct {int256 x; int256 y;}")
+	abi.Default.RegisterType("Point", abi.MustParseStruct("struct {int256 x; int256 y;}"))
 
 	// Generate calldata.
 	addTriangle := abi.MustParseMethod("addTriangle(Point a, Point b, Point c)")
@@ -52,6 +52,50 @@ func MustHexToBigInt(h string) *big.Int {
 	return x
 }
 
+// EncodeQuantity returns the hex representation of the given number as
+// a JSON-RPC QUANTITY. Quantities are prefixed with "0x" and have no leading
+// zeros, so zero is encoded as "0x0". A nil number is encoded as zero.
+//
+// Quantities cannot be negative, so an error is returned if x is negative.
+func EncodeQuantity(x *big.Int) (string, error) {
+	if x != nil && x.Sign() < 0 {
+		return "", fmt.Errorf("invalid quantity %s, negative number", x)
+	}
+	return BigIntToHex(x), nil
+}
+
+// DecodeQuantity decodes a JSON-RPC QUANTITY. Unlike HexToBigInt, it
+// requires the "0x" prefix and rejects empty values, leading zeros and
+// signs. Zero must be encoded as "0x0".
+func DecodeQuantity(h string) (*big.Int, error) {
+	if !Has0xPrefix(h) {
+		return nil, fmt.Errorf("invalid quantity %q, missing 0x prefix", h)
+	}
+	d := h[2:]
+	if len(d) == 0 {
+		return nil, fmt.Errorf("invalid quantity %q, empty number", h)
+	}
+	if len(d) > 1 && d[0] == '0' {
+		return nil, fmt.Errorf("invalid quantity %q, leading zero digits", h)
+	}
+	for i := 0; i < len(d); i++ {
+		if !IsHexDigit(d[i]) {
+			return nil, fmt.Errorf("invalid quantity %q, invalid hex digit", h)
+		}
+	}
+	x, _ := new(big.Int).SetString(d, 16)
+	return x, nil
+}
+
+// MustDecodeQuantity is like DecodeQuantity but panics on error.
+func MustDecodeQuantity(h string) *big.Int {
+	x, err := DecodeQuantity(h)
+	if err != nil {
+		panic(err)
+	}
+	return x
+}
+
 // BytesToHex returns the hex representation of the given bytes. The hex string
 // is always even-length and prefixed with "0x".
 func BytesToHex(b []byte) string {
@@ -91,6 +135,11 @@ func MustHexToBytes(h string) []byte {
 	return b
 }
 
+// IsHexDigit returns true if the given character is a hex digit.
+func IsHexDigit(c byte) bool {
+	return ('0' <= c && c <= '9') || ('a' <= c && c <= 'f') || ('A' <= c && c <= 'F')
+}
+
 // Has0xPrefix returns true if the given byte slice starts with "0x".
 func Has0xPrefix(h string) bool {
 	return len(h) >= 2 && h[0] == '0' && (h[1] == 'x' || h[1] == 'X')
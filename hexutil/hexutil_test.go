@@ -6,6 +6,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestBigIntToHex(t *testing.T) {
@@ -55,6 +56,66 @@ func TestHexToBigInt(t *testing.T) {
 	}
 }
 
+func TestEncodeQuantity(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    *big.Int
+		expected string
+	}{
+		{"nil input", nil, "0x0"},
+		{"zero value", big.NewInt(0), "0x0"},
+		{"single digit", big.NewInt(1), "0x1"},
+		{"odd number of digits", big.NewInt(0x400), "0x400"},
+		{"even number of digits", big.NewInt(0xff), "0xff"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, err := EncodeQuantity(tt.input)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, h)
+		})
+	}
+
+	_, err := EncodeQuantity(big.NewInt(-1))
+	assert.Error(t, err)
+}
+
+func TestDecodeQuantity(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected *big.Int
+		wantErr  bool
+	}{
+		{"zero", "0x0", big.NewInt(0), false},
+		{"single digit", "0x1", big.NewInt(1), false},
+		{"odd number of digits", "0x400", big.NewInt(0x400), false},
+		{"upper case", "0xFF", big.NewInt(0xff), false},
+		{"upper case prefix", "0XFF", big.NewInt(0xff), false},
+		{"empty string", "", nil, true},
+		{"empty number", "0x", nil, true},
+		{"missing prefix", "1a", nil, true},
+		{"leading zero", "0x01", nil, true},
+		{"leading zeros", "0x00", nil, true},
+		{"negative", "-0x1", nil, true},
+		{"sign after prefix", "0x-1", nil, true},
+		{"invalid hex", "0x1g", nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := DecodeQuantity(tt.input)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Zero(t, tt.expected.Cmp(result), "expected %s, got %s", tt.expected, result)
+		})
+	}
+}
+
 func TestBytesToHex(t *testing.T) {
 	tests := []struct {
 		name     string
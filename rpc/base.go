@@ -6,6 +6,7 @@ import (
 	"errors"
 	"fmt"
 	"math/big"
+	"sync"
 
 	"github.com/defiweb/go-eth/rpc/transport"
 	"github.com/defiweb/go-eth/types"
@@ -128,6 +129,18 @@ func (c *baseClient) GetStorageAt(ctx context.Context, account types.Address, ke
 	return &res, nil
 }
 
+// GetProof implements the RPC interface.
+func (c *baseClient) GetProof(ctx context.Context, account types.Address, keys []types.Hash, block types.BlockNumber) (*types.AccountProof, error) {
+	if keys == nil {
+		keys = []types.Hash{}
+	}
+	var res types.AccountProof
+	if err := c.transport.Call(ctx, &res, "eth_getProof", account, keys, block); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
 // GetTransactionCount implements the RPC interface.
 func (c *baseClient) GetTransactionCount(ctx context.Context, account types.Address, block types.BlockNumber) (uint64, error) {
 	var res types.Number
@@ -429,6 +442,15 @@ func (c *baseClient) MaxPriorityFeePerGas(ctx context.Context) (*big.Int, error)
 	return res.Big(), nil
 }
 
+// BlobBaseFee implements the RPC interface.
+func (c *baseClient) BlobBaseFee(ctx context.Context) (*big.Int, error) {
+	var res types.Number
+	if err := c.transport.Call(ctx, &res, "eth_blobBaseFee"); err != nil {
+		return nil, err
+	}
+	return res.Big(), nil
+}
+
 // SubscribeLogs implements the RPC interface.
 func (c *baseClient) SubscribeLogs(ctx context.Context, query *types.FilterLogsQuery) (<-chan types.Log, error) {
 	return subscribe[types.Log](ctx, c.transport, "logs", query)
@@ -436,7 +458,7 @@ func (c *baseClient) SubscribeLogs(ctx context.Context, query *types.FilterLogsQ
 
 // SubscribeNewHeads implements the RPC interface.
 func (c *baseClient) SubscribeNewHeads(ctx context.Context) (<-chan types.Block, error) {
-	return subscribe[types.Block](ctx, c.transport, "newHeads")
+	return subscribeWithDecoder(ctx, c.transport, decodeBlockHeader, "newHeads")
 }
 
 // SubscribeNewPendingTransactions implements the RPC interface.
@@ -444,41 +466,131 @@ func (c *baseClient) SubscribeNewPendingTransactions(ctx context.Context) (<-cha
 	return subscribe[types.Hash](ctx, c.transport, "newPendingTransactions")
 }
 
+// LogsSubscription works like SubscribeLogs, but returns a Subscription that
+// can be explicitly unsubscribed, independently of the context.
+//
+// Optional SubscribeOptions may be given to buffer the subscription
+// messages. Only the first options are used.
+func (c *baseClient) LogsSubscription(ctx context.Context, query *types.FilterLogsQuery, opts ...SubscribeOptions) (*Subscription[types.Log], error) {
+	return newSubscription(ctx, c.transport, firstSubscribeOptions(opts), decodeJSON[types.Log], "logs", query)
+}
+
+// NewHeadsSubscription works like SubscribeNewHeads, but returns a
+// Subscription that can be explicitly unsubscribed, independently of the
+// context.
+//
+// Optional SubscribeOptions may be given to buffer the subscription
+// messages. Only the first options are used.
+func (c *baseClient) NewHeadsSubscription(ctx context.Context, opts ...SubscribeOptions) (*Subscription[types.Block], error) {
+	return newSubscription(ctx, c.transport, firstSubscribeOptions(opts), decodeBlockHeader, "newHeads")
+}
+
+// NewPendingTransactionsSubscription works like
+// SubscribeNewPendingTransactions, but returns a Subscription that can be
+// explicitly unsubscribed, independently of the context.
+//
+// Optional SubscribeOptions may be given to buffer the subscription
+// messages, which is recommended because of the high rate of pending
+// transactions. Only the first options are used.
+func (c *baseClient) NewPendingTransactionsSubscription(ctx context.Context, opts ...SubscribeOptions) (*Subscription[types.Hash], error) {
+	return newSubscription(ctx, c.transport, firstSubscribeOptions(opts), decodeJSON[types.Hash], "newPendingTransactions")
+}
+
+// QueryLog is a log received by a subscription created with
+// SubscribeMultipleLogs.
+type QueryLog struct {
+	types.Log
+
+	// QueryIndex is the index of the query that matched the log.
+	QueryIndex int
+}
+
+// SubscribeMultipleLogs implements the RPC interface.
+func (c *baseClient) SubscribeMultipleLogs(ctx context.Context, queries []types.FilterLogsQuery, opts ...SubscribeOptions) (<-chan QueryLog, error) {
+	if len(queries) == 0 {
+		return nil, errors.New("rpc client: no log queries")
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	subs := make([]*Subscription[types.Log], 0, len(queries))
+	for i := range queries {
+		s, err := newSubscription(ctx, c.transport, firstSubscribeOptions(opts), decodeJSON[types.Log], "logs", &queries[i])
+		if err != nil {
+			cancel()
+			for _, s := range subs {
+				s.Unsubscribe()
+			}
+			return nil, fmt.Errorf("rpc client: cannot subscribe to logs of query %d: %w", i, err)
+		}
+		subs = append(subs, s)
+	}
+	var (
+		ch = make(chan QueryLog)
+		wg sync.WaitGroup
+	)
+	wg.Add(len(subs))
+	for i, s := range subs {
+		go func(idx int, s *Subscription[types.Log]) {
+			defer wg.Done()
+			// If one of the subscriptions ends, for example because the
+			// transport closed it, the remaining ones are ended as well,
+			// so the consumer is notified by closing the channel.
+			defer cancel()
+			defer s.Unsubscribe()
+			for log := range s.Events() {
+				select {
+				case <-ctx.Done():
+					return
+				case ch <- QueryLog{Log: log, QueryIndex: idx}:
+				}
+			}
+		}(i, s)
+	}
+	go func() {
+		wg.Wait()
+		cancel()
+		close(ch)
+	}()
+	return ch, nil
+}
+
 // subscribe creates a subscription to the given method and returns a channel
 // that will receive the subscription messages. The messages are unmarshalled
 // to the T type. The subscription is unsubscribed and channel closed when the
 // context is cancelled.
-func subscribe[T any](ctx context.Context, t transport.Transport, method string, params ...any) (chan T, error) {
-	st, ok := t.(transport.SubscriptionTransport)
-	if !ok {
-		return nil, errors.New("transport does not support subscriptions")
-	}
-	rawCh, subID, err := st.Subscribe(ctx, method, params...)
+func subscribe[T any](ctx context.Context, t transport.Transport, method string, params ...any) (<-chan T, error) {
+	return subscribeWithDecoder(ctx, t, decodeJSON[T], method, params...)
+}
+
+// subscribeWithDecoder works like subscribe, but it uses the given function
+// to decode the subscription messages.
+func subscribeWithDecoder[T any](ctx context.Context, t transport.Transport, decode func(json.RawMessage) (T, error), method string, params ...any) (<-chan T, error) {
+	s, err := newSubscription(ctx, t, SubscribeOptions{}, decode, method, params...)
 	if err != nil {
 		return nil, err
 	}
-	msgCh := make(chan T)
-	go subscriptionRoutine(ctx, st, subID, rawCh, msgCh)
-	return msgCh, nil
-}
-
-//nolint:errcheck
-func subscriptionRoutine[T any](ctx context.Context, t transport.SubscriptionTransport, subID string, rawCh chan json.RawMessage, msgCh chan T) {
-	defer close(msgCh)
-	defer t.Unsubscribe(ctx, subID)
-	for {
-		select {
-		case <-ctx.Done():
-			return
-		case raw, ok := <-rawCh:
-			if !ok {
-				return
-			}
-			var msg T
-			if err := json.Unmarshal(raw, &msg); err != nil {
-				continue
-			}
-			msgCh <- msg
-		}
+	return s.Events(), nil
+}
+
+// firstSubscribeOptions returns the first options from the given list or the
+// zero options if the list is empty.
+func firstSubscribeOptions(opts []SubscribeOptions) SubscribeOptions {
+	if len(opts) == 0 {
+		return SubscribeOptions{}
 	}
+	return opts[0]
+}
+
+// decodeJSON unmarshals the given JSON message to the T type.
+func decodeJSON[T any](raw json.RawMessage) (T, error) {
+	var msg T
+	err := json.Unmarshal(raw, &msg)
+	return msg, err
+}
+
+// decodeBlockHeader decodes a block header sent by the newHeads subscription.
+// Transactions are not decoded, because they are not part of the header.
+func decodeBlockHeader(raw json.RawMessage) (types.Block, error) {
+	var block types.Block
+	err := block.UnmarshalHeaderJSON(raw)
+	return block, err
 }
@@ -4,6 +4,8 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"io"
 	"math/big"
 	"net/http"
@@ -396,6 +398,69 @@ func TestBaseClient_GetStorageAt(t *testing.T) {
 	assert.Equal(t, types.MustHashFromHex("0x3333333333333333333333333333333333333333333333333333333333333333", types.PadNone), *storage)
 }
 
+const mockGetProofRequest = `
+	{
+	  "jsonrpc": "2.0",
+	  "id": 1,
+	  "method": "eth_getProof",
+	  "params": [
+		"0x1111111111111111111111111111111111111111",
+		["0x2222222222222222222222222222222222222222222222222222222222222222"],
+		"0x1"
+	  ]
+	}
+`
+
+const mockGetProofResponse = `
+	{
+	  "jsonrpc": "2.0",
+	  "id": 1,
+	  "result": {
+		"address": "0x1111111111111111111111111111111111111111",
+		"accountProof": ["0xf8518080"],
+		"balance": "0x64",
+		"codeHash": "0xc5d2460186f7233c927e7db2dcc703c0e500b653ca82273b7bfad8045d85a470",
+		"nonce": "0x1",
+		"storageHash": "0x56e81f171bcc55a6ff8345e692c0f86e5b48e01b996cadc001622fb5e363b421",
+		"storageProof": [
+		  {
+			"key": "0x2222222222222222222222222222222222222222222222222222222222222222",
+			"value": "0x0",
+			"proof": []
+		  }
+		]
+	  }
+	}
+`
+
+func TestBaseClient_GetProof(t *testing.T) {
+	httpMock := newHTTPMock()
+	client := &baseClient{transport: httpMock}
+
+	httpMock.ResponseMock = &http.Response{
+		StatusCode: 200,
+		Body:       io.NopCloser(bytes.NewBufferString(mockGetProofResponse)),
+	}
+
+	proof, err := client.GetProof(
+		context.Background(),
+		types.MustAddressFromHex("0x1111111111111111111111111111111111111111"),
+		[]types.Hash{types.MustHashFromHex("0x2222222222222222222222222222222222222222222222222222222222222222", types.PadNone)},
+		types.MustBlockNumberFromHex("0x1"),
+	)
+
+	require.NoError(t, err)
+	assert.JSONEq(t, mockGetProofRequest, readBody(httpMock.Request))
+	assert.Equal(t, types.MustAddressFromHex("0x1111111111111111111111111111111111111111"), proof.Address)
+	assert.Equal(t, []types.Bytes{{0xf8, 0x51, 0x80, 0x80}}, proof.AccountProof)
+	assert.Equal(t, big.NewInt(100), proof.Balance)
+	assert.Equal(t, types.EmptyCodeHash, proof.CodeHash)
+	assert.Equal(t, uint64(1), proof.Nonce)
+	assert.Equal(t, types.EmptyRootHash, proof.StorageHash)
+	require.Len(t, proof.StorageProof, 1)
+	assert.Equal(t, big.NewInt(0), proof.StorageProof[0].Value)
+}
+
 const mockGetTransactionCountRequest = `
 	{
 	  "jsonrpc": "2.0",
@@ -1296,6 +1361,7 @@ func TestBaseClient_GetTransactionReceipt(t *testing.T) {
 	assert.JSONEq(t, mockGetTransactionReceiptRequest, readBody(httpMock.Request))
 	assert.Equal(t, types.MustHashFromHex("0xaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa", types.PadNone), receipt.TransactionHash)
 	assert.Equal(t, uint64(17), receipt.TransactionIndex)
+	assert.Equal(t, types.LegacyTxType, receipt.Type)
 	assert.Equal(t, types.MustHashFromHex("0x1111111111111111111111111111111111111111111111111111111111111111", types.PadNone), receipt.BlockHash)
 	assert.Equal(t, big.NewInt(0x2222), receipt.BlockNumber)
 	assert.Equal(t, (*types.Address)(nil), receipt.ContractAddress)
@@ -1851,6 +1917,38 @@ func TestBaseClient_MaxPriorityFeePerGas(t *testing.T) {
 	assert.Equal(t, hexToBigInt("0x1"), gasPrice)
 }
 
+const mockBlobBaseFeeRequest = `
+	{
+	  "jsonrpc": "2.0",
+	  "id": 1,
+	  "method": "eth_blobBaseFee",
+	  "params": []
+	}
+`
+
+const mockBlobBaseFeeResponse = `
+	{
+	  "jsonrpc": "2.0",
+	  "id": 1,
+	  "result": "0x2"
+	}
+`
+
+func TestBaseClient_BlobBaseFee(t *testing.T) {
+	httpMock := newHTTPMock()
+	client := &baseClient{transport: httpMock}
+
+	httpMock.ResponseMock = &http.Response{
+		StatusCode: 200,
+		Body:       io.NopCloser(bytes.NewBufferString(mockBlobBaseFeeResponse)),
+	}
+
+	blobBaseFee, err := client.BlobBaseFee(context.Background())
+	require.NoError(t, err)
+	assert.JSONEq(t, mockBlobBaseFeeRequest, readBody(httpMock.Request))
+	assert.Equal(t, hexToBigInt("0x2"), blobBaseFee)
+}
+
 const mockSubscribeLogsResponse = `
 	{
 	  "address": "0x3333333333333333333333333333333333333333",
@@ -1923,6 +2021,182 @@ func TestBaseClient_SubscribeLogs(t *testing.T) {
 	}, time.Second, 10*time.Millisecond)
 }
 
+func TestBaseClient_LogsSubscription_Unsubscribe(t *testing.T) {
+	streamMock := newStreamMock(t)
+	client := &baseClient{transport: streamMock}
+
+	rawCh := make(chan json.RawMessage)
+	query := &types.FilterLogsQuery{}
+	streamMock.SubscribeMocks = append(streamMock.SubscribeMocks, subscribeMock{
+		ArgMethod: "logs",
+		ArgParams: []any{query},
+		RetCh:     rawCh,
+		RetID:     "1",
+	})
+	streamMock.UnsubscribeMocks = append(streamMock.UnsubscribeMocks, unsubscribeMock{
+		ArgID: "1",
+	})
+
+	sub, err := client.LogsSubscription(context.Background(), query)
+	require.NoError(t, err)
+
+	// Send a message that is never read, so the reader goroutine blocks
+	// on the events channel.
+	rawCh <- json.RawMessage(mockSubscribeLogsResponse)
+
+	// Unsubscribe must stop the goroutine without canceling the context.
+	sub.Unsubscribe()
+	sub.Unsubscribe()
+	assert.Empty(t, streamMock.UnsubscribeMocks)
+	_, ok := <-sub.Events()
+	assert.False(t, ok)
+}
+
+func TestBaseClient_SubscribeMultipleLogs(t *testing.T) {
+	streamMock := newStreamMock(t)
+	client := &baseClient{transport: streamMock}
+
+	rawCh1 := make(chan json.RawMessage)
+	rawCh2 := make(chan json.RawMessage)
+	queries := []types.FilterLogsQuery{
+		{Address: []types.Address{types.MustAddressFromHex("0x1111111111111111111111111111111111111111")}},
+		{Address: []types.Address{types.MustAddressFromHex("0x2222222222222222222222222222222222222222")}},
+	}
+	streamMock.SubscribeMocks = append(streamMock.SubscribeMocks,
+		subscribeMock{ArgMethod: "logs", ArgParams: []any{&queries[0]}, RetCh: rawCh1, RetID: "1"},
+		subscribeMock{ArgMethod: "logs", ArgParams: []any{&queries[1]}, RetCh: rawCh2, RetID: "2"},
+	)
+	streamMock.UnsubscribeMocks = append(streamMock.UnsubscribeMocks,
+		unsubscribeMock{ArgID: "1"},
+		unsubscribeMock{ArgID: "2"},
+	)
+
+	ctx, ctxCancel := context.WithCancel(context.Background())
+	defer ctxCancel()
+	logsCh, err := client.SubscribeMultipleLogs(ctx, queries)
+	require.NoError(t, err)
+	require.NotNil(t, logsCh)
+
+	// Logs are tagged with the index of the query.
+	rawCh2 <- json.RawMessage(mockSubscribeLogsResponse)
+	log := <-logsCh
+	assert.Equal(t, 1, log.QueryIndex)
+	assert.Equal(t, "0x3333333333333333333333333333333333333333", log.Address.String())
+	rawCh1 <- json.RawMessage(mockSubscribeLogsResponse)
+	log = <-logsCh
+	assert.Equal(t, 0, log.QueryIndex)
+
+	// Canceling the context closes the channel.
+	ctxCancel()
+	for range logsCh {
+	}
+	assert.Empty(t, streamMock.UnsubscribeMocks)
+}
+
+func TestBaseClient_SubscribeMultipleLogs_Closed(t *testing.T) {
+	streamMock := newStreamMock(t)
+	client := &baseClient{transport: streamMock}
+
+	rawCh1 := make(chan json.RawMessage)
+	rawCh2 := make(chan json.RawMessage)
+	queries := []types.FilterLogsQuery{{}, {}}
+	streamMock.SubscribeMocks = append(streamMock.SubscribeMocks,
+		subscribeMock{ArgMethod: "logs", ArgParams: []any{&queries[0]}, RetCh: rawCh1, RetID: "1"},
+		subscribeMock{ArgMethod: "logs", ArgParams: []any{&queries[1]}, RetCh: rawCh2, RetID: "2"},
+	)
+	streamMock.UnsubscribeMocks = append(streamMock.UnsubscribeMocks,
+		unsubscribeMock{ArgID: "1"},
+		unsubscribeMock{ArgID: "2"},
+	)
+
+	logsCh, err := client.SubscribeMultipleLogs(context.Background(), queries, SubscribeOptions{BufferSize: 1})
+	require.NoError(t, err)
+
+	// Closing one subscription ends all of them and closes the channel.
+	close(rawCh1)
+	for range logsCh {
+	}
+	assert.Empty(t, streamMock.UnsubscribeMocks)
+}
+
+func TestBaseClient_SubscribeMultipleLogs_Error(t *testing.T) {
+	streamMock := newStreamMock(t)
+	client := &baseClient{transport: streamMock}
+
+	queries := []types.FilterLogsQuery{{}, {}}
+	streamMock.SubscribeMocks = append(streamMock.SubscribeMocks,
+		subscribeMock{ArgMethod: "logs", ArgParams: []any{&queries[0]}, RetCh: make(chan json.RawMessage), RetID: "1"},
+		subscribeMock{ArgMethod: "logs", ArgParams: []any{&queries[1]}, RetErr: errors.New("subscribe failed")},
+	)
+	streamMock.UnsubscribeMocks = append(streamMock.UnsubscribeMocks, unsubscribeMock{ArgID: "1"})
+
+	// Already created subscriptions are unsubscribed on error.
+	_, err := client.SubscribeMultipleLogs(context.Background(), queries)
+	require.Error(t, err)
+	assert.Empty(t, streamMock.UnsubscribeMocks)
+
+	_, err = client.SubscribeMultipleLogs(context.Background(), nil)
+	assert.Error(t, err)
+}
+
+func TestBaseClient_NewPendingTransactionsSubscription_Buffered(t *testing.T) {
+	h1 := types.MustHashFromHex("0x1111111111111111111111111111111111111111111111111111111111111111", types.PadNone)
+	h2 := types.MustHashFromHex("0x2222222222222222222222222222222222222222222222222222222222222222", types.PadNone)
+	h3 := types.MustHashFromHex("0x3333333333333333333333333333333333333333333333333333333333333333", types.PadNone)
+	tests := []struct {
+		dropOldest bool
+		want       []types.Hash
+	}{
+		{dropOldest: false, want: []types.Hash{h1, h2}},
+		{dropOldest: true, want: []types.Hash{h2, h3}},
+	}
+	for n, tt := range tests {
+		t.Run(fmt.Sprintf("case-%d", n+1), func(t *testing.T) {
+			streamMock := newStreamMock(t)
+			client := &baseClient{transport: streamMock}
+
+			rawCh := make(chan json.RawMessage)
+			streamMock.SubscribeMocks = append(streamMock.SubscribeMocks, subscribeMock{
+				ArgMethod: "newPendingTransactions",
+				RetCh:     rawCh,
+				RetID:     "1",
+			})
+			streamMock.UnsubscribeMocks = append(streamMock.UnsubscribeMocks, unsubscribeMock{
+				ArgID: "1",
+			})
+
+			sub, err := client.NewPendingTransactionsSubscription(
+				context.Background(),
+				SubscribeOptions{BufferSize: 2, DropOldest: tt.dropOldest},
+			)
+			require.NoError(t, err)
+
+			// Messages are not read, so the third one must be dropped
+			// without blocking the reader goroutine.
+			for _, h := range []types.Hash{h1, h2, h3} {
+				rawCh <- json.RawMessage(`"` + h.String() + `"`)
+			}
+			assert.Eventually(t, func() bool {
+				return sub.Dropped() == 1
+			}, time.Second, 10*time.Millisecond)
+
+			sub.Unsubscribe()
+			var got []types.Hash
+			for h := range sub.Events() {
+				got = append(got, h)
+			}
+			assert.Equal(t, tt.want, got)
+			assert.Empty(t, streamMock.UnsubscribeMocks)
+		})
+	}
+}
+
+func TestBaseClient_LogsSubscription_NegativeBufferSize(t *testing.T) {
+	client := &baseClient{transport: newStreamMock(t)}
+	_, err := client.LogsSubscription(context.Background(), &types.FilterLogsQuery{}, SubscribeOptions{BufferSize: -1})
+	assert.Error(t, err)
+}
+
 const mockSubscribeNewHeadsResponse = `
 	{
 	  "number": "0x11",
@@ -1942,6 +2216,7 @@ const mockSubscribeNewHeadsResponse = `
 	  "gasLimit": "0xeeeeee",
 	  "gasUsed": "0xffffff",
 	  "timestamp": "0x54e34e8e",
+	  "baseFeePerGas": "0x7",
 	  "transactions": [
 	    {
 	  	"hash": "0x1111111111111111111111111111111111111111111111111111111111111111",
@@ -2012,18 +2287,14 @@ func TestBaseClient_SubscribeNewHeads(t *testing.T) {
 	assert.Equal(t, hexToBigInt("0xeeeeee").Uint64(), block.GasLimit)
 	assert.Equal(t, hexToBigInt("0xffffff").Uint64(), block.GasUsed)
 	assert.Equal(t, int64(1424182926), block.Timestamp.Unix())
-	require.Len(t, block.Transactions, 1)
+	assert.Equal(t, big.NewInt(7), block.BaseFeePerGas)
 	require.Len(t, block.Uncles, 1)
-	assert.Equal(t, types.MustHashFromHexPtr("0x1111111111111111111111111111111111111111111111111111111111111111", types.PadNone), block.Transactions[0].Hash)
-	assert.Equal(t, uint64(0x22), *block.Transactions[0].Nonce)
-	assert.Equal(t, types.MustAddressFromHexPtr("0x5555555555555555555555555555555555555555"), block.Transactions[0].From)
-	assert.Equal(t, types.MustAddressFromHexPtr("0x6666666666666666666666666666666666666666"), block.Transactions[0].To)
-	assert.Equal(t, big.NewInt(10000000000), block.Transactions[0].Value)
-	assert.Equal(t, uint64(30400), *block.Transactions[0].GasLimit)
-	assert.Equal(t, big.NewInt(10000000000000), block.Transactions[0].GasPrice)
-	assert.Equal(t, hexToBytes("0x777777777777"), block.Transactions[0].Input)
 	assert.Equal(t, types.MustHashFromHex("0x8888888888888888888888888888888888888888888888888888888888888888", types.PadNone), block.Uncles[0])
 
+	// Subscription is header-only, transactions must not be decoded.
+	assert.Nil(t, block.Transactions)
+	assert.Nil(t, block.TransactionHashes)
+
 	ctxCancel()
 	assert.Eventually(t, func() bool {
 		return len(streamMock.UnsubscribeMocks) == 0
@@ -2071,6 +2342,178 @@ func TestClient_SubscribeNewPendingTransactions(t *testing.T) {
 	}, time.Second, 10*time.Millisecond)
 }
 
+func TestBaseClient_BlockTags(t *testing.T) {
+	const (
+		addr = `"0x1111111111111111111111111111111111111111"`
+		hash = `"0x2222222222222222222222222222222222222222222222222222222222222222"`
+	)
+	var (
+		address = types.MustAddressFromHex("0x1111111111111111111111111111111111111111")
+		key     = types.MustHashFromHex("0x2222222222222222222222222222222222222222222222222222222222222222", types.PadNone)
+		call    = &types.Call{To: &address}
+	)
+	tests := []struct {
+		method string
+		params func(tag string) string
+		result string
+		call   func(c *baseClient, block types.BlockNumber) error
+	}{
+		{
+			method: "eth_getBalance",
+			params: func(tag string) string { return `[` + addr + `,` + tag + `]` },
+			result: `"0x1"`,
+			call: func(c *baseClient, block types.BlockNumber) error {
+				_, err := c.GetBalance(context.Background(), address, block)
+				return err
+			},
+		},
+		{
+			method: "eth_getStorageAt",
+			params: func(tag string) string { return `[` + addr + `,` + hash + `,` + tag + `]` },
+			result: hash,
+			call: func(c *baseClient, block types.BlockNumber) error {
+				_, err := c.GetStorageAt(context.Background(), address, key, block)
+				return err
+			},
+		},
+		{
+			method: "eth_getProof",
+			params: func(tag string) string { return `[` + addr + `,[` + hash + `],` + tag + `]` },
+			result: `{}`,
+			call: func(c *baseClient, block types.BlockNumber) error {
+				_, err := c.GetProof(context.Background(), address, []types.Hash{key}, block)
+				return err
+			},
+		},
+		{
+			method: "eth_getTransactionCount",
+			params: func(tag string) string { return `[` + addr + `,` + tag + `]` },
+			result: `"0x1"`,
+			call: func(c *baseClient, block types.BlockNumber) error {
+				_, err := c.GetTransactionCount(context.Background(), address, block)
+				return err
+			},
+		},
+		{
+			method: "eth_getBlockTransactionCountByNumber",
+			params: func(tag string) string { return `[` + tag + `]` },
+			result: `"0x1"`,
+			call: func(c *baseClient, block types.BlockNumber) error {
+				_, err := c.GetBlockTransactionCountByNumber(context.Background(), block)
+				return err
+			},
+		},
+		{
+			method: "eth_getUncleCountByBlockNumber",
+			params: func(tag string) string { return `[` + tag + `]` },
+			result: `"0x1"`,
+			call: func(c *baseClient, block types.BlockNumber) error {
+				_, err := c.GetUncleCountByBlockNumber(context.Background(), block)
+				return err
+			},
+		},
+		{
+			method: "eth_getCode",
+			params: func(tag string) string { return `[` + addr + `,` + tag + `]` },
+			result: `"0x00"`,
+			call: func(c *baseClient, block types.BlockNumber) error {
+				_, err := c.GetCode(context.Background(), address, block)
+				return err
+			},
+		},
+		{
+			method: "eth_call",
+			params: func(tag string) string { return `[{"to":` + addr + `},` + tag + `]` },
+			result: `"0x00"`,
+			call: func(c *baseClient, block types.BlockNumber) error {
+				_, _, err := c.Call(context.Background(), call, block)
+				return err
+			},
+		},
+		{
+			method: "eth_estimateGas",
+			params: func(tag string) string { return `[{"to":` + addr + `},` + tag + `]` },
+			result: `"0x5208"`,
+			call: func(c *baseClient, block types.BlockNumber) error {
+				_, _, err := c.EstimateGas(context.Background(), call, block)
+				return err
+			},
+		},
+		{
+			method: "eth_getBlockByNumber",
+			params: func(tag string) string { return `[` + tag + `,false]` },
+			result: `{}`,
+			call: func(c *baseClient, block types.BlockNumber) error {
+				_, err := c.BlockByNumber(context.Background(), block, false)
+				return err
+			},
+		},
+		{
+			method: "eth_getTransactionByBlockNumberAndIndex",
+			params: func(tag string) string { return `[` + tag + `,"0x0"]` },
+			result: `{}`,
+			call: func(c *baseClient, block types.BlockNumber) error {
+				_, err := c.GetTransactionByBlockNumberAndIndex(context.Background(), block, 0)
+				return err
+			},
+		},
+		{
+			method: "eth_getBlockReceipts",
+			params: func(tag string) string { return `[` + tag + `]` },
+			result: `[]`,
+			call: func(c *baseClient, block types.BlockNumber) error {
+				_, err := c.GetBlockReceipts(context.Background(), block)
+				return err
+			},
+		},
+		{
+			method: "eth_getUncleByBlockNumberAndIndex",
+			params: func(tag string) string { return `[` + tag + `,"0x0"]` },
+			result: `{}`,
+			call: func(c *baseClient, block types.BlockNumber) error {
+				_, err := c.GetUncleByBlockNumberAndIndex(context.Background(), block, 0)
+				return err
+			},
+		},
+		{
+			method: "eth_getLogs",
+			params: func(tag string) string {
+				return `[{"address":null,"fromBlock":` + tag + `,"toBlock":` + tag + `,"topics":null}]`
+			},
+			result: `[]`,
+			call: func(c *baseClient, block types.BlockNumber) error {
+				_, err := c.GetLogs(context.Background(), types.NewFilterLogsQuery().SetFromBlock(&block).SetToBlock(&block))
+				return err
+			},
+		},
+		{
+			method: "eth_newFilter",
+			params: func(tag string) string {
+				return `[{"address":null,"fromBlock":` + tag + `,"toBlock":` + tag + `,"topics":null}]`
+			},
+			result: `"0x1"`,
+			call: func(c *baseClient, block types.BlockNumber) error {
+				_, err := c.NewFilter(context.Background(), types.NewFilterLogsQuery().SetFromBlock(&block).SetToBlock(&block))
+				return err
+			},
+		},
+	}
+	for _, tag := range []types.BlockNumber{types.SafeBlockNumber, types.FinalizedBlockNumber} {
+		for _, tt := range tests {
+			t.Run(tt.method+"/"+tag.String(), func(t *testing.T) {
+				callMock := newCallMock(t, callMockEntry{
+					ArgMethod: tt.method,
+					ArgParams: tt.params(`"` + tag.String() + `"`),
+					RetResult: tt.result,
+				})
+				client := &baseClient{transport: callMock}
+				require.NoError(t, tt.call(client, tag))
+				assert.Empty(t, callMock.CallMocks)
+			})
+		}
+	}
+}
+
 func readBody(r *http.Request) string {
 	body, _ := io.ReadAll(r.Body)
 	return string(body)
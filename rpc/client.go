@@ -2,8 +2,14 @@ package rpc
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"math/big"
+	"time"
 
+	"github.com/defiweb/go-eth/abi"
+	"github.com/defiweb/go-eth/crypto"
 	"github.com/defiweb/go-eth/rpc/transport"
 	"github.com/defiweb/go-eth/types"
 	"github.com/defiweb/go-eth/wallet"
@@ -13,9 +19,14 @@ import (
 type Client struct {
 	baseClient
 
-	keys        map[types.Address]wallet.Key
-	defaultAddr *types.Address
-	txModifiers []TXModifier
+	keys              map[types.Address]wallet.Signer
+	defaultAddr       *types.Address
+	chainID           *uint64
+	txModifiers       []TXModifier
+	omitCallFrom      bool
+	noCallDefaultAddr bool
+	validateTX        bool
+	logger            Logger
 }
 
 type ClientOptions func(c *Client) error
@@ -26,6 +37,17 @@ type TXModifier interface {
 	Modify(ctx context.Context, client RPC, tx *types.Transaction) error
 }
 
+// TXOnlyModifier is an optional interface for transaction modifiers that only
+// set fields that are not a part of a call, such as the nonce or the chain ID.
+// Such modifiers are not applied by Client.PrepareCall.
+type TXOnlyModifier interface {
+	TXModifier
+
+	// TXOnly returns true if the modifier only sets transaction-specific
+	// fields.
+	TXOnly() bool
+}
+
 type TXModifierFunc func(ctx context.Context, client RPC, tx *types.Transaction) error
 
 func (f TXModifierFunc) Modify(ctx context.Context, client RPC, tx *types.Transaction) error {
@@ -52,7 +74,25 @@ func WithTransport(transport transport.Transport) ClientOptions {
 func WithKeys(keys ...wallet.Key) ClientOptions {
 	return func(c *Client) error {
 		for _, k := range keys {
-			c.keys[k.Address()] = k
+			if s, ok := k.(wallet.Signer); ok {
+				c.keys[k.Address()] = s
+				continue
+			}
+			c.keys[k.Address()] = keySigner{k}
+		}
+		return nil
+	}
+}
+
+// WithSigner works like WithKeys, but accepts signers that are only able to
+// sign hashes, such as KMS, HSM or remote signers.
+//
+// Messages passed to the Sign method are prefixed as defined in EIP-191,
+// hashed and then signed using the SignHash method.
+func WithSigner(signers ...wallet.Signer) ClientOptions {
+	return func(c *Client) error {
+		for _, s := range signers {
+			c.keys[s.Address()] = s
 		}
 		return nil
 	}
@@ -64,6 +104,9 @@ func WithKeys(keys ...wallet.Key) ClientOptions {
 //   - SendTransaction
 //   - Call
 //   - EstimateGas
+//
+// The WithoutCallDefaultAddress option may be used to not set the default
+// address for the Call method.
 func WithDefaultAddress(addr types.Address) ClientOptions {
 	return func(c *Client) error {
 		c.defaultAddr = &addr
@@ -71,6 +114,64 @@ func WithDefaultAddress(addr types.Address) ClientOptions {
 	}
 }
 
+// WithChainID sets the chain ID of transactions that do not have one. The
+// chain ID is set before the transaction modifiers are applied, and the
+// chain ID of transactions that already have one is never changed. A
+// txmodifier.ChainIDProvider does not override it unless its Replace option
+// is set. To verify the chain ID against the node, use a
+// txmodifier.ChainIDProvider with the Verify option.
+//
+// The chain ID 0 disables EIP-155 replay protection of legacy transactions
+// signed using keys provided by the WithKeys or WithSigner options. The V
+// value of such signatures is 27 or 28. It may be required by chains that do
+// not support EIP-155, but such transactions can be replayed on other
+// chains. Typed transactions always include the chain ID, so the chain ID 0
+// should not be used for them.
+func WithChainID(chainID uint64) ClientOptions {
+	return func(c *Client) error {
+		c.chainID = &chainID
+		return nil
+	}
+}
+
+// WithOmitCallFrom removes the "from" field from calls made using the Call
+// method, even if it is set on the call or a default address is configured
+// using WithDefaultAddress.
+//
+// Some providers behave differently or bill differently when the "from"
+// field is set. This option may be used to make anonymous read calls.
+// EstimateGas is not affected.
+func WithOmitCallFrom() ClientOptions {
+	return func(c *Client) error {
+		c.omitCallFrom = true
+		return nil
+	}
+}
+
+// WithoutCallDefaultAddress disables setting the default address configured
+// using WithDefaultAddress on calls made using the Call method. Unlike
+// WithOmitCallFrom, the "from" field is still sent if it is set on the call.
+//
+// It may be used when the default address is intended only for sending
+// transactions, and read calls should be made without a sender unless one is
+// given explicitly.
+func WithoutCallDefaultAddress() ClientOptions {
+	return func(c *Client) error {
+		c.noCallDefaultAddr = true
+		return nil
+	}
+}
+
+// WithLogger sets a logger that logs every RPC call performed by the client,
+// including the method, parameters, duration and the truncated response or
+// error.
+func WithLogger(logger Logger) ClientOptions {
+	return func(c *Client) error {
+		c.logger = logger
+		return nil
+	}
+}
+
 // WithTXModifiers allows to modify the transaction before it is signed and
 // sent to the node.
 //
@@ -82,10 +183,25 @@ func WithTXModifiers(modifiers ...TXModifier) ClientOptions {
 	}
 }
 
+// WithTXValidation enables validation of transactions using the
+// types.Transaction.Validate method before they are signed or sent to the
+// node. Transactions are validated after the modifiers are applied.
+//
+// The following methods are affected:
+//   - PrepareTransaction
+//   - SignTransaction
+//   - SendTransaction
+func WithTXValidation() ClientOptions {
+	return func(c *Client) error {
+		c.validateTX = true
+		return nil
+	}
+}
+
 // NewClient creates a new RPC client.
 // The WithTransport option is required.
 func NewClient(opts ...ClientOptions) (*Client, error) {
-	c := &Client{keys: make(map[types.Address]wallet.Key)}
+	c := &Client{keys: make(map[types.Address]wallet.Signer)}
 	for _, opt := range opts {
 		if err := opt(c); err != nil {
 			return nil, err
@@ -94,6 +210,9 @@ func NewClient(opts ...ClientOptions) (*Client, error) {
 	if c.transport == nil {
 		return nil, fmt.Errorf("rpc client: transport is required")
 	}
+	if c.logger != nil {
+		c.transport = newLoggingTransport(c.transport, c.logger)
+	}
 	return c, nil
 }
 
@@ -115,7 +234,10 @@ func (c *Client) Sign(ctx context.Context, account types.Address, data []byte) (
 		return c.baseClient.Sign(ctx, account, data)
 	}
 	if key := c.findKey(&account); key != nil {
-		return key.SignMessage(ctx, data)
+		if k, ok := key.(wallet.Key); ok {
+			return k.SignMessage(ctx, data)
+		}
+		return key.SignHash(ctx, crypto.Keccak256(crypto.AddMessagePrefix(data)))
 	}
 	return nil, fmt.Errorf("rpc client: no key found for address %s", account)
 }
@@ -148,6 +270,57 @@ func (c *Client) SendTransaction(ctx context.Context, tx *types.Transaction) (*t
 	if err != nil {
 		return nil, nil, err
 	}
+	return c.sendPreparedTransaction(ctx, tx)
+}
+
+// DeployContract deploys a contract using the given bytecode. If the
+// constructor is not nil, the constructor arguments are ABI encoded and
+// appended to the bytecode.
+//
+// The contract is deployed from the default address, which must be set using
+// the WithDefaultAddress option. If the nonce is not set by the transaction
+// modifiers, the pending nonce of the default address is used.
+//
+// It returns the hash of the deployment transaction and the address of the
+// deployed contract, which is computed from the sender address and nonce.
+func (c *Client) DeployContract(ctx context.Context, bytecode []byte, constructor *abi.Constructor, args ...any) (types.Hash, types.Address, error) {
+	input := bytecode
+	switch {
+	case constructor != nil:
+		var err error
+		if input, err = constructor.EncodeArgs(bytecode, args...); err != nil {
+			return types.Hash{}, types.Address{}, fmt.Errorf("rpc client: unable to encode constructor arguments: %w", err)
+		}
+	case len(args) > 0:
+		return types.Hash{}, types.Address{}, fmt.Errorf("rpc client: constructor is required to encode arguments")
+	}
+	if c.defaultAddr == nil {
+		return types.Hash{}, types.Address{}, fmt.Errorf("rpc client: default address is required to deploy a contract")
+	}
+	tx, err := c.PrepareTransaction(ctx, types.NewTransaction().SetInput(input))
+	if err != nil {
+		return types.Hash{}, types.Address{}, err
+	}
+	if tx.Call.To != nil {
+		return types.Hash{}, types.Address{}, fmt.Errorf("rpc client: contract creation transaction must not have a recipient")
+	}
+	if tx.Nonce == nil {
+		nonce, err := c.PendingNonce(ctx, *tx.Call.From)
+		if err != nil {
+			return types.Hash{}, types.Address{}, err
+		}
+		tx.Nonce = &nonce
+	}
+	txHash, tx, err := c.sendPreparedTransaction(ctx, tx)
+	if err != nil {
+		return types.Hash{}, types.Address{}, err
+	}
+	return *txHash, types.CreateAddress(*tx.Call.From, *tx.Nonce), nil
+}
+
+// sendPreparedTransaction signs and sends a transaction that was already
+// prepared using PrepareTransaction.
+func (c *Client) sendPreparedTransaction(ctx context.Context, tx *types.Transaction) (*types.Hash, *types.Transaction, error) {
 	if len(c.keys) == 0 {
 		return c.baseClient.SendTransaction(ctx, tx)
 	}
@@ -155,7 +328,15 @@ func (c *Client) SendTransaction(ctx context.Context, tx *types.Transaction) (*t
 		if err := key.SignTransaction(ctx, tx); err != nil {
 			return nil, nil, err
 		}
-		raw, err := tx.Raw()
+		var (
+			raw []byte
+			err error
+		)
+		if tx.Sidecar != nil {
+			raw, err = tx.EncodeNetworkWrapper()
+		} else {
+			raw, err = tx.Raw()
+		}
 		if err != nil {
 			return nil, nil, err
 		}
@@ -169,7 +350,9 @@ func (c *Client) SendTransaction(ctx context.Context, tx *types.Transaction) (*t
 }
 
 // PrepareTransaction prepares the transaction by applying transaction
-// modifiers and setting the default address if it is not set.
+// modifiers and setting the default address and chain ID if they are not
+// set. If the WithTXValidation option is used, the transaction is also
+// validated.
 //
 // A copy of the modified transaction is returned.
 func (c *Client) PrepareTransaction(ctx context.Context, tx *types.Transaction) (*types.Transaction, error) {
@@ -181,21 +364,72 @@ func (c *Client) PrepareTransaction(ctx context.Context, tx *types.Transaction)
 		defaultAddr := *c.defaultAddr
 		txCpy.Call.From = &defaultAddr
 	}
+	if txCpy.ChainID == nil && c.chainID != nil {
+		chainID := *c.chainID
+		txCpy.ChainID = &chainID
+	}
 	for _, modifier := range c.txModifiers {
 		if err := modifier.Modify(ctx, c, txCpy); err != nil {
 			return nil, err
 		}
 	}
+	if c.validateTX {
+		if err := txCpy.Validate(); err != nil {
+			return nil, fmt.Errorf("rpc client: %w", err)
+		}
+	}
 	return txCpy, nil
 }
 
+// PrepareCall prepares the call in the same way as PrepareTransaction
+// prepares transactions, so it can be used to preview the transaction using
+// the Call method under realistic fee conditions.
+//
+// The default address is set if the from address is not set, and the
+// transaction modifiers are applied to a transaction wrapping the call.
+// Modifiers that implement TXOnlyModifier, such as the nonce and chain ID
+// providers, are skipped, and fields that are specific to transactions are
+// discarded. If the gas limit is not set by the modifiers, it is estimated
+// using the EstimateGas method.
+//
+// A copy of the modified call is returned.
+func (c *Client) PrepareCall(ctx context.Context, call *types.Call) (*types.Call, error) {
+	if call == nil {
+		return nil, fmt.Errorf("rpc client: call is nil")
+	}
+	tx := &types.Transaction{Call: *call.Copy()}
+	if tx.Call.From == nil && c.defaultAddr != nil {
+		defaultAddr := *c.defaultAddr
+		tx.Call.From = &defaultAddr
+	}
+	for _, modifier := range c.txModifiers {
+		if m, ok := modifier.(TXOnlyModifier); ok && m.TXOnly() {
+			continue
+		}
+		if err := modifier.Modify(ctx, c, tx); err != nil {
+			return nil, err
+		}
+	}
+	if tx.Call.GasLimit == nil {
+		gasLimit, _, err := c.EstimateGas(ctx, &tx.Call, types.LatestBlockNumber)
+		if err != nil {
+			return nil, err
+		}
+		tx.Call.GasLimit = &gasLimit
+	}
+	return &tx.Call, nil
+}
+
 // Call implements the RPC interface.
 func (c *Client) Call(ctx context.Context, call *types.Call, block types.BlockNumber) ([]byte, *types.Call, error) {
 	if call == nil {
 		return nil, nil, fmt.Errorf("rpc client: call is nil")
 	}
 	callCpy := call.Copy()
-	if callCpy.From == nil && c.defaultAddr != nil {
+	switch {
+	case c.omitCallFrom:
+		callCpy.From = nil
+	case callCpy.From == nil && c.defaultAddr != nil && !c.noCallDefaultAddr:
 		defaultAddr := *c.defaultAddr
 		callCpy.From = &defaultAddr
 	}
@@ -215,8 +449,720 @@ func (c *Client) EstimateGas(ctx context.Context, call *types.Call, block types.
 	return c.baseClient.EstimateGas(ctx, callCpy, block)
 }
 
+// PendingNonce returns the next nonce for the given account, taking pending
+// transactions into account. It calls GetTransactionCount with the pending
+// block tag.
+//
+// Note that the behavior of the pending tag may differ between Ethereum
+// clients.
+func (c *Client) PendingNonce(ctx context.Context, account types.Address) (uint64, error) {
+	return c.baseClient.GetTransactionCount(ctx, account, types.PendingBlockNumber)
+}
+
+// GetStorageValue returns the value of the given storage slot of the
+// contract at the given address.
+//
+// Unlike GetStorageAt, the slot is given as a number. The returned value may
+// be decoded using the types.Hash helpers, such as Uint256, Address or Bool.
+func (c *Client) GetStorageValue(ctx context.Context, account types.Address, slot *big.Int, block types.BlockNumber) (types.Hash, error) {
+	if slot == nil {
+		return types.ZeroHash, fmt.Errorf("rpc client: slot is nil")
+	}
+	if slot.Sign() < 0 {
+		return types.ZeroHash, fmt.Errorf("rpc client: slot cannot be negative")
+	}
+	key, err := types.HashFromBigInt(slot)
+	if err != nil {
+		return types.ZeroHash, fmt.Errorf("rpc client: invalid slot: %w", err)
+	}
+	res, err := c.baseClient.GetStorageAt(ctx, account, key, block)
+	if err != nil {
+		return types.ZeroHash, err
+	}
+	return *res, nil
+}
+
+// Uncles returns the uncle blocks of the block with the given hash. It
+// fetches the number of uncles first and then each uncle by its index.
+//
+// Uncle blocks do not contain transactions, only the block headers are
+// available.
+func (c *Client) Uncles(ctx context.Context, blockHash types.Hash) ([]types.Block, error) {
+	count, err := c.baseClient.GetUncleCountByBlockHash(ctx, blockHash)
+	if err != nil {
+		return nil, err
+	}
+	uncles := make([]types.Block, 0, count)
+	for i := uint64(0); i < count; i++ {
+		uncle, err := c.baseClient.GetUncleByBlockHashAndIndex(ctx, blockHash, i)
+		if err != nil {
+			return nil, err
+		}
+		uncles = append(uncles, *uncle)
+	}
+	return uncles, nil
+}
+
+// GetTransactionReceipts returns the receipts of the transactions with the
+// given hashes. If the transport supports JSON-RPC batch requests, all
+// receipts are fetched using a single request, otherwise they are fetched one
+// by one.
+//
+// The returned receipts are in the same order as the hashes. Entries for
+// transactions that are not mined yet are nil.
+func (c *Client) GetTransactionReceipts(ctx context.Context, hashes []types.Hash) ([]*types.TransactionReceipt, error) {
+	receipts := make([]*types.TransactionReceipt, len(hashes))
+	calls := make([]transport.BatchCall, len(hashes))
+	for i, hash := range hashes {
+		calls[i] = transport.BatchCall{
+			Result: &receipts[i],
+			Method: "eth_getTransactionReceipt",
+			Args:   []any{hash},
+		}
+	}
+	if err := transport.CallBatch(ctx, c.transport, calls); err != nil {
+		return nil, err
+	}
+	for i, call := range calls {
+		if call.Error != nil {
+			return nil, fmt.Errorf("rpc client: failed to get receipt of transaction %s: %w", hashes[i], call.Error)
+		}
+	}
+	return receipts, nil
+}
+
+// BlockWithReceipts returns the block with the given number, including full
+// transaction objects, together with the receipts of its transactions. The
+// receipts are in the same order as the block transactions, so the receipt
+// of block.Transactions[i] is receipts[i].
+//
+// The receipts are fetched using the eth_getBlockReceipts method. If the node
+// does not support it, they are fetched using GetTransactionReceipts.
+//
+// An error is returned if the receipts do not match the block transactions,
+// which may happen if the block is reorganized between the calls.
+func (c *Client) BlockWithReceipts(ctx context.Context, number types.BlockNumber) (*types.Block, []types.TransactionReceipt, error) {
+	block, err := c.baseClient.BlockByNumber(ctx, number, true)
+	if err != nil {
+		return nil, nil, err
+	}
+	if block.Number == nil {
+		return nil, nil, fmt.Errorf("rpc client: block %s not found", number.String())
+	}
+	receipts, err := c.baseClient.GetBlockReceipts(ctx, types.BlockNumberFromBigInt(block.Number))
+	if isMethodNotFound(err) {
+		hashes := make([]types.Hash, len(block.Transactions))
+		for i, tx := range block.Transactions {
+			if tx.Hash == nil {
+				return nil, nil, fmt.Errorf("rpc client: transaction %d of block %s has no hash", i, block.Hash)
+			}
+			hashes[i] = *tx.Hash
+		}
+		receipts, err = c.GetTransactionReceipts(ctx, hashes)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(receipts) != len(block.Transactions) {
+		return nil, nil, fmt.Errorf(
+			"rpc client: block %s has %d transactions, but %d receipts were returned",
+			block.Hash, len(block.Transactions), len(receipts),
+		)
+	}
+	res := make([]types.TransactionReceipt, len(receipts))
+	seen := make([]bool, len(receipts))
+	for _, r := range receipts {
+		if r == nil {
+			return nil, nil, fmt.Errorf("rpc client: missing receipt for a transaction of block %s", block.Hash)
+		}
+		if r.BlockHash != block.Hash {
+			return nil, nil, fmt.Errorf("rpc client: receipt of transaction %s belongs to block %s, expected %s", r.TransactionHash, r.BlockHash, block.Hash)
+		}
+		idx := r.TransactionIndex
+		if idx >= uint64(len(res)) || seen[idx] {
+			return nil, nil, fmt.Errorf("rpc client: invalid receipt index %d for transaction %s", idx, r.TransactionHash)
+		}
+		if tx := block.Transactions[idx]; tx.Hash != nil && *tx.Hash != r.TransactionHash {
+			return nil, nil, fmt.Errorf("rpc client: receipt of transaction %s does not match transaction %s at index %d", r.TransactionHash, *tx.Hash, idx)
+		}
+		res[idx] = *r
+		seen[idx] = true
+	}
+	return block, res, nil
+}
+
+// WaitForConfirmations waits until the transaction with the given hash is
+// included in a block that is at least confirmations blocks deep, counting
+// the block that contains the transaction, and returns its receipt. The
+// receipt is checked every pollInterval.
+//
+// The receipt is fetched again on every check, so if a reorg moves the
+// transaction to another block, the confirmations are counted from the new
+// block. If the transaction is removed from the chain, the method waits until
+// it is included again.
+//
+// The method returns an error if the context is canceled or if any of the
+// RPC calls fails.
+func (c *Client) WaitForConfirmations(ctx context.Context, txHash types.Hash, confirmations uint64, pollInterval time.Duration) (*types.TransactionReceipt, error) {
+	if pollInterval <= 0 {
+		return nil, fmt.Errorf("rpc client: poll interval must be positive")
+	}
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+	for {
+		receipt, err := c.confirmedReceipt(ctx, txHash, confirmations)
+		if err != nil {
+			return nil, err
+		}
+		if receipt != nil {
+			return receipt, nil
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// GetLogsChunked performs eth_getLogs RPC calls for the block range of the
+// given query, split into chunks of at most chunkSize blocks. It is useful
+// for querying large block ranges that exceed node limits.
+//
+// If FromBlock is nil, the range starts at the earliest block. If ToBlock is
+// nil or a tag, it is resolved to a block number before the first call.
+// Queries with BlockHash set cannot be chunked.
+//
+// The returned logs are sorted by block number and log index, and duplicated
+// logs with the same block hash and log index are removed. Logs returned by
+// a single GetLogs call are returned as-is.
+func (c *Client) GetLogsChunked(ctx context.Context, query *types.FilterLogsQuery, chunkSize uint64) ([]types.Log, error) {
+	if query == nil {
+		return nil, fmt.Errorf("rpc client: query is nil")
+	}
+	if query.BlockHash != nil {
+		return nil, fmt.Errorf("rpc client: cannot chunk a query with block hash")
+	}
+	if chunkSize == 0 {
+		return nil, fmt.Errorf("rpc client: chunk size must be greater than zero")
+	}
+	from, err := c.resolveBlockNumber(ctx, query.FromBlock, types.EarliestBlockNumber)
+	if err != nil {
+		return nil, err
+	}
+	to, err := c.resolveBlockNumber(ctx, query.ToBlock, types.LatestBlockNumber)
+	if err != nil {
+		return nil, err
+	}
+	var logs []types.Log
+	for start := from; start <= to; start += chunkSize {
+		end := start + chunkSize - 1
+		if end > to || end < start {
+			end = to
+		}
+		chunk := &types.FilterLogsQuery{
+			Address:   query.Address,
+			FromBlock: types.BlockNumberFromUint64Ptr(start),
+			ToBlock:   types.BlockNumberFromUint64Ptr(end),
+			Topics:    query.Topics,
+		}
+		res, err := c.baseClient.GetLogs(ctx, chunk)
+		if err != nil {
+			return nil, err
+		}
+		logs = append(logs, res...)
+		if end == to {
+			break
+		}
+	}
+	return sortAndDedupLogs(logs), nil
+}
+
+// GetEventLogs performs eth_getLogs RPC call for logs of the given event
+// emitted by the given contract in the given block range, and decodes them.
+//
+// The indexedFilters are used to filter logs by the indexed event arguments,
+// as described in abi.Event.FilterTopics.
+//
+// Values are decoded as described in abi.Event.DecodeLogToMap, so indexed
+// arguments of dynamic types are returned as types.Hash.
+func (c *Client) GetEventLogs(ctx context.Context, addr types.Address, event *abi.Event, from, to types.BlockNumber, indexedFilters ...any) ([]abi.DecodedLog, error) {
+	if event == nil {
+		return nil, fmt.Errorf("rpc client: event is nil")
+	}
+	topics, err := event.FilterTopics(indexedFilters...)
+	if err != nil {
+		return nil, fmt.Errorf("rpc client: %w", err)
+	}
+	query := types.NewFilterLogsQuery().
+		SetAddresses(addr).
+		SetFromBlock(&from).
+		SetToBlock(&to).
+		SetTopics(topics...)
+	logs, err := c.GetLogs(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]abi.DecodedLog, len(logs))
+	for i, log := range logs {
+		values, err := event.DecodeLogToMap(log)
+		if err != nil {
+			return nil, fmt.Errorf("rpc client: cannot decode log %d: %w", i, err)
+		}
+		res[i] = abi.DecodedLog{Log: log, Event: event, Values: values}
+	}
+	return res, nil
+}
+
+// Multicall3Address is the address of the Multicall3 contract. It is deployed
+// at the same address on most EVM chains.
+var Multicall3Address = types.MustAddressFromHex("0xcA11bde05977b3631167028862bE2a173976CA11")
+
+var multicall3Aggregate3 = abi.MustParseMethod(
+	"aggregate3((address target, bool allowFailure, bytes callData)[] calls) payable returns ((bool success, bytes returnData)[] returnData)",
+)
+
+// MulticallCall is a single call aggregated by Client.Multicall.
+type MulticallCall struct {
+	Target       types.Address `abi:"target"`
+	AllowFailure bool          `abi:"allowFailure"`
+	CallData     []byte        `abi:"callData"`
+}
+
+// MulticallResult is the result of a single call aggregated by
+// Client.Multicall.
+type MulticallResult struct {
+	Success    bool   `abi:"success"`
+	ReturnData []byte `abi:"returnData"`
+}
+
+// MulticallOptions contains options for Client.Multicall.
+type MulticallOptions struct {
+	// Address is the address of the Multicall3 contract. If nil,
+	// Multicall3Address is used.
+	Address *types.Address
+
+	// MaxCalls is the maximum number of calls in a single batch. If zero,
+	// the number of calls is not limited.
+	MaxCalls int
+
+	// MaxCalldataSize is the maximum size of the ABI-encoded calldata of a
+	// single batch in bytes. A call that alone exceeds the limit is sent in
+	// a separate batch. If zero, the size is not limited.
+	MaxCalldataSize int
+}
+
+// Multicall aggregates the given calls using the aggregate3 method of the
+// Multicall3 contract, and returns their results in the same order.
+//
+// Calls are split into batches according to the MaxCalls and MaxCalldataSize
+// options, and every batch is sent as a separate eth_call. If any batch
+// fails, for example, because a call without AllowFailure reverted, an error
+// is returned.
+func (c *Client) Multicall(ctx context.Context, calls []MulticallCall, block types.BlockNumber, opts MulticallOptions) ([]MulticallResult, error) {
+	if opts.MaxCalls < 0 || opts.MaxCalldataSize < 0 {
+		return nil, fmt.Errorf("rpc client: multicall limits cannot be negative")
+	}
+	addr := Multicall3Address
+	if opts.Address != nil {
+		addr = *opts.Address
+	}
+	results := make([]MulticallResult, 0, len(calls))
+	for _, batch := range splitMulticall(calls, opts.MaxCalls, opts.MaxCalldataSize) {
+		input, err := multicall3Aggregate3.EncodeArgs(batch)
+		if err != nil {
+			return nil, fmt.Errorf("rpc client: %w", err)
+		}
+		data, _, err := c.Call(ctx, types.NewCall().SetTo(addr).SetInput(input), block)
+		if err != nil {
+			return nil, err
+		}
+		var res []MulticallResult
+		if err := multicall3Aggregate3.DecodeValues(data, &res); err != nil {
+			return nil, fmt.Errorf("rpc client: %w", err)
+		}
+		if len(res) != len(batch) {
+			return nil, fmt.Errorf("rpc client: multicall returned %d results for %d calls", len(res), len(batch))
+		}
+		results = append(results, res...)
+	}
+	return results, nil
+}
+
+// splitMulticall splits the given calls into batches of at most maxCalls
+// calls, whose encoded aggregate3 calldata does not exceed maxSize bytes.
+// Zero limits are ignored.
+func splitMulticall(calls []MulticallCall, maxCalls, maxSize int) [][]MulticallCall {
+	const (
+		batchOverhead = 4 + 2*32 // Selector, array offset and length.
+		callOverhead  = 5 * 32   // Element offset, target, allowFailure, bytes offset and length.
+	)
+	var (
+		batches [][]MulticallCall
+		start   int
+		size    = batchOverhead
+	)
+	for i, call := range calls {
+		callSize := callOverhead + (len(call.CallData)+31)/32*32
+		full := i > start && (maxCalls > 0 && i-start >= maxCalls || maxSize > 0 && size+callSize > maxSize)
+		if full {
+			batches = append(batches, calls[start:i])
+			start, size = i, batchOverhead
+		}
+		size += callSize
+	}
+	if start < len(calls) {
+		batches = append(batches, calls[start:])
+	}
+	return batches
+}
+
+// RawCall performs a JSON-RPC call with the given method and params and
+// returns the result as raw, unparsed JSON.
+//
+// It is useful for calling methods that are not supported by the client, or
+// to access fields that are lost when the result is decoded into the types
+// defined in this library.
+func (c *Client) RawCall(ctx context.Context, method string, params ...any) (json.RawMessage, error) {
+	var res json.RawMessage
+	if err := c.transport.Call(ctx, &res, method, params...); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// TraceCall is a call simulated by TraceCallMany together with the trace
+// types requested for it.
+type TraceCall struct {
+	Call types.Call // Call is the call to simulate.
+
+	// TraceTypes is the list of requested trace types. Valid trace types are
+	// "trace", "stateDiff" and "vmTrace". If empty, only "trace" is
+	// requested.
+	TraceTypes []string
+}
+
+// TraceCallMany simulates the given bundles of calls using the
+// trace_callMany RPC method, supported by Erigon and other nodes that
+// implement the trace namespace.
+//
+// Calls within a bundle are executed one after another on top of the state
+// of the given block, so each call sees the state changes of the previous
+// ones. Bundles are independent of each other. Each bundle is sent as a
+// separate trace_callMany call, using a single batch request if the
+// transport supports it.
+//
+// The default address set using WithDefaultAddress is used for calls that
+// do not have the From field set.
+//
+// The result of bundles[i][j] is returned as res[i][j].
+func (c *Client) TraceCallMany(ctx context.Context, bundles [][]TraceCall, block types.BlockNumber) ([][]types.TraceResult, error) {
+	res := make([][]types.TraceResult, len(bundles))
+	calls := make([]transport.BatchCall, len(bundles))
+	for i, bundle := range bundles {
+		params := make([][]any, len(bundle))
+		for j, tc := range bundle {
+			call := tc.Call.Copy()
+			if call.From == nil && c.defaultAddr != nil {
+				defaultAddr := *c.defaultAddr
+				call.From = &defaultAddr
+			}
+			traceTypes := tc.TraceTypes
+			if len(traceTypes) == 0 {
+				traceTypes = []string{"trace"}
+			}
+			params[j] = []any{call, traceTypes}
+		}
+		calls[i] = transport.BatchCall{
+			Result: &res[i],
+			Method: "trace_callMany",
+			Args:   []any{params, block},
+		}
+	}
+	if err := transport.CallBatch(ctx, c.transport, calls); err != nil {
+		return nil, err
+	}
+	for i, call := range calls {
+		if call.Error != nil {
+			return nil, fmt.Errorf("rpc client: failed to trace bundle %d: %w", i, call.Error)
+		}
+		if len(res[i]) != len(bundles[i]) {
+			return nil, fmt.Errorf("rpc client: trace_callMany returned %d results for %d calls", len(res[i]), len(bundles[i]))
+		}
+	}
+	return res, nil
+}
+
+// LogSpec specifies the events to query using GetTaggedLogs.
+type LogSpec struct {
+	// Address is the address of the contract that emits the events. If nil,
+	// events emitted by any contract are matched.
+	Address *types.Address
+
+	// Events is the list of events to match. Anonymous events are not
+	// supported, because they cannot be recognized by their topics.
+	Events []*abi.Event
+}
+
+// GetTaggedLogs performs a single eth_getLogs RPC call for logs of all events
+// in the given specs, emitted in the given block range, and decodes them.
+//
+// Every log is matched to an event by its address, topic0 and number of
+// topics. The number of topics distinguishes events with the same signature,
+// but different indexed arguments, such as ERC-20 and ERC-721 Transfer
+// events. Logs that do not match any spec are skipped.
+//
+// Values are decoded as described in abi.Event.DecodeLogToMap, so indexed
+// arguments of dynamic types are returned as types.Hash.
+func (c *Client) GetTaggedLogs(ctx context.Context, from, to types.BlockNumber, specs []LogSpec) ([]abi.DecodedLog, error) {
+	if len(specs) == 0 {
+		return nil, errors.New("rpc client: no log specs given")
+	}
+	var (
+		addrs     []types.Address
+		topic0s   []types.Hash
+		anyAddr   bool
+		seenAddr  = make(map[types.Address]bool)
+		seenTopic = make(map[types.Hash]bool)
+	)
+	for _, spec := range specs {
+		if len(spec.Events) == 0 {
+			return nil, errors.New("rpc client: log spec has no events")
+		}
+		if spec.Address == nil {
+			anyAddr = true
+		} else if !seenAddr[*spec.Address] {
+			seenAddr[*spec.Address] = true
+			addrs = append(addrs, *spec.Address)
+		}
+		for _, event := range spec.Events {
+			if event == nil {
+				return nil, errors.New("rpc client: event is nil")
+			}
+			if event.IsAnonymous() {
+				return nil, fmt.Errorf("rpc client: anonymous event %s is not supported", event.Name())
+			}
+			if !seenTopic[event.Topic0()] {
+				seenTopic[event.Topic0()] = true
+				topic0s = append(topic0s, event.Topic0())
+			}
+		}
+	}
+	query := types.NewFilterLogsQuery().
+		SetFromBlock(&from).
+		SetToBlock(&to).
+		SetTopics(topic0s)
+	if !anyAddr {
+		query.SetAddresses(addrs...)
+	}
+	logs, err := c.baseClient.GetLogs(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	return decodeTaggedLogs(specs, logs)
+}
+
+// decodeTaggedLogs decodes logs that match the given specs. Logs that do not
+// match any spec are skipped.
+func decodeTaggedLogs(specs []LogSpec, logs []types.Log) ([]abi.DecodedLog, error) {
+	var res []abi.DecodedLog
+	for i, log := range logs {
+		event := matchLogSpecs(specs, log)
+		if event == nil {
+			continue
+		}
+		values, err := event.DecodeLogToMap(log)
+		if err != nil {
+			return nil, fmt.Errorf("rpc client: cannot decode log %d as %s: %w", i, event.Name(), err)
+		}
+		res = append(res, abi.DecodedLog{Log: log, Event: event, Values: values})
+	}
+	return res, nil
+}
+
+// SupportsEIP1559 returns true if the chain supports EIP-1559 dynamic fee
+// transactions, that is, if the latest block has a base fee.
+//
+// It may be used to choose between legacy and dynamic fee transactions
+// without relying on a list of known chain IDs.
+func (c *Client) SupportsEIP1559(ctx context.Context) (bool, error) {
+	baseFee, err := c.latestBaseFee(ctx)
+	if err != nil {
+		return false, err
+	}
+	return baseFee != nil, nil
+}
+
+// latestBaseFee returns the base fee of the latest block, or nil if the
+// block has no base fee, that is, if the chain does not support EIP-1559.
+func (c *Client) latestBaseFee(ctx context.Context) (*big.Int, error) {
+	block, err := c.baseClient.BlockByNumber(ctx, types.LatestBlockNumber, false)
+	if err != nil {
+		return nil, err
+	}
+	return block.BaseFeePerGas, nil
+}
+
+// SuggestFeeData returns suggested fee data for a new transaction.
+//
+// If the latest block has a base fee, the chain is assumed to support
+// EIP-1559 and the MaxFeePerGas and MaxPriorityFeePerGas fields are set.
+// The MaxFeePerGas is calculated using types.FeeCap, the same way as in
+// SuggestGasFeeCap. Otherwise, the legacy GasPrice field is set.
+//
+// The returned fee data may be used with the types.Transaction.SetFeeData
+// method.
+func (c *Client) SuggestFeeData(ctx context.Context) (types.FeeData, error) {
+	baseFee, err := c.latestBaseFee(ctx)
+	if err != nil {
+		return types.FeeData{}, err
+	}
+	if baseFee == nil {
+		gasPrice, err := c.baseClient.GasPrice(ctx)
+		if err != nil {
+			return types.FeeData{}, err
+		}
+		return types.FeeData{GasPrice: gasPrice}, nil
+	}
+	priorityFee, err := c.baseClient.MaxPriorityFeePerGas(ctx)
+	if err != nil {
+		return types.FeeData{}, err
+	}
+	return types.FeeData{
+		MaxFeePerGas:         types.FeeCap(baseFee, priorityFee),
+		MaxPriorityFeePerGas: priorityFee,
+	}, nil
+}
+
+// SuggestGasTipCap returns the suggested priority fee per gas for a new
+// EIP-1559 transaction, as returned by the eth_maxPriorityFeePerGas method.
+func (c *Client) SuggestGasTipCap(ctx context.Context) (*big.Int, error) {
+	return c.baseClient.MaxPriorityFeePerGas(ctx)
+}
+
+// SuggestGasFeeCap returns the suggested maximum fee per gas for a new
+// EIP-1559 transaction. It is calculated using types.FeeCap from the base
+// fee of the latest block and the tip returned by SuggestGasTipCap.
+//
+// It returns an error if the latest block has no base fee, that is, if the
+// chain does not support EIP-1559.
+func (c *Client) SuggestGasFeeCap(ctx context.Context) (*big.Int, error) {
+	baseFee, err := c.latestBaseFee(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if baseFee == nil {
+		return nil, errors.New("rpc client: latest block has no base fee, the chain does not support EIP-1559")
+	}
+	tip, err := c.SuggestGasTipCap(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return types.FeeCap(baseFee, tip), nil
+}
+
+// FeeHistory performs eth_feeHistory RPC call.
+//
+// It returns the fee history of blockCount blocks ending at the newest
+// block, including the rewards for the given percentiles. The percentiles
+// are stored in the RewardPercentiles field of the result, so they can be
+// used with types.FeeHistory.SuggestTip.
+func (c *Client) FeeHistory(ctx context.Context, blockCount uint64, newest types.BlockNumber, percentiles []float64) (*types.FeeHistory, error) {
+	if percentiles == nil {
+		percentiles = []float64{}
+	}
+	var res types.FeeHistory
+	if err := c.transport.Call(ctx, &res, "eth_feeHistory", types.NumberFromUint64(blockCount), newest, percentiles); err != nil {
+		return nil, err
+	}
+	res.RewardPercentiles = percentiles
+	return &res, nil
+}
+
+// resolveBlockNumber converts a block number, that may be a tag, to a
+// number. If block is nil, def is used instead.
+func (c *Client) resolveBlockNumber(ctx context.Context, block *types.BlockNumber, def types.BlockNumber) (uint64, error) {
+	if block == nil {
+		block = &def
+	}
+	switch {
+	case !block.IsTag():
+		if !block.Big().IsUint64() {
+			return 0, fmt.Errorf("rpc client: block number is too big")
+		}
+		return block.Big().Uint64(), nil
+	case block.IsEarliest():
+		return 0, nil
+	case block.IsLatest(), block.IsPending():
+		bn, err := c.baseClient.BlockNumber(ctx)
+		if err != nil {
+			return 0, err
+		}
+		return bn.Uint64(), nil
+	default:
+		b, err := c.baseClient.BlockByNumber(ctx, *block, false)
+		if err != nil {
+			return 0, err
+		}
+		if b.Number == nil {
+			return 0, fmt.Errorf("rpc client: unable to resolve block %s", block.String())
+		}
+		return b.Number.Uint64(), nil
+	}
+}
+
+// matchLogSpecs returns the first event from the specs that matches the
+// address, topic0 and number of topics of the given log, or nil if there is
+// no such event.
+func matchLogSpecs(specs []LogSpec, log types.Log) *abi.Event {
+	if len(log.Topics) == 0 {
+		return nil
+	}
+	for _, spec := range specs {
+		if spec.Address != nil && *spec.Address != log.Address {
+			continue
+		}
+		for _, event := range spec.Events {
+			if event.Topic0() == log.Topics[0] && event.Inputs().IndexedSize()+1 == len(log.Topics) {
+				return event
+			}
+		}
+	}
+	return nil
+}
+
+// isMethodNotFound returns true if the error is a JSON-RPC error indicating
+// that the method is not supported by the node.
+func isMethodNotFound(err error) bool {
+	var rpcErr transport.RPCErrorCode
+	return errors.As(err, &rpcErr) && rpcErr.RPCErrorCode() == transport.ErrCodeMethodNotFound
+}
+
+// confirmedReceipt returns the receipt of the transaction with the given
+// hash if the transaction has at least the given number of confirmations.
+// Otherwise, it returns nil.
+func (c *Client) confirmedReceipt(ctx context.Context, txHash types.Hash, confirmations uint64) (*types.TransactionReceipt, error) {
+	var receipt *types.TransactionReceipt
+	if err := c.transport.Call(ctx, &receipt, "eth_getTransactionReceipt", txHash); err != nil {
+		return nil, err
+	}
+	if receipt == nil || receipt.BlockNumber == nil {
+		return nil, nil
+	}
+	latest, err := c.baseClient.BlockNumber(ctx)
+	if err != nil {
+		return nil, err
+	}
+	depth := new(big.Int).Sub(latest, receipt.BlockNumber)
+	depth.Add(depth, big.NewInt(1))
+	if depth.Cmp(new(big.Int).SetUint64(confirmations)) < 0 {
+		return nil, nil
+	}
+	return receipt, nil
+}
+
 // findKey finds a key by address.
-func (c *Client) findKey(addr *types.Address) wallet.Key {
+func (c *Client) findKey(addr *types.Address) wallet.Signer {
 	if addr == nil {
 		return nil
 	}
@@ -225,3 +1171,14 @@ func (c *Client) findKey(addr *types.Address) wallet.Key {
 	}
 	return nil
 }
+
+// keySigner adapts a wallet.Key that does not support hash signing to the
+// wallet.Signer interface.
+type keySigner struct {
+	wallet.Key
+}
+
+// SignHash implements the wallet.Signer interface.
+func (k keySigner) SignHash(_ context.Context, _ types.Hash) (*types.Signature, error) {
+	return nil, fmt.Errorf("rpc client: key %s does not support hash signing", k.Address())
+}
@@ -3,15 +3,23 @@ package rpc
 import (
 	"bytes"
 	"context"
+	"errors"
+	"fmt"
 	"io"
 	"math/big"
 	"net/http"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/defiweb/go-eth/abi"
+	"github.com/defiweb/go-eth/crypto"
+	"github.com/defiweb/go-eth/rpc/transport"
 	"github.com/defiweb/go-eth/types"
+	"github.com/defiweb/go-eth/wallet"
 )
 
 func TestClient_Sign(t *testing.T) {
@@ -35,6 +43,69 @@ func TestClient_Sign(t *testing.T) {
 	assert.Equal(t, types.MustSignatureFromHex("0xa3a7b12762dbc5df6cfbedbecdf8a821929c6112d2634abbb0d99dc63ad914908051b2c8c7d159db49ad19bd01026156eedab2f3d8c1dfdd07d21c07a4bbdd846f"), *signature)
 }
 
+func TestClient_SignWithSigner(t *testing.T) {
+	httpMock := newHTTPMock()
+	keyMock := &keyMock{}
+	keyMock.addressCallback = func() types.Address {
+		return types.MustAddressFromHex("0x1111111111111111111111111111111111111111")
+	}
+	keyMock.signHashCallback = func(hash types.Hash) (*types.Signature, error) {
+		assert.Equal(t, crypto.Keccak256(crypto.AddMessagePrefix([]byte("All your base are belong to us"))), hash)
+		return types.MustSignatureFromHexPtr("0xa3a7b12762dbc5df6cfbedbecdf8a821929c6112d2634abbb0d99dc63ad914908051b2c8c7d159db49ad19bd01026156eedab2f3d8c1dfdd07d21c07a4bbdd846f"), nil
+	}
+
+	// Hide the wallet.Key methods, so only the wallet.Signer ones are
+	// available to the client.
+	signer := struct{ wallet.Signer }{keyMock}
+
+	client, _ := NewClient(WithTransport(httpMock), WithSigner(signer))
+
+	accounts, err := client.Accounts(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []types.Address{types.MustAddressFromHex("0x1111111111111111111111111111111111111111")}, accounts)
+
+	signature, err := client.Sign(
+		context.Background(),
+		types.MustAddressFromHex("0x1111111111111111111111111111111111111111"),
+		[]byte("All your base are belong to us"),
+	)
+	require.NoError(t, err)
+	assert.Equal(t, types.MustSignatureFromHex("0xa3a7b12762dbc5df6cfbedbecdf8a821929c6112d2634abbb0d99dc63ad914908051b2c8c7d159db49ad19bd01026156eedab2f3d8c1dfdd07d21c07a4bbdd846f"), *signature)
+}
+
+func TestClient_SignTransactionWithSigner(t *testing.T) {
+	httpMock := newHTTPMock()
+	keyMock := &keyMock{}
+	keyMock.addressCallback = func() types.Address {
+		return types.MustAddressFromHex("0xb60e8dd61c5d32be8058bb8eb970870f07233155")
+	}
+	keyMock.signTransactionCallback = func(tx *types.Transaction) error {
+		tx.Signature = types.MustSignatureFromHexPtr("0x2222222222222222222222222222222222222222222222222222222222222222333333333333333333333333333333333333333333333333333333333333333311")
+		return nil
+	}
+
+	client, _ := NewClient(WithTransport(httpMock), WithSigner(struct{ wallet.Signer }{keyMock}))
+
+	from := types.MustAddressFromHex("0xb60e8dd61c5d32be8058bb8eb970870f07233155")
+	to := types.MustAddressFromHex("0xd46e8dd67c5d32be8058bb8eb970870f07244567")
+	gasLimit := uint64(30400)
+	chainID := uint64(1)
+	_, tx, err := client.SignTransaction(
+		context.Background(),
+		&types.Transaction{
+			ChainID: &chainID,
+			Call: types.Call{
+				From:     &from,
+				To:       &to,
+				GasLimit: &gasLimit,
+				GasPrice: big.NewInt(10000000000000),
+			},
+		},
+	)
+	require.NoError(t, err)
+	assert.Equal(t, types.MustSignatureFromHexPtr("0x2222222222222222222222222222222222222222222222222222222222222222333333333333333333333333333333333333333333333333333333333333333311"), tx.Signature)
+}
+
 func TestClient_SignTransaction(t *testing.T) {
 	httpMock := newHTTPMock()
 	keyMock := &keyMock{}
@@ -79,6 +150,34 @@ func TestClient_SignTransaction(t *testing.T) {
 	assert.Equal(t, hexToBytes("0x3333333333333333333333333333333333333333333333333333333333333333"), tx.Signature.Bytes()[32:64])
 }
 
+func TestClient_SignTransactionWithChainIDZero(t *testing.T) {
+	httpMock := newHTTPMock()
+	key := wallet.NewKeyFromBytes(hexToBytes("0x4646464646464646464646464646464646464646464646464646464646464646"))
+
+	client, _ := NewClient(WithTransport(httpMock), WithKeys(key), WithChainID(0))
+
+	from := key.Address()
+	to := types.MustAddressFromHex("0xd46e8dd67c5d32be8058bb8eb970870f07244567")
+	gasLimit := uint64(21000)
+	nonce := uint64(0)
+	_, tx, err := client.SignTransaction(
+		context.Background(),
+		&types.Transaction{
+			Call: types.Call{
+				From:     &from,
+				To:       &to,
+				GasLimit: &gasLimit,
+				GasPrice: big.NewInt(10000000000000),
+			},
+			Nonce: &nonce,
+		},
+	)
+	require.NoError(t, err)
+	require.NotNil(t, tx.ChainID)
+	assert.Equal(t, uint64(0), *tx.ChainID)
+	assert.Contains(t, []int64{27, 28}, tx.Signature.V.Int64())
+}
+
 func TestClient_SendTransaction(t *testing.T) {
 	httpMock := newHTTPMock()
 	keyMock := &keyMock{}
@@ -128,6 +227,143 @@ func TestClient_SendTransaction(t *testing.T) {
 	assert.Equal(t, input, tx.Input)
 }
 
+func TestClient_SendBlobTransaction(t *testing.T) {
+	sig := types.MustSignatureFromHex("0x2222222222222222222222222222222222222222222222222222222222222222333333333333333333333333333333333333333333333333333333333333333301")
+	keyMock := &keyMock{}
+	keyMock.addressCallback = func() types.Address {
+		return types.MustAddressFromHex("0xb60e8dd61c5d32be8058bb8eb970870f07233155")
+	}
+	keyMock.signTransactionCallback = func(tx *types.Transaction) error {
+		tx.Signature = sig.Copy()
+		return nil
+	}
+
+	sidecar := &types.BlobSidecar{
+		Blobs:       [][]byte{make([]byte, types.BlobLength)},
+		Commitments: [][]byte{make([]byte, types.KZGCommitmentLength)},
+		Proofs:      [][]byte{make([]byte, types.KZGProofLength)},
+	}
+	tx := types.NewTransaction().
+		SetType(types.BlobTxType).
+		SetChainID(1).
+		SetNonce(1).
+		SetFrom(types.MustAddressFromHex("0xb60e8dd61c5d32be8058bb8eb970870f07233155")).
+		SetTo(types.MustAddressFromHex("0xd46e8dd67c5d32be8058bb8eb970870f07244567")).
+		SetGasLimit(21000).
+		SetMaxFeePerGas(big.NewInt(100)).
+		SetMaxPriorityFeePerGas(big.NewInt(10)).
+		SetMaxFeePerBlobGas(big.NewInt(1)).
+		SetBlobVersionedHashes(sidecar.VersionedHashes())
+	tx.Sidecar = sidecar
+
+	signed := tx.Copy().SetSignature(sig)
+	wrapper, err := signed.EncodeNetworkWrapper()
+	require.NoError(t, err)
+
+	callMock := newCallMock(t, callMockEntry{
+		ArgMethod: "eth_sendRawTransaction",
+		ArgParams: fmt.Sprintf(`["0x%x"]`, wrapper),
+		RetResult: `"0x1111111111111111111111111111111111111111111111111111111111111111"`,
+	})
+	client, _ := NewClient(WithTransport(callMock), WithKeys(keyMock))
+
+	_, _, err = client.SendTransaction(context.Background(), tx)
+	require.NoError(t, err)
+	assert.Empty(t, callMock.CallMocks)
+}
+
+func TestClient_DeployContract(t *testing.T) {
+	callMock := newCallMock(t,
+		callMockEntry{
+			ArgMethod: "eth_getTransactionCount",
+			ArgParams: `["0x1111111111111111111111111111111111111111","pending"]`,
+			RetResult: `"0x5"`,
+		},
+		callMockEntry{
+			ArgMethod: "eth_sendTransaction",
+			ArgParams: `[{"from":"0x1111111111111111111111111111111111111111","input":"0x60806040000000000000000000000000000000000000000000000000000000000000002a","nonce":"0x5"}]`,
+			RetResult: `"0x2222222222222222222222222222222222222222222222222222222222222222"`,
+		},
+	)
+	client, _ := NewClient(
+		WithTransport(callMock),
+		WithDefaultAddress(types.MustAddressFromHex("0x1111111111111111111111111111111111111111")),
+	)
+
+	txHash, addr, err := client.DeployContract(
+		context.Background(),
+		hexToBytes("0x60806040"),
+		abi.MustParseConstructor("constructor(uint256 x)"),
+		42,
+	)
+	require.NoError(t, err)
+	assert.Equal(t, types.MustHashFromHex("0x2222222222222222222222222222222222222222222222222222222222222222", types.PadNone), txHash)
+	assert.Equal(t, types.CreateAddress(types.MustAddressFromHex("0x1111111111111111111111111111111111111111"), 5), addr)
+	assert.Empty(t, callMock.CallMocks)
+}
+
+func TestClient_DeployContractWithoutDefaultAddress(t *testing.T) {
+	callMock := newCallMock(t)
+	client, _ := NewClient(WithTransport(callMock))
+
+	_, _, err := client.DeployContract(context.Background(), hexToBytes("0x60806040"), nil)
+	require.Error(t, err)
+
+	_, _, err = client.DeployContract(context.Background(), hexToBytes("0x60806040"), nil, 42)
+	require.Error(t, err)
+	assert.Empty(t, callMock.CallMocks)
+}
+
+func TestClient_SendTransactionWithTXValidation(t *testing.T) {
+	callMock := newCallMock(t)
+	client, _ := NewClient(WithTransport(callMock), WithTXValidation())
+
+	// Dynamic fee transactions must not set GasPrice.
+	_, _, err := client.SendTransaction(
+		context.Background(),
+		types.NewTransaction().
+			SetType(types.DynamicFeeTxType).
+			SetTo(types.MustAddressFromHex("0xd46e8dd67c5d32be8058bb8eb970870f07244567")).
+			SetGasPrice(big.NewInt(1)),
+	)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "GasPrice")
+	assert.Empty(t, callMock.CallMocks)
+}
+
+func TestClient_PrepareCall(t *testing.T) {
+	callMock := newCallMock(t,
+		callMockEntry{
+			ArgMethod: "eth_estimateGas",
+			ArgParams: `[{"from":"0x1111111111111111111111111111111111111111","to":"0x2222222222222222222222222222222222222222","maxFeePerGas":"0x2","maxPriorityFeePerGas":"0x1"},"latest"]`,
+			RetResult: `"0x5208"`,
+		},
+	)
+	client, _ := NewClient(
+		WithTransport(callMock),
+		WithDefaultAddress(types.MustAddressFromHex("0x1111111111111111111111111111111111111111")),
+		WithTXModifiers(TXModifierFunc(func(ctx context.Context, client RPC, tx *types.Transaction) error {
+			tx.SetMaxFeePerGas(big.NewInt(2))
+			tx.SetMaxPriorityFeePerGas(big.NewInt(1))
+			tx.SetNonce(1)
+			return nil
+		})),
+	)
+
+	call := types.NewCall().SetTo(types.MustAddressFromHex("0x2222222222222222222222222222222222222222"))
+	prepared, err := client.PrepareCall(context.Background(), call)
+	require.NoError(t, err)
+	require.Empty(t, callMock.CallMocks)
+	assert.Equal(t, "0x1111111111111111111111111111111111111111", prepared.From.String())
+	assert.Equal(t, uint64(21000), *prepared.GasLimit)
+	assert.Equal(t, big.NewInt(2), prepared.MaxFeePerGas)
+	assert.Equal(t, big.NewInt(1), prepared.MaxPriorityFeePerGas)
+
+	// The original call must not be modified.
+	assert.Nil(t, call.From)
+	assert.Nil(t, call.GasLimit)
+}
+
 func TestClient_Call(t *testing.T) {
 	httpMock := newHTTPMock()
 	client, _ := NewClient(
@@ -187,3 +423,885 @@ func TestClient_EstimateGas(t *testing.T) {
 	require.NoError(t, err)
 	assert.JSONEq(t, mockEstimateGasRequest, readBody(httpMock.Request))
 }
+
+func TestClient_GetStorageValue(t *testing.T) {
+	httpMock := newHTTPMock()
+	client, _ := NewClient(WithTransport(httpMock))
+
+	httpMock.ResponseMock = &http.Response{
+		StatusCode: 200,
+		Body:       io.NopCloser(bytes.NewBufferString(`{"jsonrpc":"2.0","id":1,"result":"0x0000000000000000000000011111111111111111111111111111111111111111"}`)),
+	}
+
+	value, err := client.GetStorageValue(
+		context.Background(),
+		types.MustAddressFromHex("0x2222222222222222222222222222222222222222"),
+		big.NewInt(3),
+		types.LatestBlockNumber,
+	)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"jsonrpc": "2.0",
+		"id": 1,
+		"method": "eth_getStorageAt",
+		"params": [
+			"0x2222222222222222222222222222222222222222",
+			"0x0000000000000000000000000000000000000000000000000000000000000003",
+			"latest"
+		]
+	}`, readBody(httpMock.Request))
+	assert.Equal(t, types.MustAddressFromHex("0x1111111111111111111111111111111111111111"), value.Address())
+	assert.True(t, value.Bool())
+
+	_, err = client.GetStorageValue(context.Background(), types.ZeroAddress, big.NewInt(-1), types.LatestBlockNumber)
+	assert.Error(t, err)
+}
+
+func TestClient_GetLogsChunked(t *testing.T) {
+	callMock := newCallMock(t,
+		callMockEntry{
+			ArgMethod: "eth_blockNumber",
+			RetResult: `"0x19"`,
+		},
+		callMockEntry{
+			ArgMethod: "eth_getLogs",
+			ArgParams: `[{"fromBlock":"0xa","toBlock":"0x13","address":"0x1111111111111111111111111111111111111111","topics":null}]`,
+			RetResult: `[
+				{"address":"0x1111111111111111111111111111111111111111","blockHash":"0x2222222222222222222222222222222222222222222222222222222222222222","blockNumber":"0x13","logIndex":"0x2","topics":[],"data":"0x"},
+				{"address":"0x1111111111111111111111111111111111111111","blockHash":"0x2222222222222222222222222222222222222222222222222222222222222222","blockNumber":"0x13","logIndex":"0x1","topics":[],"data":"0x"},
+				{"address":"0x1111111111111111111111111111111111111111","blockHash":"0x3333333333333333333333333333333333333333333333333333333333333333","blockNumber":"0xa","logIndex":"0x0","topics":[],"data":"0x"}
+			]`,
+		},
+		callMockEntry{
+			ArgMethod: "eth_getLogs",
+			ArgParams: `[{"fromBlock":"0x14","toBlock":"0x19","address":"0x1111111111111111111111111111111111111111","topics":null}]`,
+			RetResult: `[
+				{"address":"0x1111111111111111111111111111111111111111","blockHash":"0x2222222222222222222222222222222222222222222222222222222222222222","blockNumber":"0x13","logIndex":"0x2","topics":[],"data":"0x"},
+				{"address":"0x1111111111111111111111111111111111111111","blockHash":"0x4444444444444444444444444444444444444444444444444444444444444444","blockNumber":"0x14","logIndex":"0x0","topics":[],"data":"0x"}
+			]`,
+		},
+	)
+	client, _ := NewClient(WithTransport(callMock))
+
+	logs, err := client.GetLogsChunked(
+		context.Background(),
+		types.NewFilterLogsQuery().
+			SetAddresses(types.MustAddressFromHex("0x1111111111111111111111111111111111111111")).
+			SetFromBlock(types.BlockNumberFromUint64Ptr(10)),
+		10,
+	)
+	require.NoError(t, err)
+	require.Empty(t, callMock.CallMocks)
+	require.Len(t, logs, 4)
+	assert.Equal(t, []uint64{10, 19, 19, 20}, []uint64{logs[0].BlockNumber.Uint64(), logs[1].BlockNumber.Uint64(), logs[2].BlockNumber.Uint64(), logs[3].BlockNumber.Uint64()})
+	assert.Equal(t, []uint64{0, 1, 2, 0}, []uint64{*logs[0].LogIndex, *logs[1].LogIndex, *logs[2].LogIndex, *logs[3].LogIndex})
+}
+
+func TestClient_GetEventLogs(t *testing.T) {
+	callMock := newCallMock(t,
+		callMockEntry{
+			ArgMethod: "eth_getLogs",
+			ArgParams: `[{"fromBlock":"0xa","toBlock":"latest","address":"0x1111111111111111111111111111111111111111","topics":["0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef",[],"0x0000000000000000000000002222222222222222222222222222222222222222"]}]`,
+			RetResult: `[
+				{
+					"address":"0x1111111111111111111111111111111111111111",
+					"blockNumber":"0xb",
+					"logIndex":"0x0",
+					"topics":[
+						"0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef",
+						"0x0000000000000000000000003333333333333333333333333333333333333333",
+						"0x0000000000000000000000002222222222222222222222222222222222222222"
+					],
+					"data":"0x000000000000000000000000000000000000000000000000000000000000002a"
+				}
+			]`,
+		},
+	)
+	client, _ := NewClient(WithTransport(callMock))
+
+	logs, err := client.GetEventLogs(
+		context.Background(),
+		types.MustAddressFromHex("0x1111111111111111111111111111111111111111"),
+		abi.MustParseEvent("Transfer(address indexed from, address indexed to, uint256 value)"),
+		types.BlockNumberFromUint64(10),
+		types.LatestBlockNumber,
+		nil,
+		types.MustAddressFromHex("0x2222222222222222222222222222222222222222"),
+	)
+	require.NoError(t, err)
+	require.Empty(t, callMock.CallMocks)
+	require.Len(t, logs, 1)
+	assert.Equal(t, uint64(11), logs[0].BlockNumber.Uint64())
+	assert.Equal(t, types.MustAddressFromHex("0x3333333333333333333333333333333333333333"), logs[0].Values["from"])
+	assert.Equal(t, types.MustAddressFromHex("0x2222222222222222222222222222222222222222"), logs[0].Values["to"])
+	assert.Equal(t, big.NewInt(42), logs[0].Values["value"])
+}
+
+func TestClient_GetEventLogs_IndexedString(t *testing.T) {
+	event := abi.MustParseEvent("Named(string indexed name)")
+	nameHash := types.MustHashFromHex("0x41b1a0649752af1b28b3dc29a1556eee781e4a4c3a1f7f53f90fa834de098c4d", types.PadNone)
+	callMock := newCallMock(t,
+		callMockEntry{
+			ArgMethod: "eth_getLogs",
+			ArgParams: `[{"fromBlock":"0xa","toBlock":"latest","address":"0x1111111111111111111111111111111111111111","topics":["` + event.Topic0().String() + `"]}]`,
+			RetResult: `[{"address":"0x1111111111111111111111111111111111111111","topics":["` + event.Topic0().String() + `","` + nameHash.String() + `"],"data":"0x"}]`,
+		},
+	)
+	client, _ := NewClient(WithTransport(callMock))
+
+	logs, err := client.GetEventLogs(
+		context.Background(),
+		types.MustAddressFromHex("0x1111111111111111111111111111111111111111"),
+		event,
+		types.BlockNumberFromUint64(10),
+		types.LatestBlockNumber,
+	)
+	require.NoError(t, err)
+	require.Len(t, logs, 1)
+	assert.Same(t, event, logs[0].Event)
+
+	// Indexed strings are decoded in the same way as by GetTaggedLogs.
+	assert.Equal(t, nameHash, logs[0].Values["name"])
+}
+
+func TestClient_RawCall(t *testing.T) {
+	const block = `{"number":"0x1","hash":"0x1111111111111111111111111111111111111111111111111111111111111111","newField":"0x2a"}`
+	callMock := newCallMock(t, callMockEntry{
+		ArgMethod: "eth_getBlockByNumber",
+		ArgParams: `["latest", false]`,
+		RetResult: block,
+	})
+	client, _ := NewClient(WithTransport(callMock))
+
+	raw, err := client.RawCall(context.Background(), "eth_getBlockByNumber", types.LatestBlockNumber, false)
+	require.NoError(t, err)
+	require.Empty(t, callMock.CallMocks)
+	assert.JSONEq(t, block, string(raw))
+}
+
+func TestClient_Multicall(t *testing.T) {
+	target := types.MustAddressFromHex("0x1111111111111111111111111111111111111111")
+	calls := make([]MulticallCall, 5)
+	results := make([]MulticallResult, 5)
+	for i := range calls {
+		calls[i] = MulticallCall{Target: target, AllowFailure: i%2 == 0, CallData: []byte{byte(i)}}
+		results[i] = MulticallResult{Success: i != 3, ReturnData: []byte{byte(i), byte(i)}}
+	}
+
+	var entries []callMockEntry
+	for _, r := range [][2]int{{0, 2}, {2, 4}, {4, 5}} {
+		input, err := multicall3Aggregate3.EncodeArgs(calls[r[0]:r[1]])
+		require.NoError(t, err)
+		output, err := abi.EncodeValues(multicall3Aggregate3.Outputs(), results[r[0]:r[1]])
+		require.NoError(t, err)
+		entries = append(entries, callMockEntry{
+			ArgMethod: "eth_call",
+			ArgParams: fmt.Sprintf(`[{"to":"0xca11bde05977b3631167028862be2a173976ca11","data":"0x%x"},"latest"]`, input),
+			RetResult: fmt.Sprintf(`"0x%x"`, output),
+		})
+	}
+	callMock := newCallMock(t, entries...)
+	client, _ := NewClient(WithTransport(callMock))
+
+	res, err := client.Multicall(context.Background(), calls, types.LatestBlockNumber, MulticallOptions{MaxCalls: 2})
+	require.NoError(t, err)
+	require.Empty(t, callMock.CallMocks)
+	assert.Equal(t, results, res)
+
+	// No calls.
+	res, err = client.Multicall(context.Background(), nil, types.LatestBlockNumber, MulticallOptions{})
+	require.NoError(t, err)
+	assert.Empty(t, res)
+
+	_, err = client.Multicall(context.Background(), calls, types.LatestBlockNumber, MulticallOptions{MaxCalls: -1})
+	assert.Error(t, err)
+}
+
+func TestSplitMulticall(t *testing.T) {
+	target := types.MustAddressFromHex("0x1111111111111111111111111111111111111111")
+	calls := []MulticallCall{
+		{Target: target, CallData: make([]byte, 4)},
+		{Target: target, CallData: make([]byte, 36)},
+		{Target: target, CallData: make([]byte, 500)},
+		{Target: target, CallData: make([]byte, 68)},
+		{Target: target},
+	}
+	encodedSize := func(batch []MulticallCall) int {
+		input, err := multicall3Aggregate3.EncodeArgs(batch)
+		require.NoError(t, err)
+		return len(input)
+	}
+	tests := []struct {
+		name     string
+		maxCalls int
+		maxSize  int
+		want     []int
+	}{
+		{name: "no limits", want: []int{5}},
+		{name: "max calls", maxCalls: 2, want: []int{2, 2, 1}},
+		{name: "max size", maxSize: 600, want: []int{2, 1, 2}},
+		{name: "max size exceeded by single call", maxSize: 300, want: []int{1, 1, 1, 1, 1}},
+		{name: "both limits", maxCalls: 1, maxSize: 10000, want: []int{1, 1, 1, 1, 1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			batches := splitMulticall(calls, tt.maxCalls, tt.maxSize)
+			var sizes []int
+			var joined []MulticallCall
+			for _, b := range batches {
+				sizes = append(sizes, len(b))
+				joined = append(joined, b...)
+				if tt.maxSize > 0 && len(b) > 1 {
+					assert.LessOrEqual(t, encodedSize(b), tt.maxSize)
+				}
+			}
+			assert.Equal(t, tt.want, sizes)
+			assert.Equal(t, calls, joined)
+		})
+	}
+}
+
+func TestClient_TraceCallMany(t *testing.T) {
+	callMock := newCallMock(t,
+		callMockEntry{
+			ArgMethod: "trace_callMany",
+			ArgParams: `[
+				[
+					[{"from":"0x1111111111111111111111111111111111111111","to":"0x2222222222222222222222222222222222222222","data":"0x01"},["trace","stateDiff"]],
+					[{"from":"0x3333333333333333333333333333333333333333","to":"0x2222222222222222222222222222222222222222","data":"0x02"},["trace"]]
+				],
+				"latest"
+			]`,
+			RetResult: `[{"output":"0x","stateDiff":{},"trace":[]},{"output":"0x2a","trace":[]}]`,
+		},
+		callMockEntry{
+			ArgMethod: "trace_callMany",
+			ArgParams: `[
+				[
+					[{"from":"0x1111111111111111111111111111111111111111","to":"0x2222222222222222222222222222222222222222","data":"0x03"},["vmTrace"]]
+				],
+				"latest"
+			]`,
+			RetResult: `[{"output":"0x01","vmTrace":{}}]`,
+		},
+	)
+	client, _ := NewClient(
+		WithTransport(callMock),
+		WithDefaultAddress(types.MustAddressFromHex("0x1111111111111111111111111111111111111111")),
+	)
+
+	to := types.MustAddressFromHex("0x2222222222222222222222222222222222222222")
+	from := types.MustAddressFromHex("0x3333333333333333333333333333333333333333")
+	res, err := client.TraceCallMany(
+		context.Background(),
+		[][]TraceCall{
+			{
+				{Call: types.Call{To: &to, Input: []byte{0x01}}, TraceTypes: []string{"trace", "stateDiff"}},
+				{Call: types.Call{From: &from, To: &to, Input: []byte{0x02}}},
+			},
+			{
+				{Call: types.Call{To: &to, Input: []byte{0x03}}, TraceTypes: []string{"vmTrace"}},
+			},
+		},
+		types.LatestBlockNumber,
+	)
+	require.NoError(t, err)
+	require.Empty(t, callMock.CallMocks)
+	require.Len(t, res, 2)
+	require.Len(t, res[0], 2)
+	require.Len(t, res[1], 1)
+	assert.Equal(t, types.Bytes{0x2a}, res[0][1].Output)
+	assert.Equal(t, types.Bytes{0x01}, res[1][0].Output)
+	assert.JSONEq(t, `{}`, string(res[1][0].VMTrace))
+}
+
+func TestClient_GetTaggedLogs(t *testing.T) {
+	const (
+		transferTopic = "0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef"
+		approvalTopic = "0x8c5be1e5ebec7d5bd14f71427d1e84f3dd0314c0f7b2291e5b200ac8c7c3b925"
+		erc20Addr     = "0x1111111111111111111111111111111111111111"
+		erc721Addr    = "0x4444444444444444444444444444444444444444"
+		addr2         = "0x0000000000000000000000002222222222222222222222222222222222222222"
+		addr3         = "0x0000000000000000000000003333333333333333333333333333333333333333"
+		word42        = "0x000000000000000000000000000000000000000000000000000000000000002a"
+	)
+	callMock := newCallMock(t,
+		callMockEntry{
+			ArgMethod: "eth_getLogs",
+			ArgParams: `[{"fromBlock":"0xa","toBlock":"0x14","address":["` + erc20Addr + `","` + erc721Addr + `"],"topics":[["` + transferTopic + `","` + approvalTopic + `"]]}]`,
+			RetResult: `[
+				{"address":"` + erc20Addr + `","topics":["` + transferTopic + `","` + addr3 + `","` + addr2 + `"],"data":"` + word42 + `"},
+				{"address":"` + erc721Addr + `","topics":["` + transferTopic + `","` + addr3 + `","` + addr2 + `","` + word42 + `"],"data":"0x"},
+				{"address":"` + erc721Addr + `","topics":["` + transferTopic + `","` + addr3 + `","` + addr2 + `"],"data":"` + word42 + `"},
+				{"address":"` + erc20Addr + `","topics":["` + approvalTopic + `","` + addr3 + `","` + addr2 + `"],"data":"` + word42 + `"}
+			]`,
+		},
+	)
+	client, _ := NewClient(WithTransport(callMock))
+
+	erc20Transfer := abi.MustParseEvent("Transfer(address indexed from, address indexed to, uint256 value)")
+	erc20Approval := abi.MustParseEvent("Approval(address indexed owner, address indexed spender, uint256 value)")
+	erc721Transfer := abi.MustParseEvent("Transfer(address indexed from, address indexed to, uint256 indexed tokenId)")
+	logs, err := client.GetTaggedLogs(
+		context.Background(),
+		types.BlockNumberFromUint64(10),
+		types.BlockNumberFromUint64(20),
+		[]LogSpec{
+			{Address: types.MustAddressFromHexPtr(erc20Addr), Events: []*abi.Event{erc20Transfer, erc20Approval}},
+			{Address: types.MustAddressFromHexPtr(erc721Addr), Events: []*abi.Event{erc721Transfer}},
+		},
+	)
+	require.NoError(t, err)
+	require.Empty(t, callMock.CallMocks)
+
+	// The third log is skipped, because the ERC-721 contract does not emit
+	// ERC-20 Transfer events.
+	require.Len(t, logs, 3)
+	assert.Equal(t, "Transfer", logs[0].Event.Name())
+	assert.Same(t, erc20Transfer, logs[0].Event)
+	assert.Equal(t, big.NewInt(42), logs[0].Values["value"])
+	assert.Same(t, erc721Transfer, logs[1].Event)
+	assert.Equal(t, big.NewInt(42), logs[1].Values["tokenId"])
+	assert.Equal(t, "Approval", logs[2].Event.Name())
+	assert.Equal(t, types.MustAddressFromHex("0x2222222222222222222222222222222222222222"), logs[2].Values["spender"])
+}
+
+func TestClient_GetTaggedLogs_InvalidSpecs(t *testing.T) {
+	client, _ := NewClient(WithTransport(newCallMock(t)))
+	from, to := types.BlockNumberFromUint64(10), types.BlockNumberFromUint64(20)
+
+	_, err := client.GetTaggedLogs(context.Background(), from, to, nil)
+	assert.Error(t, err)
+	_, err = client.GetTaggedLogs(context.Background(), from, to, []LogSpec{{}})
+	assert.Error(t, err)
+	_, err = client.GetTaggedLogs(context.Background(), from, to, []LogSpec{{
+		Events: []*abi.Event{abi.MustParseEvent("event Foo(uint256 indexed a) anonymous")},
+	}})
+	assert.Error(t, err)
+}
+
+func TestClient_FeeHistory(t *testing.T) {
+	callMock := newCallMock(t, callMockEntry{
+		ArgMethod: "eth_feeHistory",
+		ArgParams: `["0x2", "latest", [25, 75]]`,
+		RetResult: `{
+			"oldestBlock": "0x10",
+			"reward": [["0x1", "0x5"], ["0x3", "0x7"]],
+			"baseFeePerGas": ["0xa", "0xb", "0xc"],
+			"gasUsedRatio": [0.5, 0.25]
+		}`,
+	})
+	client, _ := NewClient(WithTransport(callMock))
+
+	feeHistory, err := client.FeeHistory(context.Background(), 2, types.LatestBlockNumber, []float64{25, 75})
+	require.NoError(t, err)
+	require.Empty(t, callMock.CallMocks)
+	assert.Equal(t, uint64(16), feeHistory.OldestBlock)
+	assert.Equal(t, []float64{25, 75}, feeHistory.RewardPercentiles)
+	assert.Equal(t, []float64{0.5, 0.25}, feeHistory.GasUsedRatio)
+	assert.Equal(t, big.NewInt(12), feeHistory.NextBaseFee())
+	assert.Equal(t, big.NewInt(2), feeHistory.SuggestTip(25))
+	assert.Equal(t, big.NewInt(6), feeHistory.SuggestTip(75))
+}
+
+func TestClient_SupportsEIP1559(t *testing.T) {
+	tests := []struct {
+		block   string
+		want    bool
+		wantErr bool
+	}{
+		{block: `{"number":"0x1","baseFeePerGas":"0x64","transactions":[]}`, want: true},
+		{block: `{"number":"0x1","baseFeePerGas":"0x0","transactions":[]}`, want: true},
+		{block: `{"number":"0x1","transactions":[]}`, want: false},
+		{wantErr: true},
+	}
+	for n, tt := range tests {
+		t.Run(fmt.Sprintf("case-%d", n+1), func(t *testing.T) {
+			entry := callMockEntry{
+				ArgMethod: "eth_getBlockByNumber",
+				ArgParams: `["latest",false]`,
+				RetResult: tt.block,
+			}
+			if tt.wantErr {
+				entry.RetErr = errors.New("foo")
+			}
+			callMock := newCallMock(t, entry)
+			client, _ := NewClient(WithTransport(callMock))
+
+			ok, err := client.SupportsEIP1559(context.Background())
+			require.Empty(t, callMock.CallMocks)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, ok)
+		})
+	}
+}
+
+func TestClient_SuggestGasTipCap(t *testing.T) {
+	callMock := newCallMock(t, callMockEntry{
+		ArgMethod: "eth_maxPriorityFeePerGas",
+		RetResult: `"0xa"`,
+	})
+	client, _ := NewClient(WithTransport(callMock))
+
+	tip, err := client.SuggestGasTipCap(context.Background())
+	require.NoError(t, err)
+	require.Empty(t, callMock.CallMocks)
+	assert.Equal(t, big.NewInt(10), tip)
+}
+
+func TestClient_SuggestGasFeeCap(t *testing.T) {
+	t.Run("eip-1559", func(t *testing.T) {
+		callMock := newCallMock(t,
+			callMockEntry{
+				ArgMethod: "eth_getBlockByNumber",
+				ArgParams: `["latest",false]`,
+				RetResult: `{"number":"0x1","baseFeePerGas":"0x64","transactions":[]}`,
+			},
+			callMockEntry{
+				ArgMethod: "eth_maxPriorityFeePerGas",
+				RetResult: `"0xa"`,
+			},
+		)
+		client, _ := NewClient(WithTransport(callMock))
+
+		feeCap, err := client.SuggestGasFeeCap(context.Background())
+		require.NoError(t, err)
+		require.Empty(t, callMock.CallMocks)
+		assert.Equal(t, big.NewInt(210), feeCap)
+	})
+	t.Run("legacy", func(t *testing.T) {
+		callMock := newCallMock(t,
+			callMockEntry{
+				ArgMethod: "eth_getBlockByNumber",
+				ArgParams: `["latest",false]`,
+				RetResult: `{"number":"0x1","transactions":[]}`,
+			},
+		)
+		client, _ := NewClient(WithTransport(callMock))
+
+		_, err := client.SuggestGasFeeCap(context.Background())
+		require.Error(t, err)
+		require.Empty(t, callMock.CallMocks)
+	})
+}
+
+func TestClient_SuggestFeeData(t *testing.T) {
+	t.Run("eip-1559", func(t *testing.T) {
+		callMock := newCallMock(t,
+			callMockEntry{
+				ArgMethod: "eth_getBlockByNumber",
+				ArgParams: `["latest",false]`,
+				RetResult: `{"number":"0x1","baseFeePerGas":"0x64","transactions":[]}`,
+			},
+			callMockEntry{
+				ArgMethod: "eth_maxPriorityFeePerGas",
+				RetResult: `"0xa"`,
+			},
+		)
+		client, _ := NewClient(WithTransport(callMock))
+
+		fd, err := client.SuggestFeeData(context.Background())
+		require.NoError(t, err)
+		require.Empty(t, callMock.CallMocks)
+		assert.Nil(t, fd.GasPrice)
+		assert.Equal(t, big.NewInt(210), fd.MaxFeePerGas)
+		assert.Equal(t, big.NewInt(10), fd.MaxPriorityFeePerGas)
+	})
+	t.Run("legacy", func(t *testing.T) {
+		callMock := newCallMock(t,
+			callMockEntry{
+				ArgMethod: "eth_getBlockByNumber",
+				ArgParams: `["latest",false]`,
+				RetResult: `{"number":"0x1","transactions":[]}`,
+			},
+			callMockEntry{
+				ArgMethod: "eth_gasPrice",
+				RetResult: `"0x3b9aca00"`,
+			},
+		)
+		client, _ := NewClient(WithTransport(callMock))
+
+		fd, err := client.SuggestFeeData(context.Background())
+		require.NoError(t, err)
+		require.Empty(t, callMock.CallMocks)
+		assert.Equal(t, big.NewInt(1000000000), fd.GasPrice)
+		assert.Nil(t, fd.MaxFeePerGas)
+		assert.Nil(t, fd.MaxPriorityFeePerGas)
+	})
+}
+
+func TestClient_Uncles(t *testing.T) {
+	callMock := newCallMock(t,
+		callMockEntry{
+			ArgMethod: "eth_getUncleCountByBlockHash",
+			ArgParams: `["0x1111111111111111111111111111111111111111111111111111111111111111"]`,
+			RetResult: `"0x2"`,
+		},
+		callMockEntry{
+			ArgMethod: "eth_getUncleByBlockHashAndIndex",
+			ArgParams: `["0x1111111111111111111111111111111111111111111111111111111111111111","0x0"]`,
+			RetResult: `{"number":"0x1","hash":"0x2222222222222222222222222222222222222222222222222222222222222222"}`,
+		},
+		callMockEntry{
+			ArgMethod: "eth_getUncleByBlockHashAndIndex",
+			ArgParams: `["0x1111111111111111111111111111111111111111111111111111111111111111","0x1"]`,
+			RetResult: `{"number":"0x1","hash":"0x3333333333333333333333333333333333333333333333333333333333333333"}`,
+		},
+	)
+	client, _ := NewClient(WithTransport(callMock))
+
+	uncles, err := client.Uncles(context.Background(), types.MustHashFromHex("0x1111111111111111111111111111111111111111111111111111111111111111", types.PadNone))
+	require.NoError(t, err)
+	require.Empty(t, callMock.CallMocks)
+	require.Len(t, uncles, 2)
+	assert.Equal(t, "0x2222222222222222222222222222222222222222222222222222222222222222", uncles[0].Hash.String())
+	assert.Equal(t, "0x3333333333333333333333333333333333333333333333333333333333333333", uncles[1].Hash.String())
+}
+
+func TestClient_GetTransactionReceipts(t *testing.T) {
+	httpMock := newHTTPMock()
+	client, _ := NewClient(WithTransport(httpMock))
+
+	// Responses are returned in a different order than requests to verify
+	// that they are matched by ID.
+	httpMock.ResponseMock = &http.Response{
+		StatusCode: 200,
+		Body: io.NopCloser(bytes.NewBufferString(`[
+			{"jsonrpc":"2.0","id":2,"result":null},
+			{"jsonrpc":"2.0","id":1,"result":{"transactionHash":"0x1111111111111111111111111111111111111111111111111111111111111111","status":"0x1"}}
+		]`)),
+	}
+
+	receipts, err := client.GetTransactionReceipts(context.Background(), []types.Hash{
+		types.MustHashFromHex("0x1111111111111111111111111111111111111111111111111111111111111111", types.PadNone),
+		types.MustHashFromHex("0x2222222222222222222222222222222222222222222222222222222222222222", types.PadNone),
+	})
+	require.NoError(t, err)
+	assert.JSONEq(t, `[
+		{"jsonrpc":"2.0","id":1,"method":"eth_getTransactionReceipt","params":["0x1111111111111111111111111111111111111111111111111111111111111111"]},
+		{"jsonrpc":"2.0","id":2,"method":"eth_getTransactionReceipt","params":["0x2222222222222222222222222222222222222222222222222222222222222222"]}
+	]`, readBody(httpMock.Request))
+	require.Len(t, receipts, 2)
+	require.NotNil(t, receipts[0])
+	assert.Equal(t, "0x1111111111111111111111111111111111111111111111111111111111111111", receipts[0].TransactionHash.String())
+	assert.Nil(t, receipts[1])
+}
+
+func TestClient_GetTransactionReceipts_NoBatch(t *testing.T) {
+	callMock := newCallMock(t,
+		callMockEntry{
+			ArgMethod: "eth_getTransactionReceipt",
+			ArgParams: `["0x1111111111111111111111111111111111111111111111111111111111111111"]`,
+			RetResult: `null`,
+		},
+		callMockEntry{
+			ArgMethod: "eth_getTransactionReceipt",
+			ArgParams: `["0x2222222222222222222222222222222222222222222222222222222222222222"]`,
+			RetErr:    errors.New("foo"),
+		},
+	)
+	client, _ := NewClient(WithTransport(callMock))
+
+	_, err := client.GetTransactionReceipts(context.Background(), []types.Hash{
+		types.MustHashFromHex("0x1111111111111111111111111111111111111111111111111111111111111111", types.PadNone),
+		types.MustHashFromHex("0x2222222222222222222222222222222222222222222222222222222222222222", types.PadNone),
+	})
+	require.Error(t, err)
+	require.Empty(t, callMock.CallMocks)
+	assert.Contains(t, err.Error(), "0x2222222222222222222222222222222222222222222222222222222222222222")
+}
+
+func TestClient_BlockWithReceipts(t *testing.T) {
+	const (
+		blockHash = "0xaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa"
+		tx1       = "0x1111111111111111111111111111111111111111111111111111111111111111"
+		tx2       = "0x2222222222222222222222222222222222222222222222222222222222222222"
+		block     = `{"number":"0x10","hash":"` + blockHash + `","transactions":[{"hash":"` + tx1 + `"},{"hash":"` + tx2 + `"}]}`
+		receipt1  = `{"transactionHash":"` + tx1 + `","transactionIndex":"0x0","blockHash":"` + blockHash + `"}`
+		receipt2  = `{"transactionHash":"` + tx2 + `","transactionIndex":"0x1","blockHash":"` + blockHash + `"}`
+	)
+	tests := []struct {
+		name    string
+		mocks   []callMockEntry
+		wantErr bool
+	}{
+		{
+			name: "block receipts",
+			mocks: []callMockEntry{
+				{ArgMethod: "eth_getBlockByNumber", ArgParams: `["latest", true]`, RetResult: block},
+				// Receipts in a different order than transactions:
+				{ArgMethod: "eth_getBlockReceipts", ArgParams: `["0x10"]`, RetResult: `[` + receipt2 + `,` + receipt1 + `]`},
+			},
+		},
+		{
+			name: "fallback to transaction receipts",
+			mocks: []callMockEntry{
+				{ArgMethod: "eth_getBlockByNumber", ArgParams: `["latest", true]`, RetResult: block},
+				{ArgMethod: "eth_getBlockReceipts", ArgParams: `["0x10"]`, RetErr: &transport.RPCError{Code: transport.ErrCodeMethodNotFound}},
+				{ArgMethod: "eth_getTransactionReceipt", ArgParams: `["` + tx1 + `"]`, RetResult: receipt1},
+				{ArgMethod: "eth_getTransactionReceipt", ArgParams: `["` + tx2 + `"]`, RetResult: receipt2},
+			},
+		},
+		{
+			name: "missing receipt",
+			mocks: []callMockEntry{
+				{ArgMethod: "eth_getBlockByNumber", ArgParams: `["latest", true]`, RetResult: block},
+				{ArgMethod: "eth_getBlockReceipts", ArgParams: `["0x10"]`, RetResult: `[` + receipt1 + `]`},
+			},
+			wantErr: true,
+		},
+		{
+			name: "receipt from another block",
+			mocks: []callMockEntry{
+				{ArgMethod: "eth_getBlockByNumber", ArgParams: `["latest", true]`, RetResult: block},
+				{ArgMethod: "eth_getBlockReceipts", ArgParams: `["0x10"]`, RetResult: `[` + receipt1 + `,{"transactionHash":"` + tx2 + `","transactionIndex":"0x1","blockHash":"` + tx1 + `"}]`},
+			},
+			wantErr: true,
+		},
+		{
+			name: "block receipts error",
+			mocks: []callMockEntry{
+				{ArgMethod: "eth_getBlockByNumber", ArgParams: `["latest", true]`, RetResult: block},
+				{ArgMethod: "eth_getBlockReceipts", ArgParams: `["0x10"]`, RetErr: errors.New("foo")},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			callMock := newCallMock(t, tt.mocks...)
+			client, _ := NewClient(WithTransport(callMock))
+
+			b, receipts, err := client.BlockWithReceipts(context.Background(), types.LatestBlockNumber)
+			require.Empty(t, callMock.CallMocks)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Len(t, b.Transactions, 2)
+			require.Len(t, receipts, 2)
+			for i, tx := range b.Transactions {
+				assert.Equal(t, *tx.Hash, receipts[i].TransactionHash)
+			}
+		})
+	}
+}
+
+func TestClient_WaitForConfirmations(t *testing.T) {
+	const hash = "0x1111111111111111111111111111111111111111111111111111111111111111"
+	callMock := newCallMock(t,
+		// Not mined yet:
+		callMockEntry{
+			ArgMethod: "eth_getTransactionReceipt",
+			ArgParams: `["` + hash + `"]`,
+			RetResult: `null`,
+		},
+		// Mined in block 0x10, 1 confirmation:
+		callMockEntry{
+			ArgMethod: "eth_getTransactionReceipt",
+			ArgParams: `["` + hash + `"]`,
+			RetResult: `{"transactionHash":"` + hash + `","blockNumber":"0x10"}`,
+		},
+		callMockEntry{
+			ArgMethod: "eth_blockNumber",
+			RetResult: `"0x10"`,
+		},
+		// Removed by a reorg:
+		callMockEntry{
+			ArgMethod: "eth_getTransactionReceipt",
+			ArgParams: `["` + hash + `"]`,
+			RetResult: `null`,
+		},
+		// Mined again in block 0x12, 2 confirmations:
+		callMockEntry{
+			ArgMethod: "eth_getTransactionReceipt",
+			ArgParams: `["` + hash + `"]`,
+			RetResult: `{"transactionHash":"` + hash + `","blockNumber":"0x12"}`,
+		},
+		callMockEntry{
+			ArgMethod: "eth_blockNumber",
+			RetResult: `"0x13"`,
+		},
+		// 3 confirmations:
+		callMockEntry{
+			ArgMethod: "eth_getTransactionReceipt",
+			ArgParams: `["` + hash + `"]`,
+			RetResult: `{"transactionHash":"` + hash + `","blockNumber":"0x12"}`,
+		},
+		callMockEntry{
+			ArgMethod: "eth_blockNumber",
+			RetResult: `"0x14"`,
+		},
+	)
+	client, _ := NewClient(WithTransport(callMock))
+
+	receipt, err := client.WaitForConfirmations(context.Background(), types.MustHashFromHex(hash, types.PadNone), 3, time.Millisecond)
+	require.NoError(t, err)
+	require.Empty(t, callMock.CallMocks)
+	assert.Equal(t, big.NewInt(0x12), receipt.BlockNumber)
+}
+
+func TestClient_WaitForConfirmations_ContextCanceled(t *testing.T) {
+	callMock := newCallMock(t,
+		callMockEntry{
+			ArgMethod: "eth_getTransactionReceipt",
+			RetResult: `null`,
+		},
+	)
+	client, _ := NewClient(WithTransport(callMock))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err := client.WaitForConfirmations(ctx, types.Hash{}, 1, time.Hour)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestClient_PendingNonce(t *testing.T) {
+	callMock := newCallMock(t,
+		callMockEntry{
+			ArgMethod: "eth_getTransactionCount",
+			ArgParams: `["0x1111111111111111111111111111111111111111","pending"]`,
+			RetResult: `"0x5"`,
+		},
+	)
+	client, _ := NewClient(WithTransport(callMock))
+
+	nonce, err := client.PendingNonce(context.Background(), types.MustAddressFromHex("0x1111111111111111111111111111111111111111"))
+	require.NoError(t, err)
+	require.Empty(t, callMock.CallMocks)
+	assert.Equal(t, uint64(5), nonce)
+}
+
+func TestClient_CallOmitFrom(t *testing.T) {
+	callMock := newCallMock(t,
+		callMockEntry{
+			ArgMethod: "eth_call",
+			ArgParams: `[{"to":"0x2222222222222222222222222222222222222222","data":"0x01"},"latest"]`,
+			RetResult: `"0x02"`,
+		},
+	)
+	client, _ := NewClient(
+		WithTransport(callMock),
+		WithDefaultAddress(types.MustAddressFromHex("0x1111111111111111111111111111111111111111")),
+		WithOmitCallFrom(),
+	)
+
+	call := types.NewCall().
+		SetFrom(types.MustAddressFromHex("0x3333333333333333333333333333333333333333")).
+		SetTo(types.MustAddressFromHex("0x2222222222222222222222222222222222222222")).
+		SetInput([]byte{1})
+	res, _, err := client.Call(context.Background(), call, types.LatestBlockNumber)
+	require.NoError(t, err)
+	require.Empty(t, callMock.CallMocks)
+	assert.Equal(t, []byte{2}, res)
+	assert.NotNil(t, call.From) // The original call must not be modified.
+}
+
+func TestClient_CallDefaultAddress(t *testing.T) {
+	callMock := newCallMock(t,
+		callMockEntry{
+			ArgMethod: "eth_call",
+			ArgParams: `[{"from":"0x1111111111111111111111111111111111111111","to":"0x2222222222222222222222222222222222222222","data":"0x01"},"latest"]`,
+			RetResult: `"0x02"`,
+		},
+	)
+	client, _ := NewClient(
+		WithTransport(callMock),
+		WithDefaultAddress(types.MustAddressFromHex("0x1111111111111111111111111111111111111111")),
+	)
+
+	call := types.NewCall().
+		SetTo(types.MustAddressFromHex("0x2222222222222222222222222222222222222222")).
+		SetInput([]byte{1})
+	res, _, err := client.Call(context.Background(), call, types.LatestBlockNumber)
+	require.NoError(t, err)
+	require.Empty(t, callMock.CallMocks)
+	assert.Equal(t, []byte{2}, res)
+	assert.Nil(t, call.From) // The original call must not be modified.
+}
+
+func TestClient_CallWithoutDefaultAddress(t *testing.T) {
+	callMock := newCallMock(t,
+		callMockEntry{
+			ArgMethod: "eth_call",
+			ArgParams: `[{"to":"0x2222222222222222222222222222222222222222","data":"0x01"},"latest"]`,
+			RetResult: `"0x02"`,
+		},
+		callMockEntry{
+			ArgMethod: "eth_call",
+			ArgParams: `[{"from":"0x3333333333333333333333333333333333333333","to":"0x2222222222222222222222222222222222222222","data":"0x01"},"latest"]`,
+			RetResult: `"0x03"`,
+		},
+	)
+	client, _ := NewClient(
+		WithTransport(callMock),
+		WithDefaultAddress(types.MustAddressFromHex("0x1111111111111111111111111111111111111111")),
+		WithoutCallDefaultAddress(),
+	)
+
+	call := types.NewCall().
+		SetTo(types.MustAddressFromHex("0x2222222222222222222222222222222222222222")).
+		SetInput([]byte{1})
+	res, _, err := client.Call(context.Background(), call, types.LatestBlockNumber)
+	require.NoError(t, err)
+	assert.Equal(t, []byte{2}, res)
+
+	// An explicitly set address is still sent.
+	call.SetFrom(types.MustAddressFromHex("0x3333333333333333333333333333333333333333"))
+	res, _, err = client.Call(context.Background(), call, types.LatestBlockNumber)
+	require.NoError(t, err)
+	assert.Equal(t, []byte{3}, res)
+	require.Empty(t, callMock.CallMocks)
+}
+
+func TestClient_WithLogger(t *testing.T) {
+	callMock := newCallMock(t,
+		callMockEntry{
+			ArgMethod: "eth_getBalance",
+			RetResult: `"0x2a"`,
+		},
+		callMockEntry{
+			ArgMethod: "eth_call",
+			RetErr:    errors.New("execution reverted"),
+		},
+		callMockEntry{
+			ArgMethod: "eth_getCode",
+			RetResult: `"0x` + strings.Repeat("ff", maxLogResponseLen) + `"`,
+		},
+	)
+	logger := &loggerMock{}
+	client, _ := NewClient(WithTransport(callMock), WithLogger(logger))
+
+	addr := types.MustAddressFromHex("0x1111111111111111111111111111111111111111")
+	_, err := client.GetBalance(context.Background(), addr, types.LatestBlockNumber)
+	require.NoError(t, err)
+	_, _, err = client.Call(context.Background(), &types.Call{To: &addr}, types.LatestBlockNumber)
+	require.Error(t, err)
+	_, err = client.GetCode(context.Background(), addr, types.LatestBlockNumber)
+	require.NoError(t, err)
+
+	require.Len(t, logger.Entries, 3)
+	assert.Equal(t, "eth_getBalance", logger.Entries[0].Method)
+	assert.Equal(t, `["0x1111111111111111111111111111111111111111","latest"]`, logger.Entries[0].Params)
+	assert.Equal(t, `"0x2a"`, logger.Entries[0].Response)
+	assert.NoError(t, logger.Entries[0].Error)
+	assert.Equal(t, "eth_call", logger.Entries[1].Method)
+	assert.Empty(t, logger.Entries[1].Response)
+	assert.EqualError(t, logger.Entries[1].Error, "execution reverted")
+	assert.Equal(t, "eth_getCode", logger.Entries[2].Method)
+	assert.Len(t, logger.Entries[2].Response, maxLogResponseLen+len("..."))
+	assert.True(t, strings.HasSuffix(logger.Entries[2].Response, "..."))
+}
+
+func TestClient_WithLoggerSubscription(t *testing.T) {
+	streamMock := newStreamMock(t)
+	client, _ := NewClient(WithTransport(streamMock), WithLogger(&loggerMock{}))
+
+	_, ok := client.transport.(transport.SubscriptionTransport)
+	assert.True(t, ok)
+}
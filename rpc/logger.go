@@ -0,0 +1,122 @@
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/defiweb/go-eth/rpc/transport"
+)
+
+// maxLogResponseLen is the maximum length of the response logged by the
+// Logger. Longer responses are truncated.
+const maxLogResponseLen = 1024
+
+// Logger is the interface used by the client to log RPC calls.
+//
+// Log entries never include transport details, such as the URL or HTTP
+// headers, so credentials configured there are not exposed.
+type Logger interface {
+	// Log is called after each RPC call.
+	Log(ctx context.Context, entry LogEntry)
+}
+
+// LogEntry describes a single RPC call.
+type LogEntry struct {
+	Method   string        // Method is the name of the RPC method.
+	Params   string        // Params is the JSON encoded list of parameters.
+	Duration time.Duration // Duration is the time it took to perform the call.
+	Response string        // Response is the JSON encoded result, truncated if too long. Empty if the call failed.
+	Error    error         // Error is the error returned by the call, if any.
+}
+
+// loggingTransport is a transport that logs every call using the given
+// logger.
+type loggingTransport struct {
+	transport transport.Transport
+	logger    Logger
+}
+
+// loggingSubscriptionTransport is a loggingTransport that also supports
+// subscriptions.
+type loggingSubscriptionTransport struct {
+	*loggingTransport
+	subscription transport.SubscriptionTransport
+}
+
+// newLoggingTransport wraps the given transport so that every call is logged
+// using the given logger. If the transport supports subscriptions, the
+// returned transport supports them as well.
+func newLoggingTransport(t transport.Transport, logger Logger) transport.Transport {
+	lt := &loggingTransport{transport: t, logger: logger}
+	if st, ok := t.(transport.SubscriptionTransport); ok {
+		return &loggingSubscriptionTransport{loggingTransport: lt, subscription: st}
+	}
+	return lt
+}
+
+// Call implements the transport.Transport interface.
+func (t *loggingTransport) Call(ctx context.Context, result any, method string, args ...any) error {
+	start := time.Now()
+	err := t.transport.Call(ctx, result, method, args...)
+	entry := LogEntry{
+		Method:   method,
+		Params:   marshalLogValue(args),
+		Duration: time.Since(start),
+		Error:    err,
+	}
+	if err == nil && result != nil {
+		entry.Response = truncateLogValue(marshalLogValue(result))
+	}
+	t.logger.Log(ctx, entry)
+	return err
+}
+
+// CallBatch implements the transport.BatchTransport interface. Every call in
+// the batch is logged separately, with the duration of the whole batch.
+func (t *loggingTransport) CallBatch(ctx context.Context, calls []transport.BatchCall) error {
+	start := time.Now()
+	err := transport.CallBatch(ctx, t.transport, calls)
+	dur := time.Since(start)
+	for _, call := range calls {
+		entry := LogEntry{
+			Method:   call.Method,
+			Params:   marshalLogValue(call.Args),
+			Duration: dur,
+			Error:    call.Error,
+		}
+		if err != nil {
+			entry.Error = err
+		}
+		if entry.Error == nil && call.Result != nil {
+			entry.Response = truncateLogValue(marshalLogValue(call.Result))
+		}
+		t.logger.Log(ctx, entry)
+	}
+	return err
+}
+
+// Subscribe implements the transport.SubscriptionTransport interface.
+func (t *loggingSubscriptionTransport) Subscribe(ctx context.Context, method string, args ...any) (chan json.RawMessage, string, error) {
+	return t.subscription.Subscribe(ctx, method, args...)
+}
+
+// Unsubscribe implements the transport.SubscriptionTransport interface.
+func (t *loggingSubscriptionTransport) Unsubscribe(ctx context.Context, id string) error {
+	return t.subscription.Unsubscribe(ctx, id)
+}
+
+func marshalLogValue(v any) string {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return ""
+	}
+	return string(b)
+}
+
+func truncateLogValue(s string) string {
+	if len(s) <= maxLogResponseLen {
+		return s
+	}
+	return s[:maxLogResponseLen] + "..."
+}
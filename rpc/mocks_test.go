@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"errors"
 	"net/http"
+	"sync"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -40,9 +41,46 @@ func newHTTPMock() *httpMock {
 	return h
 }
 
-type streamMock struct {
+// callMock is a transport that expects a sequence of calls. Each call is
+// checked against the next expected call and its result is unmarshalled from
+// the mocked JSON.
+type callMock struct {
 	t *testing.T
 
+	CallMocks []callMockEntry
+}
+
+type callMockEntry struct {
+	ArgMethod string
+	ArgParams string // JSON array of params, ignored if empty.
+	RetResult string // JSON result.
+	RetErr    error
+}
+
+func newCallMock(t *testing.T, calls ...callMockEntry) *callMock {
+	return &callMock{t: t, CallMocks: calls}
+}
+
+func (c *callMock) Call(_ context.Context, result any, method string, args ...any) error {
+	require.NotEmpty(c.t, c.CallMocks)
+	m := c.CallMocks[0]
+	c.CallMocks = c.CallMocks[1:]
+	require.Equal(c.t, m.ArgMethod, method)
+	if m.ArgParams != "" {
+		params, err := json.Marshal(args)
+		require.NoError(c.t, err)
+		require.JSONEq(c.t, m.ArgParams, string(params))
+	}
+	if m.RetErr != nil {
+		return m.RetErr
+	}
+	return json.Unmarshal([]byte(m.RetResult), result)
+}
+
+type streamMock struct {
+	t  *testing.T
+	mu sync.Mutex
+
 	SubscribeMocks   []subscribeMock
 	UnsubscribeMocks []unsubscribeMock
 }
@@ -69,6 +107,8 @@ func (s *streamMock) Call(_ context.Context, _ any, _ string, _ ...any) error {
 }
 
 func (s *streamMock) Subscribe(_ context.Context, method string, args ...any) (ch chan json.RawMessage, id string, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
 	require.NotEmpty(s.t, s.SubscribeMocks)
 	m := s.SubscribeMocks[0]
 	s.SubscribeMocks = s.SubscribeMocks[1:]
@@ -80,12 +120,20 @@ func (s *streamMock) Subscribe(_ context.Context, method string, args ...any) (c
 	return m.RetCh, m.RetID, m.RetErr
 }
 
+// Unsubscribe consumes the first unsubscribe mock with a matching ID.
+// Subscriptions are unsubscribed from their own goroutines, so the order of
+// the calls is not guaranteed.
 func (s *streamMock) Unsubscribe(_ context.Context, id string) error {
-	require.NotEmpty(s.t, s.UnsubscribeMocks)
-	m := s.UnsubscribeMocks[0]
-	s.UnsubscribeMocks = s.UnsubscribeMocks[1:]
-	require.Equal(s.t, m.ArgID, id)
-	return m.ResultErr
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i, m := range s.UnsubscribeMocks {
+		if m.ArgID == id {
+			s.UnsubscribeMocks = append(s.UnsubscribeMocks[:i:i], s.UnsubscribeMocks[i+1:]...)
+			return m.ResultErr
+		}
+	}
+	require.Failf(s.t, "unexpected unsubscribe", "subscription ID: %s", id)
+	return nil
 }
 
 type keyMock struct {
@@ -118,3 +166,11 @@ func (k *keyMock) VerifyHash(ctx context.Context, hash types.Hash, sig types.Sig
 func (k keyMock) VerifyMessage(ctx context.Context, data []byte, sig types.Signature) bool {
 	return false
 }
+
+type loggerMock struct {
+	Entries []LogEntry
+}
+
+func (l *loggerMock) Log(_ context.Context, entry LogEntry) {
+	l.Entries = append(l.Entries, entry)
+}
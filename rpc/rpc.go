@@ -70,6 +70,13 @@ type RPC interface {
 	// address.
 	GetStorageAt(ctx context.Context, account types.Address, key types.Hash, block types.BlockNumber) (*types.Hash, error)
 
+	// GetProof performs eth_getProof RPC call.
+	//
+	// It returns the account and storage values of the given account
+	// including the Merkle proofs. The proof can be verified using the
+	// types.VerifyAccountProof function.
+	GetProof(ctx context.Context, account types.Address, keys []types.Hash, block types.BlockNumber) (*types.AccountProof, error)
+
 	// GetTransactionCount performs eth_getTransactionCount RPC call.
 	//
 	// It returns the number of transactions sent from the given address.
@@ -225,7 +232,8 @@ type RPC interface {
 
 	// GetLogs performs eth_getLogs RPC call.
 	//
-	// It returns logs that match the given query.
+	// It returns logs that match the given query. Logs are returned as-is,
+	// in the order provided by the node.
 	GetLogs(ctx context.Context, query *types.FilterLogsQuery) ([]types.Log, error)
 
 	// MaxPriorityFeePerGas performs eth_maxPriorityFeePerGas RPC call.
@@ -233,6 +241,11 @@ type RPC interface {
 	// It returns the estimated maximum priority fee per gas.
 	MaxPriorityFeePerGas(ctx context.Context) (*big.Int, error)
 
+	// BlobBaseFee performs eth_blobBaseFee RPC call.
+	//
+	// It returns the expected base fee per blob gas for the next block.
+	BlobBaseFee(ctx context.Context) (*big.Int, error)
+
 	// SubscribeLogs performs eth_subscribe RPC call with "logs" subscription
 	// type.
 	//
@@ -241,10 +254,27 @@ type RPC interface {
 	// Subscription channel will be closed when the context is canceled.
 	SubscribeLogs(ctx context.Context, query *types.FilterLogsQuery) (<-chan types.Log, error)
 
+	// SubscribeMultipleLogs works like SubscribeLogs, but subscribes to logs
+	// for multiple queries at once. A separate subscription is created for
+	// each query over the same transport, and the received logs are merged
+	// into a single channel. Each log is tagged with the index of the query
+	// that matched it. If a log matches multiple queries, it is received
+	// once for each of them.
+	//
+	// Optional SubscribeOptions may be given to buffer the subscription
+	// messages. They are applied to each of the subscriptions. Only the
+	// first options are used.
+	//
+	// Subscription channel will be closed when the context is canceled or
+	// when any of the subscriptions is closed by the transport.
+	SubscribeMultipleLogs(ctx context.Context, queries []types.FilterLogsQuery, opts ...SubscribeOptions) (<-chan QueryLog, error)
+
 	// SubscribeNewHeads performs eth_subscribe RPC call with "newHeads"
 	// subscription type.
 	//
 	// It creates a subscription that will send new block headers.
+	// Only the header fields of the returned blocks are populated, the
+	// Transactions and TransactionHashes fields are always nil.
 	//
 	// Subscription channel will be closed when the context is canceled.
 	SubscribeNewHeads(ctx context.Context) (<-chan types.Block, error)
@@ -0,0 +1,149 @@
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"sync/atomic"
+
+	"github.com/defiweb/go-eth/rpc/transport"
+)
+
+// Subscription represents an active subscription created by one of the
+// Subscription methods of the client.
+//
+// The subscription ends when Unsubscribe is called, the context used to
+// create the subscription is canceled or the transport closes the
+// subscription. Once it ends, the channel returned by Events is closed.
+type Subscription[T any] struct {
+	dropped uint64 // Accessed atomically, must be first for 64-bit alignment.
+	opts    SubscribeOptions
+	ch      chan T
+	cancel  context.CancelFunc
+	done    chan struct{}
+	once    sync.Once
+}
+
+// SubscribeOptions contains options for subscriptions created by one of the
+// Subscription methods of the client.
+type SubscribeOptions struct {
+	// BufferSize is the size of the channel returned by Events.
+	//
+	// If zero, the channel is unbuffered and a slow consumer blocks the
+	// reading of subscription messages, which may stall the connection.
+	//
+	// If positive, the messages are never blocked on. When the buffer is
+	// full, one message is dropped, according to the DropOldest option. The
+	// number of dropped messages is returned by Subscription.Dropped.
+	BufferSize int
+
+	// DropOldest specifies which message is dropped when the buffer is full.
+	// If true, the oldest message in the buffer is dropped to make room for
+	// the new one. Otherwise, the new message is dropped.
+	DropOldest bool
+}
+
+// Events returns the channel that receives subscription messages.
+func (s *Subscription[T]) Events() <-chan T {
+	return s.ch
+}
+
+// Dropped returns the number of messages dropped because the buffer was
+// full. It is always zero if the SubscribeOptions.BufferSize is zero.
+func (s *Subscription[T]) Dropped() uint64 {
+	return atomic.LoadUint64(&s.dropped)
+}
+
+// Unsubscribe ends the subscription. It stops the goroutine that reads the
+// subscription messages and waits until the channel returned by Events is
+// closed. It is safe to call Unsubscribe multiple times.
+func (s *Subscription[T]) Unsubscribe() {
+	s.once.Do(s.cancel)
+	<-s.done
+}
+
+// newSubscription creates a subscription to the given method. Subscription
+// messages are decoded using the given function.
+func newSubscription[T any](ctx context.Context, t transport.Transport, opts SubscribeOptions, decode func(json.RawMessage) (T, error), method string, params ...any) (*Subscription[T], error) {
+	if opts.BufferSize < 0 {
+		return nil, errors.New("subscription buffer size cannot be negative")
+	}
+	st, ok := t.(transport.SubscriptionTransport)
+	if !ok {
+		return nil, errors.New("transport does not support subscriptions")
+	}
+	rawCh, subID, err := st.Subscribe(ctx, method, params...)
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	s := &Subscription[T]{
+		opts:   opts,
+		ch:     make(chan T, opts.BufferSize),
+		cancel: cancel,
+		done:   make(chan struct{}),
+	}
+	go s.routine(ctx, st, subID, rawCh, decode)
+	return s, nil
+}
+
+//nolint:errcheck
+func (s *Subscription[T]) routine(ctx context.Context, t transport.SubscriptionTransport, subID string, rawCh chan json.RawMessage, decode func(json.RawMessage) (T, error)) {
+	defer close(s.done)
+	defer close(s.ch)
+	defer s.once.Do(s.cancel)
+
+	// The context is already canceled at this point, so a new one is used
+	// to send the unsubscribe request. Transports apply their own timeouts.
+	defer t.Unsubscribe(context.Background(), subID)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case raw, ok := <-rawCh:
+			if !ok {
+				return
+			}
+			msg, err := decode(raw)
+			if err != nil {
+				continue
+			}
+			if s.opts.BufferSize > 0 {
+				s.push(msg)
+				continue
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case s.ch <- msg:
+			}
+		}
+	}
+}
+
+// push sends the message to the buffered events channel without blocking.
+// If the buffer is full, either the oldest or the given message is dropped.
+func (s *Subscription[T]) push(msg T) {
+	select {
+	case s.ch <- msg:
+		return
+	default:
+	}
+	if s.opts.DropOldest {
+		// The consumer may read from the channel concurrently, so
+		// neither the receive nor the send below is guaranteed to
+		// succeed.
+		select {
+		case <-s.ch:
+			atomic.AddUint64(&s.dropped, 1)
+		default:
+		}
+		select {
+		case s.ch <- msg:
+			return
+		default:
+		}
+	}
+	atomic.AddUint64(&s.dropped, 1)
+}
@@ -28,6 +28,13 @@ func (c *Combined) Call(ctx context.Context, result any, method string, args ...
 	return c.calls.Call(ctx, result, method, args...)
 }
 
+// CallBatch implements the BatchTransport interface. If the transport used
+// for regular calls does not support batch requests, the calls are performed
+// one by one.
+func (c *Combined) CallBatch(ctx context.Context, calls []BatchCall) error {
+	return CallBatch(ctx, c.calls, calls)
+}
+
 // Subscribe implements the SubscriptionTransport interface.
 func (c *Combined) Subscribe(ctx context.Context, method string, args ...any) (ch chan json.RawMessage, id string, err error) {
 	return c.subs.Subscribe(ctx, method, args...)
@@ -2,14 +2,26 @@ package transport
 
 import (
 	"bytes"
+	"compress/gzip"
 	"context"
+	"crypto/tls"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
+	"net"
 	"net/http"
+	"net/url"
+	"reflect"
+	"strings"
 	"sync/atomic"
+	"time"
 )
 
+// ErrResponseTooLarge is returned when the size of an HTTP response body
+// exceeds the HTTPOptions.MaxResponseBytes limit.
+var ErrResponseTooLarge = errors.New("response body too large")
+
 // HTTP is a Transport implementation that uses the HTTP protocol.
 type HTTP struct {
 	opts HTTPOptions
@@ -22,11 +34,49 @@ type HTTPOptions struct {
 	URL string
 
 	// HTTPClient is the HTTP client to use. If nil, http.DefaultClient is
-	// used.
+	// used, unless one of the connection tuning options below is set.
+	//
+	// HTTPClient cannot be used together with the connection tuning options.
 	HTTPClient *http.Client
 
+	// MaxIdleConnsPerHost is the maximum number of idle connections kept
+	// for reuse. If zero, http.DefaultMaxIdleConnsPerHost is used.
+	//
+	// Increasing this value improves throughput when many concurrent
+	// requests are sent to the same endpoint.
+	MaxIdleConnsPerHost int
+
+	// IdleConnTimeout is the maximum amount of time an idle connection
+	// is kept open. If zero, the http.DefaultTransport value is used.
+	IdleConnTimeout time.Duration
+
+	// ForceHTTP2 requires the connection to use HTTP/2. Because HTTP/2 is
+	// negotiated during the TLS handshake, the URL must use the https
+	// scheme. Only the HTTP/2 protocol is offered during the handshake, so
+	// if the server does not support it, the connection fails before any
+	// request is sent. Proxy settings from the environment are ignored.
+	ForceHTTP2 bool
+
+	// DisableCompression disables gzip compression of responses.
+	//
+	// By default, the transport sends the "Accept-Encoding: gzip" header and
+	// decompresses gzip encoded responses, which reduces the bandwidth used
+	// by large responses, such as eth_getLogs results or blocks with
+	// transactions. Compressed responses are also decompressed when the
+	// header is set using the HTTPHeader option.
+	DisableCompression bool
+
+	// MaxResponseBytes is the maximum size of a response body in bytes. If
+	// a larger body is received, the request fails with ErrResponseTooLarge.
+	// For compressed responses, the limit applies to the decompressed body.
+	// If zero, the size is not limited.
+	MaxResponseBytes int64
+
 	// HTTPHeader specifies the HTTP headers to send with each request.
 	HTTPHeader http.Header
+
+	// Hook is an optional hook that is called for each request.
+	Hook TransportHook
 }
 
 // NewHTTP creates a new HTTP instance.
@@ -34,7 +84,25 @@ func NewHTTP(opts HTTPOptions) (*HTTP, error) {
 	if opts.URL == "" {
 		return nil, errors.New("URL cannot be empty")
 	}
-	if opts.HTTPClient == nil {
+	if opts.MaxResponseBytes < 0 {
+		return nil, errors.New("MaxResponseBytes cannot be negative")
+	}
+	if opts.ForceHTTP2 {
+		u, err := url.Parse(opts.URL)
+		if err != nil {
+			return nil, fmt.Errorf("invalid URL: %w", err)
+		}
+		if u.Scheme != "https" {
+			return nil, errors.New("HTTP/2 requires an https URL")
+		}
+	}
+	tuned := opts.MaxIdleConnsPerHost != 0 || opts.IdleConnTimeout != 0 || opts.ForceHTTP2
+	switch {
+	case opts.HTTPClient != nil && tuned:
+		return nil, errors.New("HTTPClient cannot be used together with connection tuning options")
+	case tuned:
+		opts.HTTPClient = &http.Client{Transport: newHTTPTransport(opts)}
+	case opts.HTTPClient == nil:
 		opts.HTTPClient = http.DefaultClient
 	}
 	return &HTTP{opts: opts}, nil
@@ -42,46 +110,432 @@ func NewHTTP(opts HTTPOptions) (*HTTP, error) {
 
 // Call implements the Transport interface.
 func (h *HTTP) Call(ctx context.Context, result any, method string, args ...any) error {
+	return callWithHook(h.opts.Hook, method, func() error {
+		return h.call(ctx, result, method, args...)
+	})
+}
+
+// CallBatch implements the BatchTransport interface.
+//
+// The hook, if set, is called for every call in the batch.
+func (h *HTTP) CallBatch(ctx context.Context, calls []BatchCall) error {
+	if len(calls) == 0 {
+		return nil
+	}
+	if h.opts.Hook == nil {
+		return h.callBatch(ctx, calls)
+	}
+	for _, call := range calls {
+		h.opts.Hook.OnRequest(call.Method)
+	}
+	start := time.Now()
+	err := h.callBatch(ctx, calls)
+	dur := time.Since(start)
+	for _, call := range calls {
+		if err != nil {
+			h.opts.Hook.OnResponse(call.Method, dur, err)
+			continue
+		}
+		h.opts.Hook.OnResponse(call.Method, dur, call.Error)
+	}
+	return err
+}
+
+func (h *HTTP) call(ctx context.Context, result any, method string, args ...any) error {
 	id := atomic.AddUint64(&h.id, 1)
 	rpcReq, err := newRPCRequest(&id, method, args)
 	if err != nil {
 		return fmt.Errorf("failed to create RPC request: %w", err)
 	}
-	httpBody, err := json.Marshal(rpcReq)
+	httpRes, err := h.post(ctx, rpcReq)
 	if err != nil {
-		return fmt.Errorf("failed to marshal RPC request: %w", err)
+		return err
+	}
+	defer httpRes.Body.Close()
+	rpcErr, resultErr, err := decodeHTTPResponse(httpRes.Body, result)
+	if errors.Is(err, ErrResponseTooLarge) {
+		return err
 	}
-	httpReq, err := http.NewRequestWithContext(ctx, "POST", h.opts.URL, bytes.NewReader(httpBody))
 	if err != nil {
-		return fmt.Errorf("failed to create HTTP request: %w", err)
+		// If the response is not a valid JSON-RPC response, return the HTTP
+		// status code as the error code.
+		return NewHTTPError(httpRes.StatusCode, nil)
 	}
-	httpReq.Header.Set("Content-Type", "application/json")
-	for k, v := range h.opts.HTTPHeader {
-		httpReq.Header[k] = v
+	if rpcErr != nil {
+		return NewRPCError(
+			rpcErr.Code,
+			rpcErr.Message,
+			rpcErr.Data,
+		)
 	}
-	httpRes, err := h.opts.HTTPClient.Do(httpReq)
+	if resultErr != nil {
+		return fmt.Errorf("failed to unmarshal RPC result: %w", resultErr)
+	}
+	return nil
+}
+
+func (h *HTTP) callBatch(ctx context.Context, calls []BatchCall) error {
+	idx := make(map[uint64]int, len(calls))
+	rpcReqs := make([]rpcRequest, len(calls))
+	for i, call := range calls {
+		id := atomic.AddUint64(&h.id, 1)
+		rpcReq, err := newRPCRequest(&id, call.Method, call.Args)
+		if err != nil {
+			return fmt.Errorf("failed to create RPC request: %w", err)
+		}
+		rpcReqs[i] = rpcReq
+		idx[id] = i
+		calls[i].Error = nil
+	}
+	httpRes, err := h.post(ctx, rpcReqs)
 	if err != nil {
-		return fmt.Errorf("failed to send HTTP request: %w", err)
+		return err
 	}
 	defer httpRes.Body.Close()
-	rpcRes := &rpcResponse{}
-	if err := json.NewDecoder(httpRes.Body).Decode(rpcRes); err != nil {
-		// If the response is not a valid JSON-RPC response, return the HTTP
-		// status code as the error code.
+	var raw json.RawMessage
+	if err := json.NewDecoder(httpRes.Body).Decode(&raw); err != nil {
+		if errors.Is(err, ErrResponseTooLarge) {
+			return err
+		}
 		return NewHTTPError(httpRes.StatusCode, nil)
 	}
-	if rpcRes.Error != nil {
+	// If the whole batch is rejected, the node may respond with a single
+	// error object instead of an array.
+	if len(raw) > 0 && raw[0] == '{' {
+		rpcRes := &rpcResponse{}
+		if err := json.Unmarshal(raw, rpcRes); err != nil || rpcRes.Error == nil {
+			return NewHTTPError(httpRes.StatusCode, nil)
+		}
 		return NewRPCError(
 			rpcRes.Error.Code,
 			rpcRes.Error.Message,
 			rpcRes.Error.Data,
 		)
 	}
-	if result == nil {
-		return nil
+	var rpcRes []rpcResponse
+	if err := json.Unmarshal(raw, &rpcRes); err != nil {
+		return NewHTTPError(httpRes.StatusCode, nil)
+	}
+	done := make([]bool, len(calls))
+	for _, res := range rpcRes {
+		if res.ID == nil {
+			continue
+		}
+		i, ok := idx[*res.ID]
+		if !ok || done[i] {
+			continue
+		}
+		done[i] = true
+		switch {
+		case res.Error != nil:
+			calls[i].Error = NewRPCError(
+				res.Error.Code,
+				res.Error.Message,
+				res.Error.Data,
+			)
+		case calls[i].Result != nil:
+			if err := json.Unmarshal(res.Result, calls[i].Result); err != nil {
+				calls[i].Error = fmt.Errorf("failed to unmarshal RPC result: %w", err)
+			}
+		}
 	}
-	if err := json.Unmarshal(rpcRes.Result, result); err != nil {
-		return fmt.Errorf("failed to unmarshal RPC result: %w", err)
+	for i := range calls {
+		if !done[i] {
+			calls[i].Error = errors.New("missing response for batch call")
+		}
 	}
 	return nil
 }
+
+// post sends the given JSON-RPC request or requests to the endpoint and
+// returns the HTTP response. The caller must close the response body.
+func (h *HTTP) post(ctx context.Context, rpcReq any) (*http.Response, error) {
+	httpBody, err := json.Marshal(rpcReq)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal RPC request: %w", err)
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", h.opts.URL, bytes.NewReader(httpBody))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create HTTP request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	if h.opts.DisableCompression {
+		httpReq.Header.Set("Accept-Encoding", "identity")
+	} else {
+		// Setting the header explicitly disables transparent decompression
+		// in the http.Transport, so responses are decompressed below. This
+		// way, compression works regardless of the HTTP client used.
+		httpReq.Header.Set("Accept-Encoding", "gzip")
+	}
+	for k, v := range h.opts.HTTPHeader {
+		httpReq.Header[k] = v
+	}
+	httpRes, err := h.opts.HTTPClient.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send HTTP request: %w", err)
+	}
+	if strings.EqualFold(httpRes.Header.Get("Content-Encoding"), "gzip") && !httpRes.Uncompressed {
+		gz, err := gzip.NewReader(httpRes.Body)
+		if err != nil {
+			httpRes.Body.Close()
+			return nil, NewHTTPError(httpRes.StatusCode, fmt.Errorf("failed to decompress response: %w", err))
+		}
+		httpRes.Body = &gzipReadCloser{Reader: gz, body: httpRes.Body}
+	}
+	if h.opts.MaxResponseBytes > 0 {
+		httpRes.Body = &limitedReadCloser{
+			Reader: io.LimitReader(httpRes.Body, h.opts.MaxResponseBytes+1),
+			body:   httpRes.Body,
+			limit:  h.opts.MaxResponseBytes,
+		}
+	}
+	return httpRes, nil
+}
+
+// gzipReadCloser decompresses a gzip encoded response body. Closing it
+// closes the underlying body.
+type gzipReadCloser struct {
+	*gzip.Reader
+	body io.ReadCloser
+}
+
+// Close implements the io.Closer interface.
+func (r *gzipReadCloser) Close() error {
+	r.Reader.Close()
+	return r.body.Close()
+}
+
+// limitedReadCloser returns ErrResponseTooLarge if more than limit bytes
+// are read from the response body. Closing it closes the underlying body.
+type limitedReadCloser struct {
+	io.Reader
+	body  io.ReadCloser
+	limit int64
+	read  int64
+}
+
+// Read implements the io.Reader interface.
+func (r *limitedReadCloser) Read(p []byte) (int, error) {
+	n, err := r.Reader.Read(p)
+	r.read += int64(n)
+	if r.read > r.limit {
+		return n - int(r.read-r.limit), fmt.Errorf("%w: limit is %d bytes", ErrResponseTooLarge, r.limit)
+	}
+	return n, err
+}
+
+// Close implements the io.Closer interface.
+func (r *limitedReadCloser) Close() error {
+	return r.body.Close()
+}
+
+// decodeHTTPResponse decodes a JSON-RPC response read from r. The result is
+// decoded directly into the given value, without buffering the entire
+// response body in memory. If the result is nil, it is discarded.
+//
+// It returns the RPC error, if the response contains one, and the error that
+// occurred while decoding the result. The last returned error is not nil if
+// the response is not a valid JSON-RPC response.
+func decodeHTTPResponse(r io.Reader, result any) (rpcErr *rpcError, resultErr error, err error) {
+	dec := json.NewDecoder(r)
+	if err := expectDelim(dec, '{'); err != nil {
+		return nil, nil, err
+	}
+	hasResult := false
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, nil, err
+		}
+		switch tok {
+		case "result":
+			hasResult = true
+			if result == nil {
+				err = dec.Decode(new(json.RawMessage))
+			} else {
+				resultErr, err = decodeStream(dec, result)
+			}
+		case "error":
+			err = dec.Decode(&rpcErr)
+		default:
+			err = dec.Decode(new(json.RawMessage))
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	if err := expectDelim(dec, '}'); err != nil {
+		return nil, nil, err
+	}
+	if !hasResult && rpcErr == nil && result != nil {
+		resultErr = errors.New("missing result")
+	}
+	return rpcErr, resultErr, nil
+}
+
+// decodeStream decodes the next JSON value into the given value.
+//
+// If the value is a pointer to a slice and the JSON value is an array, the
+// elements are decoded one by one. This way, only a single element has to be
+// buffered at a time, which keeps the memory usage bounded for large results,
+// such as eth_getLogs responses.
+//
+// The first returned error is the error that occurred while mapping the JSON
+// value to the given value. The second one is not nil if the JSON value could
+// not be read.
+func decodeStream(dec *json.Decoder, v any) (error, error) {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr ||
+		rv.IsNil() ||
+		rv.Elem().Kind() != reflect.Slice ||
+		rv.Elem().Type().Elem().Kind() == reflect.Uint8 ||
+		rv.Type().Implements(jsonUnmarshalerType) {
+		return splitDecodeError(dec.Decode(v))
+	}
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+	slice := rv.Elem()
+	switch tok {
+	case nil:
+		slice.Set(reflect.Zero(slice.Type()))
+		return nil, nil
+	case json.Delim('['):
+	case json.Delim('{'):
+		if err := skipRest(dec); err != nil {
+			return nil, err
+		}
+		return fmt.Errorf("cannot unmarshal object into %s", slice.Type()), nil
+	default:
+		return fmt.Errorf("cannot unmarshal %v into %s", tok, slice.Type()), nil
+	}
+	var resultErr error
+	elems := reflect.MakeSlice(slice.Type(), 0, 0)
+	for dec.More() {
+		elem := reflect.New(slice.Type().Elem())
+		decErr, err := splitDecodeError(dec.Decode(elem.Interface()))
+		if err != nil {
+			return nil, err
+		}
+		if decErr != nil && resultErr == nil {
+			resultErr = decErr
+		}
+		elems = reflect.Append(elems, elem.Elem())
+	}
+	if err := expectDelim(dec, ']'); err != nil {
+		return nil, err
+	}
+	if resultErr != nil {
+		return resultErr, nil
+	}
+	slice.Set(elems)
+	return nil, nil
+}
+
+// splitDecodeError splits the error returned by json.Decoder.Decode into an
+// error that occurred while mapping the value and an error that occurred
+// while reading the JSON value.
+func splitDecodeError(err error) (error, error) {
+	var syntaxErr *json.SyntaxError
+	switch {
+	case err == nil:
+		return nil, nil
+	case errors.As(err, &syntaxErr),
+		errors.Is(err, io.EOF),
+		errors.Is(err, io.ErrUnexpectedEOF),
+		errors.Is(err, ErrResponseTooLarge):
+		return nil, err
+	default:
+		return err, nil
+	}
+}
+
+// expectDelim reads the next token and returns an error if it is not the
+// given delimiter.
+func expectDelim(dec *json.Decoder, delim json.Delim) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	if tok != delim {
+		return fmt.Errorf("unexpected token %v, expected %v", tok, delim)
+	}
+	return nil
+}
+
+// skipRest skips the remaining tokens of an object or array whose opening
+// delimiter was already read.
+func skipRest(dec *json.Decoder) error {
+	for depth := 1; depth > 0; {
+		tok, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		switch tok {
+		case json.Delim('{'), json.Delim('['):
+			depth++
+		case json.Delim('}'), json.Delim(']'):
+			depth--
+		}
+	}
+	return nil
+}
+
+var jsonUnmarshalerType = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+
+// newHTTPTransport creates a http.Transport based on http.DefaultTransport
+// with the connection tuning options applied.
+func newHTTPTransport(opts HTTPOptions) *http.Transport {
+	t := http.DefaultTransport.(*http.Transport).Clone()
+	if opts.MaxIdleConnsPerHost > 0 {
+		t.MaxIdleConnsPerHost = opts.MaxIdleConnsPerHost
+		if t.MaxIdleConns != 0 && t.MaxIdleConns < opts.MaxIdleConnsPerHost {
+			t.MaxIdleConns = opts.MaxIdleConnsPerHost
+		}
+	}
+	if opts.IdleConnTimeout > 0 {
+		t.IdleConnTimeout = opts.IdleConnTimeout
+	}
+	if opts.ForceHTTP2 {
+		// Connections through a proxy are established by the http.Transport
+		// itself, so they would not be restricted to HTTP/2.
+		t.Proxy = nil
+		t.DialTLSContext = dialHTTP2(t)
+	}
+	return t
+}
+
+// dialHTTP2 returns a function that dials TLS connections for the given
+// transport, offering only the HTTP/2 protocol during the handshake. It
+// fails if the server does not agree to use HTTP/2.
+func dialHTTP2(t *http.Transport) func(ctx context.Context, network, addr string) (net.Conn, error) {
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		conn, err := t.DialContext(ctx, network, addr)
+		if err != nil {
+			return nil, err
+		}
+		cfg := &tls.Config{}
+		if t.TLSClientConfig != nil {
+			cfg = t.TLSClientConfig.Clone()
+		}
+		cfg.NextProtos = []string{"h2"}
+		if cfg.ServerName == "" {
+			host, _, err := net.SplitHostPort(addr)
+			if err != nil {
+				host = addr
+			}
+			cfg.ServerName = host
+		}
+		tlsConn := tls.Client(conn, cfg)
+		if err := tlsConn.HandshakeContext(ctx); err != nil {
+			conn.Close()
+			return nil, err
+		}
+		if tlsConn.ConnectionState().NegotiatedProtocol != "h2" {
+			tlsConn.Close()
+			return nil, errors.New("server does not support HTTP/2")
+		}
+		return tlsConn, nil
+	}
+}
@@ -2,11 +2,15 @@ package transport
 
 import (
 	"bytes"
+	"compress/gzip"
 	"context"
 	"fmt"
 	"io"
 	"net/http"
+	"net/http/httptest"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -125,6 +129,60 @@ func TestHTTP(t *testing.T) {
 				assert.Error(t, err)
 			},
 		},
+		// Array result is decoded element by element:
+		{
+			asserts: func(t *testing.T, h *httpMock) {
+				h.Response = &http.Response{
+					StatusCode: http.StatusOK,
+					Body:       io.NopCloser(bytes.NewReader([]byte(`{"jsonrpc":"2.0", "result":["0x1", "0x2", "0x3"], "id":1}`))),
+				}
+				var result []types.Number
+				require.NoError(t, h.Call(context.Background(), &result, "eth_getLogs"))
+				require.Len(t, result, 3)
+				assert.Equal(t, "1", result[0].Big().String())
+				assert.Equal(t, "2", result[1].Big().String())
+				assert.Equal(t, "3", result[2].Big().String())
+			},
+		},
+		// Null array result:
+		{
+			asserts: func(t *testing.T, h *httpMock) {
+				h.Response = &http.Response{
+					StatusCode: http.StatusOK,
+					Body:       io.NopCloser(bytes.NewReader([]byte(`{"id":1, "jsonrpc":"2.0", "result":null}`))),
+				}
+				result := []types.Number{{}}
+				require.NoError(t, h.Call(context.Background(), &result, "eth_getLogs"))
+				assert.Nil(t, result)
+			},
+		},
+		// Invalid array element:
+		{
+			asserts: func(t *testing.T, h *httpMock) {
+				h.Response = &http.Response{
+					StatusCode: http.StatusOK,
+					Body:       io.NopCloser(bytes.NewReader([]byte(`{"id":1, "jsonrpc":"2.0", "result":["0x1", {}]}`))),
+				}
+				var result []types.Number
+				err := h.Call(context.Background(), &result, "eth_getLogs")
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), "failed to unmarshal RPC result")
+				assert.Nil(t, result)
+			},
+		},
+		// Error after the result field:
+		{
+			asserts: func(t *testing.T, h *httpMock) {
+				h.Response = &http.Response{
+					StatusCode: http.StatusOK,
+					Body:       io.NopCloser(bytes.NewReader([]byte(`{"id":1, "jsonrpc":"2.0", "result":null, "error":{"code":-32005, "message":"Limit exceeded"}}`))),
+				}
+				var result []types.Number
+				err := h.Call(context.Background(), &result, "eth_getLogs")
+				assert.Error(t, err)
+				assert.Equal(t, "RPC error: -32005 Limit exceeded", err.Error())
+			},
+		},
 	}
 	for n, tt := range tests {
 		t.Run(fmt.Sprintf("case-%d", n+1), func(t *testing.T) {
@@ -145,3 +203,307 @@ func TestHTTP(t *testing.T) {
 		})
 	}
 }
+
+func TestHTTPBatch(t *testing.T) {
+	var request *http.Request
+	var response string
+	h, err := NewHTTP(HTTPOptions{
+		URL: "http://localhost",
+		HTTPClient: &http.Client{
+			Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+				request = req
+				return &http.Response{
+					StatusCode: http.StatusOK,
+					Body:       io.NopCloser(bytes.NewReader([]byte(response))),
+				}, nil
+			}),
+		},
+	})
+	require.NoError(t, err)
+
+	t.Run("success", func(t *testing.T) {
+		response = `[
+			{"id":3, "jsonrpc":"2.0", "error":{"code":-32601, "message":"Method not found"}},
+			{"id":1, "jsonrpc":"2.0", "result":"0x1"},
+			{"id":2, "jsonrpc":"2.0", "result":"0x2"}
+		]`
+		var r1, r2, r3 types.Number
+		calls := []BatchCall{
+			{Result: &r1, Method: "eth_a", Args: []any{"0x1"}},
+			{Result: &r2, Method: "eth_b"},
+			{Result: &r3, Method: "eth_c"},
+		}
+		require.NoError(t, h.CallBatch(context.Background(), calls))
+		requestBody, err := io.ReadAll(request.Body)
+		require.NoError(t, err)
+		assert.JSONEq(t, `[
+			{"id":1, "jsonrpc":"2.0", "method":"eth_a", "params":["0x1"]},
+			{"id":2, "jsonrpc":"2.0", "method":"eth_b", "params":[]},
+			{"id":3, "jsonrpc":"2.0", "method":"eth_c", "params":[]}
+		]`, string(requestBody))
+		assert.NoError(t, calls[0].Error)
+		assert.NoError(t, calls[1].Error)
+		assert.Equal(t, "RPC error: -32601 Method not found", calls[2].Error.Error())
+		assert.Equal(t, "1", r1.Big().String())
+		assert.Equal(t, "2", r2.Big().String())
+	})
+	t.Run("missing-response", func(t *testing.T) {
+		response = `[{"id":4, "jsonrpc":"2.0", "result":"0x1"}]`
+		calls := []BatchCall{{Method: "eth_a"}, {Method: "eth_b"}}
+		require.NoError(t, h.CallBatch(context.Background(), calls))
+		assert.NoError(t, calls[0].Error)
+		assert.Error(t, calls[1].Error)
+	})
+	t.Run("batch-error", func(t *testing.T) {
+		response = `{"id":null, "jsonrpc":"2.0", "error":{"code":-32600, "message":"Invalid request"}}`
+		calls := []BatchCall{{Method: "eth_a"}}
+		err := h.CallBatch(context.Background(), calls)
+		require.Error(t, err)
+		assert.Equal(t, "RPC error: -32600 Invalid request", err.Error())
+	})
+}
+
+func TestHTTPCompression(t *testing.T) {
+	gzipped := func(s string) []byte {
+		var buf bytes.Buffer
+		w := gzip.NewWriter(&buf)
+		_, _ = w.Write([]byte(s))
+		_ = w.Close()
+		return buf.Bytes()
+	}
+	tests := []struct {
+		opts           HTTPOptions
+		encoding       string
+		body           []byte
+		acceptEncoding string
+		wantErr        bool
+	}{
+		// Compressed response:
+		{
+			encoding:       "gzip",
+			body:           gzipped(`{"id":1, "jsonrpc":"2.0", "result":"0x1"}`),
+			acceptEncoding: "gzip",
+		},
+		// Uncompressed response:
+		{
+			body:           []byte(`{"id":1, "jsonrpc":"2.0", "result":"0x1"}`),
+			acceptEncoding: "gzip",
+		},
+		// Compression disabled:
+		{
+			opts:           HTTPOptions{DisableCompression: true},
+			body:           []byte(`{"id":1, "jsonrpc":"2.0", "result":"0x1"}`),
+			acceptEncoding: "identity",
+		},
+		// Invalid compressed response:
+		{
+			encoding:       "gzip",
+			body:           []byte(`{"id":1, "jsonrpc":"2.0", "result":"0x1"}`),
+			acceptEncoding: "gzip",
+			wantErr:        true,
+		},
+	}
+	for n, tt := range tests {
+		t.Run(fmt.Sprintf("case-%d", n+1), func(t *testing.T) {
+			var request *http.Request
+			opts := tt.opts
+			opts.URL = "http://localhost"
+			opts.HTTPClient = &http.Client{
+				Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+					request = req
+					res := &http.Response{
+						StatusCode: http.StatusOK,
+						Header:     http.Header{},
+						Body:       io.NopCloser(bytes.NewReader(tt.body)),
+					}
+					if tt.encoding != "" {
+						res.Header.Set("Content-Encoding", tt.encoding)
+					}
+					return res, nil
+				}),
+			}
+			h, err := NewHTTP(opts)
+			require.NoError(t, err)
+			result := types.Number{}
+			err = h.Call(context.Background(), &result, "eth_a")
+			assert.Equal(t, tt.acceptEncoding, request.Header.Get("Accept-Encoding"))
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, "1", result.Big().String())
+		})
+	}
+}
+
+func TestHTTPMaxResponseBytes(t *testing.T) {
+	const (
+		small = `{"id":1, "jsonrpc":"2.0", "result":"0x1"}`
+		large = `{"id":1, "jsonrpc":"2.0", "result":["0x1","0x2","0x3","0x4","0x5","0x6","0x7","0x8"]}`
+	)
+	tests := []struct {
+		name    string
+		limit   int64
+		body    string
+		batch   bool
+		wantErr bool
+	}{
+		{name: "no limit", body: large},
+		{name: "within limit", limit: int64(len(small)), body: small},
+		{name: "exceeded", limit: 64, body: large, wantErr: true},
+		{name: "exceeded in batch", limit: 16, body: "[" + small + "]", batch: true, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, err := NewHTTP(HTTPOptions{
+				URL:              "http://localhost",
+				MaxResponseBytes: tt.limit,
+				HTTPClient: &http.Client{
+					Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+						return &http.Response{
+							StatusCode: http.StatusOK,
+							Body:       io.NopCloser(bytes.NewBufferString(tt.body)),
+						}, nil
+					}),
+				},
+			})
+			require.NoError(t, err)
+			if tt.batch {
+				err = h.CallBatch(context.Background(), []BatchCall{{Method: "eth_a"}})
+			} else {
+				err = h.Call(context.Background(), nil, "eth_a")
+			}
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrResponseTooLarge)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+
+	_, err := NewHTTP(HTTPOptions{URL: "http://localhost", MaxResponseBytes: -1})
+	assert.Error(t, err)
+}
+
+type hookCall struct {
+	method string
+	err    error
+}
+
+type hookMock struct {
+	mu        sync.Mutex
+	requests  []string
+	responses []hookCall
+}
+
+func (h *hookMock) OnRequest(method string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.requests = append(h.requests, method)
+}
+
+func (h *hookMock) OnResponse(method string, _ time.Duration, err error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.responses = append(h.responses, hookCall{method: method, err: err})
+}
+
+func TestHTTPHook(t *testing.T) {
+	hook := &hookMock{}
+	responses := []string{
+		`{"id":1, "jsonrpc":"2.0", "result":"0x1"}`,
+		`{"id":2, "jsonrpc":"2.0", "error":{"code":1, "message":"error"}}`,
+	}
+	h, err := NewHTTP(HTTPOptions{
+		URL:  "http://localhost",
+		Hook: hook,
+		HTTPClient: &http.Client{
+			Transport: roundTripFunc(func(req *http.Request) (*http.Response, error) {
+				res := responses[0]
+				responses = responses[1:]
+				return &http.Response{
+					StatusCode: http.StatusOK,
+					Body:       io.NopCloser(bytes.NewReader([]byte(res))),
+				}, nil
+			}),
+		},
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, h.Call(context.Background(), nil, "eth_a"))
+	require.Error(t, h.Call(context.Background(), nil, "eth_b"))
+
+	assert.Equal(t, []string{"eth_a", "eth_b"}, hook.requests)
+	require.Len(t, hook.responses, 2)
+	assert.Equal(t, "eth_a", hook.responses[0].method)
+	assert.NoError(t, hook.responses[0].err)
+	assert.Equal(t, "eth_b", hook.responses[1].method)
+	assert.Error(t, hook.responses[1].err)
+}
+
+func TestHTTPConnectionTuning(t *testing.T) {
+	t.Run("transport", func(t *testing.T) {
+		h, err := NewHTTP(HTTPOptions{
+			URL:                 "http://localhost",
+			MaxIdleConnsPerHost: 200,
+			IdleConnTimeout:     time.Minute,
+		})
+		require.NoError(t, err)
+		tr, ok := h.opts.HTTPClient.Transport.(*http.Transport)
+		require.True(t, ok)
+		assert.Equal(t, 200, tr.MaxIdleConnsPerHost)
+		assert.Equal(t, 200, tr.MaxIdleConns)
+		assert.Equal(t, time.Minute, tr.IdleConnTimeout)
+	})
+	t.Run("http-client-conflict", func(t *testing.T) {
+		_, err := NewHTTP(HTTPOptions{
+			URL:                 "http://localhost",
+			HTTPClient:          &http.Client{},
+			MaxIdleConnsPerHost: 10,
+		})
+		require.Error(t, err)
+	})
+	t.Run("http2-requires-https", func(t *testing.T) {
+		_, err := NewHTTP(HTTPOptions{
+			URL:        "http://localhost",
+			ForceHTTP2: true,
+		})
+		require.Error(t, err)
+	})
+	t.Run("http2", func(t *testing.T) {
+		srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, 2, r.ProtoMajor)
+			_, _ = w.Write([]byte(`{"id":1, "jsonrpc":"2.0", "result":"0x1"}`))
+		}))
+		srv.EnableHTTP2 = true
+		srv.StartTLS()
+		defer srv.Close()
+
+		h, err := NewHTTP(HTTPOptions{URL: srv.URL, ForceHTTP2: true})
+		require.NoError(t, err)
+		tr := h.opts.HTTPClient.Transport.(*http.Transport)
+		tr.TLSClientConfig = srv.Client().Transport.(*http.Transport).TLSClientConfig.Clone()
+
+		result := types.Number{}
+		require.NoError(t, h.Call(context.Background(), &result, "eth_blockNumber"))
+		assert.Equal(t, "1", result.Big().String())
+	})
+	t.Run("http2-unsupported", func(t *testing.T) {
+		requests := 0
+		srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests++
+			_, _ = w.Write([]byte(`{"id":1, "jsonrpc":"2.0", "result":"0x1"}`))
+		}))
+		defer srv.Close()
+
+		h, err := NewHTTP(HTTPOptions{URL: srv.URL, ForceHTTP2: true})
+		require.NoError(t, err)
+		tr := h.opts.HTTPClient.Transport.(*http.Transport)
+		tr.TLSClientConfig = srv.Client().Transport.(*http.Transport).TLSClientConfig.Clone()
+
+		// The request must not reach the server.
+		require.Error(t, h.Call(context.Background(), nil, "eth_sendRawTransaction", "0x00"))
+		assert.Equal(t, 0, requests)
+	})
+}
@@ -29,6 +29,9 @@ type IPCOptions struct {
 
 	// ErrorCh is an optional channel used to report errors.
 	ErrorCh chan error
+
+	// Hook is an optional hook that is called for each request.
+	Hook TransportHook
 }
 
 // NewIPC creates a new IPC instance.
@@ -49,6 +52,7 @@ func NewIPC(opts IPCOptions) (*IPC, error) {
 			ctx:     opts.Context,
 			errCh:   opts.ErrorCh,
 			timeout: opts.Timout,
+			hook:    opts.Hook,
 		},
 		conn: conn,
 	}
@@ -157,6 +157,39 @@ func (c *Retry) Call(ctx context.Context, result any, method string, args ...any
 	return err
 }
 
+// CallBatch implements the BatchTransport interface. If the underlying
+// transport does not support batch requests, the calls are performed one by
+// one, each with its own retries.
+//
+// Only failures of the whole batch request are retried. Errors of individual
+// calls are not retried.
+func (c *Retry) CallBatch(ctx context.Context, calls []BatchCall) (err error) {
+	bt, ok := c.opts.Transport.(BatchTransport)
+	if !ok {
+		for i := range calls {
+			calls[i].Error = c.Call(ctx, calls[i].Result, calls[i].Method, calls[i].Args...)
+		}
+		return nil
+	}
+	var i int
+	for {
+		err = bt.CallBatch(ctx, calls)
+		if !c.opts.RetryFunc(err) {
+			return err
+		}
+		if c.opts.MaxRetries >= 0 && i >= c.opts.MaxRetries {
+			break
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(c.opts.BackoffFunc(i)):
+		}
+		i++
+	}
+	return err
+}
+
 // Subscribe implements the SubscriptionTransport interface.
 func (c *Retry) Subscribe(ctx context.Context, method string, args ...any) (ch chan json.RawMessage, id string, err error) {
 	if s, ok := c.opts.Transport.(SubscriptionTransport); ok {
@@ -22,6 +22,7 @@ type stream struct {
 	errCh    chan error       // Channel to which errors are sent.
 	timeout  time.Duration    // Timeout for requests.
 	onClose  func()           // Callback that is called when the stream is closed.
+	hook     TransportHook    // Optional hook that is called for each request.
 
 	// State fields. Should not be accessed by structs that embed stream.
 	id    uint64                          // Request ID counter.
@@ -43,6 +44,12 @@ func (s *stream) initStream() *stream {
 
 // Call implements the Transport interface.
 func (s *stream) Call(ctx context.Context, result any, method string, args ...any) error {
+	return callWithHook(s.hook, method, func() error {
+		return s.call(ctx, result, method, args...)
+	})
+}
+
+func (s *stream) call(ctx context.Context, result any, method string, args ...any) error {
 	ctx, ctxCancel := context.WithTimeout(ctx, s.timeout)
 	defer ctxCancel()
 
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	netURL "net/url"
+	"time"
 )
 
 // Transport handles the transport layer of the JSON-RPC protocol.
@@ -26,6 +27,65 @@ type SubscriptionTransport interface {
 	Unsubscribe(ctx context.Context, id string) error
 }
 
+// BatchCall is a single call performed as a part of a batch request.
+type BatchCall struct {
+	Result any    // Result is the value where the result will be decoded, may be nil.
+	Method string // Method is the name of the RPC method.
+	Args   []any  // Args are the arguments of the RPC method.
+	Error  error  // Error is set after the call if the call failed.
+}
+
+// BatchTransport is transport that supports JSON-RPC batch requests.
+type BatchTransport interface {
+	Transport
+
+	// CallBatch performs multiple JSON-RPC calls using a single batch
+	// request. Errors of individual calls are stored in the Error field of
+	// each call. The returned error is not nil only if the whole batch
+	// request failed.
+	CallBatch(ctx context.Context, calls []BatchCall) error
+}
+
+// CallBatch performs the given calls using a batch request if the transport
+// implements the BatchTransport interface. Otherwise, the calls are performed
+// one by one and the returned error is always nil.
+func CallBatch(ctx context.Context, t Transport, calls []BatchCall) error {
+	if bt, ok := t.(BatchTransport); ok {
+		return bt.CallBatch(ctx, calls)
+	}
+	for i := range calls {
+		calls[i].Error = t.Call(ctx, calls[i].Result, calls[i].Method, calls[i].Args...)
+	}
+	return nil
+}
+
+// TransportHook allows to observe JSON-RPC calls performed by a transport.
+//
+// It may be used to collect metrics, such as the number of requests, latency
+// and errors per method. Hook methods are called synchronously, so they
+// should not block.
+type TransportHook interface {
+	// OnRequest is called before a request is sent.
+	OnRequest(method string)
+
+	// OnResponse is called after a response is received or the call fails.
+	// The err is nil if the call was successful.
+	OnResponse(method string, dur time.Duration, err error)
+}
+
+// callWithHook invokes the given call function and reports it to the hook,
+// if the hook is not nil.
+func callWithHook(hook TransportHook, method string, call func() error) error {
+	if hook == nil {
+		return call()
+	}
+	hook.OnRequest(method)
+	start := time.Now()
+	err := call()
+	hook.OnResponse(method, time.Since(start), err)
+	return err
+}
+
 // New returns a new Transport instance based on the URL scheme.
 // Supported schemes are: http, https, ws, wss.
 // If scheme is empty, it will use IPC.
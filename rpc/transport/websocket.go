@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"net"
 	"net/http"
 	"time"
 
@@ -15,7 +16,10 @@ import (
 // protocol.
 type Websocket struct {
 	*stream
-	conn *websocket.Conn
+	conn         *websocket.Conn
+	writeTimeout time.Duration
+	pingPeriod   time.Duration
+	pongWait     time.Duration
 }
 
 // WebsocketOptions contains options for the websocket transport.
@@ -37,8 +41,36 @@ type WebsocketOptions struct {
 	// Timeout is the timeout for the websocket requests. Default is 60s.
 	Timout time.Duration
 
+	// WriteTimeout is the maximum time allowed to write a single message
+	// to the connection. If the timeout is exceeded, the connection is
+	// closed. Default is 10s.
+	WriteTimeout time.Duration
+
+	// ReadLimit is the maximum size of a single message in bytes that can
+	// be read from the connection. If a larger message is received, the
+	// connection is closed. Default is 32MiB. Set to -1 to disable the
+	// limit.
+	ReadLimit int64
+
+	// PingPeriod is the interval at which ping messages are sent to keep
+	// the connection alive. Default is 30s. Set to -1 to disable pings.
+	PingPeriod time.Duration
+
+	// PongWait is the maximum time to wait for a pong message after a ping
+	// is sent. If the pong is not received in time, the connection is
+	// closed. Default is 10s.
+	PongWait time.Duration
+
+	// DisableCompression disables the permessage-deflate extension. By
+	// default, compression is negotiated during the handshake and used only
+	// if the server supports it.
+	DisableCompression bool
+
 	// ErrorCh is an optional channel used to report errors.
 	ErrorCh chan error
+
+	// Hook is an optional hook that is called for each request.
+	Hook TransportHook
 }
 
 // NewWebsocket creates a new Websocket instance.
@@ -52,25 +84,50 @@ func NewWebsocket(opts WebsocketOptions) (*Websocket, error) {
 	if opts.Timout == 0 {
 		opts.Timout = 60 * time.Second
 	}
+	if opts.WriteTimeout == 0 {
+		opts.WriteTimeout = 10 * time.Second
+	}
+	if opts.ReadLimit == 0 {
+		opts.ReadLimit = 32 << 20
+	}
+	if opts.PingPeriod == 0 {
+		opts.PingPeriod = 30 * time.Second
+	}
+	if opts.PongWait == 0 {
+		opts.PongWait = 10 * time.Second
+	}
+	compression := websocket.CompressionNoContextTakeover
+	if opts.DisableCompression {
+		compression = websocket.CompressionDisabled
+	}
 	conn, _, err := websocket.Dial(opts.Context, opts.URL, &websocket.DialOptions{ //nolint:bodyclose
-		HTTPClient: opts.HTTPClient,
-		HTTPHeader: opts.HTTPHeader,
+		HTTPClient:      opts.HTTPClient,
+		HTTPHeader:      opts.HTTPHeader,
+		CompressionMode: compression,
 	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to dial websocket: %w", err)
 	}
+	conn.SetReadLimit(opts.ReadLimit)
 	i := &Websocket{
 		stream: &stream{
 			ctx:     opts.Context,
 			errCh:   opts.ErrorCh,
 			timeout: opts.Timout,
+			hook:    opts.Hook,
 		},
-		conn: conn,
+		conn:         conn,
+		writeTimeout: opts.WriteTimeout,
+		pingPeriod:   opts.PingPeriod,
+		pongWait:     opts.PongWait,
 	}
 	i.onClose = i.close
 	i.stream.initStream()
 	go i.readerRoutine()
 	go i.writerRoutine()
+	if i.pingPeriod > 0 {
+		go i.pingRoutine()
+	}
 	return i, nil
 }
 
@@ -85,6 +142,11 @@ func (ws *Websocket) readerRoutine() {
 			if ws.ctx.Err() != nil || errors.As(err, &websocket.CloseError{}) {
 				return
 			}
+			if errors.Is(err, net.ErrClosed) {
+				// The connection was closed locally, most likely because
+				// the read limit, write timeout or pong wait was exceeded.
+				return
+			}
 			if ws.errCh != nil {
 				ws.errCh <- fmt.Errorf("websocket reading error: %w", err)
 			}
@@ -100,7 +162,10 @@ func (ws *Websocket) writerRoutine() {
 		case <-ws.ctx.Done():
 			return
 		case req := <-ws.writerCh:
-			if err := wsjson.Write(ws.ctx, ws.conn, req); err != nil {
+			ctx, cancel := context.WithTimeout(ws.ctx, ws.writeTimeout)
+			err := wsjson.Write(ctx, ws.conn, req)
+			cancel()
+			if err != nil {
 				if ws.errCh != nil {
 					ws.errCh <- fmt.Errorf("websocket writing error: %w", err)
 				}
@@ -110,6 +175,31 @@ func (ws *Websocket) writerRoutine() {
 	}
 }
 
+func (ws *Websocket) pingRoutine() {
+	t := time.NewTicker(ws.pingPeriod)
+	defer t.Stop()
+	for {
+		select {
+		case <-ws.ctx.Done():
+			return
+		case <-t.C:
+			ctx, cancel := context.WithTimeout(ws.ctx, ws.pongWait)
+			err := ws.conn.Ping(ctx)
+			cancel()
+			if err != nil {
+				if ws.ctx.Err() != nil {
+					return
+				}
+				if ws.errCh != nil {
+					ws.errCh <- fmt.Errorf("websocket ping error: %w", err)
+				}
+				_ = ws.conn.CloseNow()
+				return
+			}
+		}
+	}
+}
+
 func (ws *Websocket) close() {
 	err := ws.conn.Close(websocket.StatusNormalClosure, "")
 	if err != nil && ws.errCh != nil {
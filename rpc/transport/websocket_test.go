@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"net"
 	"net/http"
+	"strings"
 	"sync"
 	"testing"
 	"time"
@@ -212,3 +213,180 @@ func TestWebsocket(t *testing.T) {
 		})
 	}
 }
+
+func TestWebsocketKeepaliveAndLimits(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    WebsocketOptions
+		handler func(ctx context.Context, conn *websocket.Conn)
+		call    bool
+		wantErr string
+	}{
+		{
+			name: "read limit",
+			opts: WebsocketOptions{ReadLimit: 64, PingPeriod: -1},
+			handler: func(ctx context.Context, conn *websocket.Conn) {
+				var req json.RawMessage
+				_ = wsjson.Read(ctx, conn, &req)
+				_ = wsjson.Write(ctx, conn, json.RawMessage(`{"id":1, "result":"`+strings.Repeat("a", 128)+`"}`))
+			},
+			call:    true,
+			wantErr: "websocket reading error",
+		},
+		{
+			name: "pong wait",
+			opts: WebsocketOptions{PingPeriod: 50 * time.Millisecond, PongWait: 50 * time.Millisecond},
+			handler: func(ctx context.Context, conn *websocket.Conn) {
+				// Do not read from the connection, so pings are never
+				// answered.
+				<-ctx.Done()
+			},
+			wantErr: "websocket ping error",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second*10)
+			defer cancel()
+
+			// Websocket server.
+			server := &http.Server{Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				conn, err := websocket.Accept(w, r, nil)
+				if err != nil {
+					return
+				}
+				defer conn.CloseNow()
+				tt.handler(ctx, conn)
+				<-ctx.Done()
+			})}
+			ln, err := net.Listen("tcp", "127.0.0.1:0")
+			require.NoError(t, err)
+			go func() { _ = server.Serve(ln) }()
+			defer server.Close()
+
+			// Create a websocket client.
+			errCh := make(chan error, 10)
+			opts := tt.opts
+			opts.Context = ctx
+			opts.URL = "ws://" + ln.Addr().String()
+			opts.Timout = 500 * time.Millisecond
+			opts.ErrorCh = errCh
+			ws, err := NewWebsocket(opts)
+			require.NoError(t, err)
+
+			if tt.call {
+				require.Error(t, ws.Call(ctx, nil, "eth_call"))
+			}
+
+			select {
+			case err := <-errCh:
+				assert.Contains(t, err.Error(), tt.wantErr)
+			case <-ctx.Done():
+				t.Fatal("timeout waiting for error")
+			}
+			cancel()
+		})
+	}
+}
+
+func TestWebsocketCompression(t *testing.T) {
+	tests := []struct {
+		name               string
+		disableCompression bool
+		serverCompression  websocket.CompressionMode
+		wantExtension      bool
+	}{
+		{name: "enabled", serverCompression: websocket.CompressionNoContextTakeover, wantExtension: true},
+		{name: "server without compression", serverCompression: websocket.CompressionDisabled, wantExtension: true},
+		{name: "disabled", disableCompression: true, serverCompression: websocket.CompressionNoContextTakeover},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second*10)
+			defer cancel()
+
+			// Websocket server that responds with a large, compressible
+			// message.
+			extCh := make(chan string, 1)
+			server := &http.Server{Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				extCh <- r.Header.Get("Sec-WebSocket-Extensions")
+				conn, err := websocket.Accept(w, r, &websocket.AcceptOptions{CompressionMode: tt.serverCompression})
+				if err != nil {
+					return
+				}
+				defer conn.CloseNow()
+				var req json.RawMessage
+				_ = wsjson.Read(ctx, conn, &req)
+				_ = wsjson.Write(ctx, conn, json.RawMessage(`{"id":1, "result":"0x`+strings.Repeat("00", 4096)+`"}`))
+				<-ctx.Done()
+			})}
+			ln, err := net.Listen("tcp", "127.0.0.1:0")
+			require.NoError(t, err)
+			go func() { _ = server.Serve(ln) }()
+			defer server.Close()
+
+			ws, err := NewWebsocket(WebsocketOptions{
+				Context:            ctx,
+				URL:                "ws://" + ln.Addr().String(),
+				DisableCompression: tt.disableCompression,
+			})
+			require.NoError(t, err)
+
+			ext := <-extCh
+			if tt.wantExtension {
+				assert.Contains(t, ext, "permessage-deflate")
+			} else {
+				assert.NotContains(t, ext, "permessage-deflate")
+			}
+
+			res := &types.Bytes{}
+			require.NoError(t, ws.Call(ctx, res, "eth_getCode"))
+			assert.Len(t, res.Bytes(), 4096)
+			cancel()
+		})
+	}
+}
+
+func TestWebsocketHook(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*10)
+	defer cancel()
+
+	// Websocket server that responds to every request with its ID.
+	server := &http.Server{Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := websocket.Accept(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.CloseNow()
+		for {
+			var req struct {
+				ID json.RawMessage `json:"id"`
+			}
+			if err := wsjson.Read(ctx, conn, &req); err != nil {
+				return
+			}
+			_ = wsjson.Write(ctx, conn, json.RawMessage(`{"id":`+string(req.ID)+`, "result":"0x1"}`))
+		}
+	})}
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	go func() { _ = server.Serve(ln) }()
+	defer server.Close()
+
+	hook := &hookMock{}
+	ws, err := NewWebsocket(WebsocketOptions{
+		Context:    ctx,
+		URL:        "ws://" + ln.Addr().String(),
+		PingPeriod: -1,
+		Hook:       hook,
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, ws.Call(ctx, nil, "eth_a"))
+	require.NoError(t, ws.Call(ctx, nil, "eth_b"))
+
+	hook.mu.Lock()
+	defer hook.mu.Unlock()
+	assert.Equal(t, []string{"eth_a", "eth_b"}, hook.requests)
+	assert.Equal(t, []hookCall{{method: "eth_a"}, {method: "eth_b"}}, hook.responses)
+}
@@ -2,6 +2,7 @@ package rpc
 
 import (
 	"encoding/json"
+	"sort"
 
 	"github.com/defiweb/go-eth/types"
 )
@@ -30,3 +31,57 @@ func (s *signTransactionResult) UnmarshalJSON(input []byte) error {
 	s.Raw = dec.Raw
 	return nil
 }
+
+// sortAndDedupLogs sorts logs by block number and log index, and removes
+// duplicated logs with the same block hash and log index. Pending logs, that
+// have no block number, are placed at the end.
+func sortAndDedupLogs(logs []types.Log) []types.Log {
+	sort.SliceStable(logs, func(i, j int) bool {
+		return compareLogs(&logs[i], &logs[j]) < 0
+	})
+	type logKey struct {
+		blockHash types.Hash
+		logIndex  uint64
+	}
+	seen := make(map[logKey]struct{}, len(logs))
+	res := logs[:0]
+	for _, l := range logs {
+		if l.BlockHash != nil && l.LogIndex != nil {
+			k := logKey{blockHash: *l.BlockHash, logIndex: *l.LogIndex}
+			if _, ok := seen[k]; ok {
+				continue
+			}
+			seen[k] = struct{}{}
+		}
+		res = append(res, l)
+	}
+	return res
+}
+
+// compareLogs compares logs by block number and log index.
+func compareLogs(a, b *types.Log) int {
+	switch {
+	case a.BlockNumber == nil && b.BlockNumber == nil:
+	case a.BlockNumber == nil:
+		return 1
+	case b.BlockNumber == nil:
+		return -1
+	default:
+		if c := a.BlockNumber.Cmp(b.BlockNumber); c != 0 {
+			return c
+		}
+	}
+	switch {
+	case a.LogIndex == nil && b.LogIndex == nil:
+		return 0
+	case a.LogIndex == nil:
+		return 1
+	case b.LogIndex == nil:
+		return -1
+	case *a.LogIndex < *b.LogIndex:
+		return -1
+	case *a.LogIndex > *b.LogIndex:
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,53 @@
+package token
+
+import (
+	"github.com/defiweb/go-eth/abi"
+	"github.com/defiweb/go-eth/types"
+)
+
+// Topics of the standard ERC-20 and ERC-721 events.
+//
+// ERC-20 and ERC-721 Transfer and Approval events have the same signatures,
+// so they have the same topics. They differ only in which arguments are
+// indexed.
+var (
+	// ERC20TransferTopic is the topic of Transfer(address,address,uint256).
+	ERC20TransferTopic = types.MustHashFromHex("0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef", types.PadNone)
+
+	// ERC20ApprovalTopic is the topic of Approval(address,address,uint256).
+	ERC20ApprovalTopic = types.MustHashFromHex("0x8c5be1e5ebec7d5bd14f71427d1e84f3dd0314c0f7b2291e5b200ac8c7c3b925", types.PadNone)
+
+	// ERC721TransferTopic is the topic of Transfer(address,address,uint256).
+	ERC721TransferTopic = ERC20TransferTopic
+
+	// ERC721ApprovalTopic is the topic of Approval(address,address,uint256).
+	ERC721ApprovalTopic = ERC20ApprovalTopic
+
+	// ERC721ApprovalForAllTopic is the topic of
+	// ApprovalForAll(address,address,bool).
+	ERC721ApprovalForAllTopic = types.MustHashFromHex("0x17307eab39ab6107e8899845ad3d59bd9653f200f220920489ca2b5937696c31", types.PadNone)
+)
+
+// Selectors of the standard ERC-20 methods.
+var (
+	ERC20TotalSupplySelector  = abi.FourBytes{0x18, 0x16, 0x0d, 0xdd} // totalSupply()
+	ERC20BalanceOfSelector    = abi.FourBytes{0x70, 0xa0, 0x82, 0x31} // balanceOf(address)
+	ERC20TransferSelector     = abi.FourBytes{0xa9, 0x05, 0x9c, 0xbb} // transfer(address,uint256)
+	ERC20TransferFromSelector = abi.FourBytes{0x23, 0xb8, 0x72, 0xdd} // transferFrom(address,address,uint256)
+	ERC20ApproveSelector      = abi.FourBytes{0x09, 0x5e, 0xa7, 0xb3} // approve(address,uint256)
+	ERC20AllowanceSelector    = abi.FourBytes{0xdd, 0x62, 0xed, 0x3e} // allowance(address,address)
+)
+
+// Selectors of the standard ERC-721 methods.
+var (
+	ERC721BalanceOfSelector            = abi.FourBytes{0x70, 0xa0, 0x82, 0x31} // balanceOf(address)
+	ERC721OwnerOfSelector              = abi.FourBytes{0x63, 0x52, 0x21, 0x1e} // ownerOf(uint256)
+	ERC721TransferFromSelector         = abi.FourBytes{0x23, 0xb8, 0x72, 0xdd} // transferFrom(address,address,uint256)
+	ERC721SafeTransferFromSelector     = abi.FourBytes{0x42, 0x84, 0x2e, 0x0e} // safeTransferFrom(address,address,uint256)
+	ERC721SafeTransferFromDataSelector = abi.FourBytes{0xb8, 0x8d, 0x4f, 0xde} // safeTransferFrom(address,address,uint256,bytes)
+	ERC721ApproveSelector              = abi.FourBytes{0x09, 0x5e, 0xa7, 0xb3} // approve(address,uint256)
+	ERC721SetApprovalForAllSelector    = abi.FourBytes{0xa2, 0x2c, 0xb4, 0x65} // setApprovalForAll(address,bool)
+	ERC721GetApprovedSelector          = abi.FourBytes{0x08, 0x18, 0x12, 0xfc} // getApproved(uint256)
+	ERC721IsApprovedForAllSelector     = abi.FourBytes{0xe9, 0x85, 0xe9, 0xc5} // isApprovedForAll(address,address)
+	ERC721TokenURISelector             = abi.FourBytes{0xc8, 0x7b, 0x56, 0xdd} // tokenURI(uint256)
+)
@@ -0,0 +1,57 @@
+package token
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/defiweb/go-eth/abi"
+	"github.com/defiweb/go-eth/types"
+)
+
+func TestTopics(t *testing.T) {
+	tests := []struct {
+		topic types.Hash
+		event string
+	}{
+		{topic: ERC20TransferTopic, event: "Transfer(address indexed from, address indexed to, uint256 value)"},
+		{topic: ERC20ApprovalTopic, event: "Approval(address indexed owner, address indexed spender, uint256 value)"},
+		{topic: ERC721TransferTopic, event: "Transfer(address indexed from, address indexed to, uint256 indexed tokenId)"},
+		{topic: ERC721ApprovalTopic, event: "Approval(address indexed owner, address indexed approved, uint256 indexed tokenId)"},
+		{topic: ERC721ApprovalForAllTopic, event: "ApprovalForAll(address indexed owner, address indexed operator, bool approved)"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.event, func(t *testing.T) {
+			assert.Equal(t, tt.topic, abi.MustParseEvent(tt.event).Topic0())
+		})
+	}
+}
+
+func TestSelectors(t *testing.T) {
+	tests := []struct {
+		selector abi.FourBytes
+		method   string
+	}{
+		{selector: ERC20TotalSupplySelector, method: "totalSupply()"},
+		{selector: ERC20BalanceOfSelector, method: "balanceOf(address)"},
+		{selector: ERC20TransferSelector, method: "transfer(address,uint256)"},
+		{selector: ERC20TransferFromSelector, method: "transferFrom(address,address,uint256)"},
+		{selector: ERC20ApproveSelector, method: "approve(address,uint256)"},
+		{selector: ERC20AllowanceSelector, method: "allowance(address,address)"},
+		{selector: ERC721BalanceOfSelector, method: "balanceOf(address)"},
+		{selector: ERC721OwnerOfSelector, method: "ownerOf(uint256)"},
+		{selector: ERC721TransferFromSelector, method: "transferFrom(address,address,uint256)"},
+		{selector: ERC721SafeTransferFromSelector, method: "safeTransferFrom(address,address,uint256)"},
+		{selector: ERC721SafeTransferFromDataSelector, method: "safeTransferFrom(address,address,uint256,bytes)"},
+		{selector: ERC721ApproveSelector, method: "approve(address,uint256)"},
+		{selector: ERC721SetApprovalForAllSelector, method: "setApprovalForAll(address,bool)"},
+		{selector: ERC721GetApprovedSelector, method: "getApproved(uint256)"},
+		{selector: ERC721IsApprovedForAllSelector, method: "isApprovedForAll(address,address)"},
+		{selector: ERC721TokenURISelector, method: "tokenURI(uint256)"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.method, func(t *testing.T) {
+			assert.Equal(t, tt.selector, abi.MustParseMethod(tt.method).FourBytes())
+		})
+	}
+}
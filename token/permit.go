@@ -0,0 +1,144 @@
+package token
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+
+	"github.com/defiweb/go-eth/abi"
+	"github.com/defiweb/go-eth/crypto"
+	"github.com/defiweb/go-eth/types"
+	"github.com/defiweb/go-eth/wallet"
+)
+
+// permitType is the EIP-712 encoded type of the EIP-2612 Permit struct.
+const permitType = "Permit(address owner,address spender,uint256 value,uint256 nonce,uint256 deadline)"
+
+var (
+	permitTypeHash   = crypto.Keccak256([]byte(permitType))
+	permitStructType = abi.MustParseType("(bytes32,address,address,uint256,uint256,uint256)")
+)
+
+// ERC20Permit is an EIP-2612 permit, which allows the spender to spend the
+// owner's tokens without the owner sending an approve transaction.
+type ERC20Permit struct {
+	// Domain fields:
+	Name    string        // Name is the name of the token, as used in its EIP-712 domain.
+	Version string        // Version is the version of the token's EIP-712 domain, usually "1".
+	ChainID uint64        // ChainID is the chain ID of the network the token is deployed on.
+	Token   types.Address // Token is the address of the token contract.
+
+	// Permit fields:
+	Owner    types.Address // Owner is the address of the token owner that signs the permit.
+	Spender  types.Address // Spender is the address allowed to spend the tokens.
+	Value    *big.Int      // Value is the amount of tokens the spender is allowed to spend.
+	Nonce    *big.Int      // Nonce is the current nonce of the owner, as returned by the nonces method of the token.
+	Deadline *big.Int      // Deadline is the Unix timestamp after which the permit is no longer valid.
+}
+
+// Domain returns the EIP-712 domain of the token.
+func (p *ERC20Permit) Domain() types.TypedDataDomain {
+	token := p.Token
+	return types.TypedDataDomain{
+		Name:              p.Name,
+		Version:           p.Version,
+		ChainID:           new(big.Int).SetUint64(p.ChainID),
+		VerifyingContract: &token,
+	}
+}
+
+// TypedData returns the permit as EIP-712 typed data. It can be used to sign
+// the permit using the eth_signTypedData_v4 method.
+//
+// The EIP712Domain type contains the same fields as the domain used by
+// Digest, so empty Name or Version fields are omitted.
+func (p *ERC20Permit) TypedData() *types.TypedData {
+	domain := p.Domain()
+	return &types.TypedData{
+		Types: map[string][]types.TypedDataField{
+			"EIP712Domain": domain.Fields(),
+			"Permit": {
+				{Name: "owner", Type: "address"},
+				{Name: "spender", Type: "address"},
+				{Name: "value", Type: "uint256"},
+				{Name: "nonce", Type: "uint256"},
+				{Name: "deadline", Type: "uint256"},
+			},
+		},
+		PrimaryType: "Permit",
+		Domain:      domain,
+		Message: map[string]any{
+			"owner":    p.Owner,
+			"spender":  p.Spender,
+			"value":    json.Number(bigOrZero(p.Value).String()),
+			"nonce":    json.Number(bigOrZero(p.Nonce).String()),
+			"deadline": json.Number(bigOrZero(p.Deadline).String()),
+		},
+	}
+}
+
+// Digest returns the EIP-712 digest of the permit, that is the hash that
+// must be signed by the owner.
+func (p *ERC20Permit) Digest() (types.Hash, error) {
+	domain := p.Domain()
+	separator, err := domain.Separator()
+	if err != nil {
+		return types.Hash{}, fmt.Errorf("token: unable to compute permit domain separator: %w", err)
+	}
+	data, err := abi.EncodeValues(
+		permitStructType,
+		permitTypeHash,
+		p.Owner,
+		p.Spender,
+		bigOrZero(p.Value),
+		bigOrZero(p.Nonce),
+		bigOrZero(p.Deadline),
+	)
+	if err != nil {
+		return types.Hash{}, fmt.Errorf("token: unable to encode permit: %w", err)
+	}
+	structHash := crypto.Keccak256(data)
+	return crypto.Keccak256([]byte{0x19, 0x01}, separator.Bytes(), structHash.Bytes()), nil
+}
+
+// SignERC20Permit signs the given permit using the given signer and returns
+// the signature in the form expected by the permit method of the token:
+// permit(owner, spender, value, deadline, v, r, s).
+//
+// The signer address must be the same as the permit owner.
+func SignERC20Permit(ctx context.Context, signer wallet.Signer, permit *ERC20Permit) (v uint8, r, s types.Hash, err error) {
+	if signer.Address() != permit.Owner {
+		return 0, types.Hash{}, types.Hash{}, fmt.Errorf("token: signer address %s does not match permit owner %s", signer.Address(), permit.Owner)
+	}
+	digest, err := permit.Digest()
+	if err != nil {
+		return 0, types.Hash{}, types.Hash{}, err
+	}
+	sig, err := signer.SignHash(ctx, digest)
+	if err != nil {
+		return 0, types.Hash{}, types.Hash{}, fmt.Errorf("token: unable to sign permit: %w", err)
+	}
+	if sig.V == nil || sig.R == nil || sig.S == nil {
+		return 0, types.Hash{}, types.Hash{}, fmt.Errorf("token: invalid permit signature")
+	}
+	sv := sig.V.Uint64()
+	if sv < 27 {
+		sv += 27
+	}
+	if r, err = types.HashFromBigInt(sig.R); err != nil {
+		return 0, types.Hash{}, types.Hash{}, fmt.Errorf("token: invalid permit signature: %w", err)
+	}
+	if s, err = types.HashFromBigInt(sig.S); err != nil {
+		return 0, types.Hash{}, types.Hash{}, fmt.Errorf("token: invalid permit signature: %w", err)
+	}
+	return uint8(sv), r, s, nil
+}
+
+// bigOrZero returns x or zero if x is nil.
+func bigOrZero(x *big.Int) *big.Int {
+	if x == nil {
+		return big.NewInt(0)
+	}
+	return x
+}
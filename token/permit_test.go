@@ -0,0 +1,103 @@
+package token
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/defiweb/go-eth/hexutil"
+	"github.com/defiweb/go-eth/types"
+	"github.com/defiweb/go-eth/wallet"
+)
+
+func TestSignERC20Permit(t *testing.T) {
+	key := wallet.NewKeyFromBytes(hexutil.MustHexToBytes("0x4646464646464646464646464646464646464646464646464646464646464646"))
+	permit := &ERC20Permit{
+		Name:     "Dai Stablecoin",
+		Version:  "1",
+		ChainID:  1,
+		Token:    types.MustAddressFromHex("0x6b175474e89094c44da98b954eedeac495271d0f"),
+		Owner:    key.Address(),
+		Spender:  types.MustAddressFromHex("0x1111111111111111111111111111111111111111"),
+		Value:    big.NewInt(1000),
+		Nonce:    big.NewInt(0),
+		Deadline: big.NewInt(1700000000),
+	}
+
+	assert.Equal(t, types.MustHashFromHex("0x6e71edae12b1b97f4d1f60370fef10105fa2faae0126114a169c64845d6126c9", types.PadNone), permitTypeHash)
+
+	v, r, s, err := SignERC20Permit(context.Background(), key, permit)
+	require.NoError(t, err)
+	assert.Contains(t, []uint8{27, 28}, v)
+
+	digest, err := permit.Digest()
+	require.NoError(t, err)
+	sig := append(append(r.Bytes(), s.Bytes()...), v)
+	addr, err := wallet.RecoverCompact(digest, sig)
+	require.NoError(t, err)
+	assert.Equal(t, key.Address(), addr)
+
+	// Signer must be the owner:
+	permit.Owner = permit.Spender
+	_, _, _, err = SignERC20Permit(context.Background(), key, permit)
+	assert.Error(t, err)
+}
+
+func TestERC20Permit_TypedData(t *testing.T) {
+	permit := &ERC20Permit{
+		Name:     "Dai Stablecoin",
+		Version:  "1",
+		ChainID:  1,
+		Token:    types.MustAddressFromHex("0x6b175474e89094c44da98b954eedeac495271d0f"),
+		Owner:    types.MustAddressFromHex("0x2222222222222222222222222222222222222222"),
+		Spender:  types.MustAddressFromHex("0x1111111111111111111111111111111111111111"),
+		Value:    big.NewInt(1000),
+		Deadline: big.NewInt(1700000000),
+	}
+	td := permit.TypedData()
+	assert.Equal(t, "Permit", td.PrimaryType)
+
+	// The typed data domain must match the domain used to compute the digest.
+	sep, err := td.Domain.Separator()
+	require.NoError(t, err)
+	domain := permit.Domain()
+	expSep, err := domain.Separator()
+	require.NoError(t, err)
+	assert.Equal(t, expSep, sep)
+
+	j, err := td.MarshalJSON()
+	require.NoError(t, err)
+	parsed, err := types.ParseTypedData(j)
+	require.NoError(t, err)
+	assert.Equal(t, json.Number("0"), parsed.Message["nonce"])
+	assert.Equal(t, json.Number("1700000000"), parsed.Message["deadline"])
+	assert.Equal(t, []types.TypedDataField{
+		{Name: "name", Type: "string"},
+		{Name: "version", Type: "string"},
+		{Name: "chainId", Type: "uint256"},
+		{Name: "verifyingContract", Type: "address"},
+	}, parsed.Types["EIP712Domain"])
+}
+
+func TestERC20Permit_TypedData_EmptyVersion(t *testing.T) {
+	permit := &ERC20Permit{
+		Name:    "Token",
+		ChainID: 1,
+		Token:   types.MustAddressFromHex("0x6b175474e89094c44da98b954eedeac495271d0f"),
+	}
+	td := permit.TypedData()
+
+	// Fields that are not a part of the domain separator must not be a part
+	// of the EIP712Domain type, otherwise wallets would compute a different
+	// digest.
+	assert.Equal(t, []types.TypedDataField{
+		{Name: "name", Type: "string"},
+		{Name: "chainId", Type: "uint256"},
+		{Name: "verifyingContract", Type: "address"},
+	}, td.Types["EIP712Domain"])
+	assert.Equal(t, "EIP712Domain(string name,uint256 chainId,address verifyingContract)", td.Domain.EncodeType())
+}
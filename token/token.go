@@ -0,0 +1,82 @@
+package token
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/defiweb/go-eth/abi"
+	"github.com/defiweb/go-eth/rpc"
+	"github.com/defiweb/go-eth/types"
+)
+
+var (
+	nameMethod     = abi.MustParseMethod("function name() view returns (string)")
+	symbolMethod   = abi.MustParseMethod("function symbol() view returns (string)")
+	decimalsMethod = abi.MustParseMethod("function decimals() view returns (uint8)")
+	tokenURIMethod = abi.MustParseMethod("function tokenURI(uint256 tokenId) view returns (string)")
+)
+
+// ERC20Info returns the name, symbol and decimals of the ERC-20 token at the
+// given address.
+//
+// Some older tokens, like MKR, return the name and symbol as bytes32 instead
+// of string. Both forms are supported.
+func ERC20Info(ctx context.Context, client rpc.RPC, addr types.Address) (name, symbol string, decimals uint8, err error) {
+	name, err = callString(ctx, client, addr, nameMethod)
+	if err != nil {
+		return "", "", 0, err
+	}
+	symbol, err = callString(ctx, client, addr, symbolMethod)
+	if err != nil {
+		return "", "", 0, err
+	}
+	data, err := call(ctx, client, addr, decimalsMethod)
+	if err != nil {
+		return "", "", 0, err
+	}
+	if err := decimalsMethod.DecodeValues(data, &decimals); err != nil {
+		return "", "", 0, fmt.Errorf("token: unable to decode decimals: %w", err)
+	}
+	return name, symbol, decimals, nil
+}
+
+// ERC721TokenURI returns the URI of the given token of the ERC-721 contract
+// at the given address.
+func ERC721TokenURI(ctx context.Context, client rpc.RPC, addr types.Address, tokenID *big.Int) (string, error) {
+	if tokenID == nil {
+		return "", fmt.Errorf("token: token ID is nil")
+	}
+	return callString(ctx, client, addr, tokenURIMethod, tokenID)
+}
+
+// call calls the given method on the contract at the given address.
+func call(ctx context.Context, client rpc.RPC, addr types.Address, method *abi.Method, args ...any) ([]byte, error) {
+	input, err := method.EncodeArgs(args...)
+	if err != nil {
+		return nil, fmt.Errorf("token: unable to encode %s call: %w", method.Name(), err)
+	}
+	data, _, err := client.Call(ctx, types.NewCall().SetTo(addr).SetInput(input), types.LatestBlockNumber)
+	if err != nil {
+		return nil, fmt.Errorf("token: %s call failed: %w", method.Name(), err)
+	}
+	return data, nil
+}
+
+// callString calls the given method that returns a string. If the method
+// returns exactly 32 bytes, the result is decoded as a null-padded bytes32.
+func callString(ctx context.Context, client rpc.RPC, addr types.Address, method *abi.Method, args ...any) (string, error) {
+	data, err := call(ctx, client, addr, method, args...)
+	if err != nil {
+		return "", err
+	}
+	if len(data) == abi.WordLength {
+		return string(bytes.TrimRight(data, "\x00")), nil
+	}
+	var s string
+	if err := method.DecodeValues(data, &s); err != nil {
+		return "", fmt.Errorf("token: unable to decode %s: %w", method.Name(), err)
+	}
+	return s, nil
+}
@@ -0,0 +1,102 @@
+package token
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/defiweb/go-eth/abi"
+	"github.com/defiweb/go-eth/hexutil"
+	"github.com/defiweb/go-eth/rpc"
+	"github.com/defiweb/go-eth/types"
+)
+
+// callMock is a transport that responds to eth_call requests based on the
+// method selector.
+type callMock map[string][]byte
+
+func (m callMock) Call(_ context.Context, result any, method string, args ...any) error {
+	if method != "eth_call" {
+		return errors.New("unexpected method")
+	}
+	input := args[0].(*types.Call).Input
+	res, ok := m[hexutil.BytesToHex(input[:4])]
+	if !ok {
+		return errors.New("execution reverted")
+	}
+	*result.(*types.Bytes) = res
+	return nil
+}
+
+func TestERC20Info(t *testing.T) {
+	tests := []struct {
+		name         string
+		mock         callMock
+		wantName     string
+		wantSymbol   string
+		wantDecimals uint8
+		wantErr      bool
+	}{
+		{
+			name: "string",
+			mock: callMock{
+				hexutil.BytesToHex(nameMethod.FourBytes().Bytes()):     abi.MustEncodeValues(abi.MustParseType("(string)"), "Wrapped Ether"),
+				hexutil.BytesToHex(symbolMethod.FourBytes().Bytes()):   abi.MustEncodeValues(abi.MustParseType("(string)"), "WETH"),
+				hexutil.BytesToHex(decimalsMethod.FourBytes().Bytes()): abi.MustEncodeValues(abi.MustParseType("(uint8)"), 18),
+			},
+			wantName:     "Wrapped Ether",
+			wantSymbol:   "WETH",
+			wantDecimals: 18,
+		},
+		{
+			name: "bytes32",
+			mock: callMock{
+				hexutil.BytesToHex(nameMethod.FourBytes().Bytes()):     abi.MustEncodeValues(abi.MustParseType("(bytes32)"), [32]byte{'M', 'a', 'k', 'e', 'r'}),
+				hexutil.BytesToHex(symbolMethod.FourBytes().Bytes()):   abi.MustEncodeValues(abi.MustParseType("(bytes32)"), [32]byte{'M', 'K', 'R'}),
+				hexutil.BytesToHex(decimalsMethod.FourBytes().Bytes()): abi.MustEncodeValues(abi.MustParseType("(uint8)"), 18),
+			},
+			wantName:     "Maker",
+			wantSymbol:   "MKR",
+			wantDecimals: 18,
+		},
+		{
+			name: "missing decimals",
+			mock: callMock{
+				hexutil.BytesToHex(nameMethod.FourBytes().Bytes()):   abi.MustEncodeValues(abi.MustParseType("(string)"), "Wrapped Ether"),
+				hexutil.BytesToHex(symbolMethod.FourBytes().Bytes()): abi.MustEncodeValues(abi.MustParseType("(string)"), "WETH"),
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, err := rpc.NewClient(rpc.WithTransport(tt.mock))
+			require.NoError(t, err)
+
+			name, symbol, decimals, err := ERC20Info(context.Background(), client, types.ZeroAddress)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantName, name)
+			assert.Equal(t, tt.wantSymbol, symbol)
+			assert.Equal(t, tt.wantDecimals, decimals)
+		})
+	}
+}
+
+func TestERC721TokenURI(t *testing.T) {
+	client, err := rpc.NewClient(rpc.WithTransport(callMock{
+		hexutil.BytesToHex(tokenURIMethod.FourBytes().Bytes()): abi.MustEncodeValues(abi.MustParseType("(string)"), "ipfs://foo/1"),
+	}))
+	require.NoError(t, err)
+
+	uri, err := ERC721TokenURI(context.Background(), client, types.ZeroAddress, big.NewInt(1))
+	require.NoError(t, err)
+	assert.Equal(t, "ipfs://foo/1", uri)
+}
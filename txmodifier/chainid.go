@@ -15,10 +15,12 @@ import (
 // To use this modifier, add it using the WithTXModifiers option when creating
 // a new rpc.Client.
 type ChainIDProvider struct {
-	mu      sync.Mutex
-	chainID uint64
-	replace bool
-	cache   bool
+	mu          sync.Mutex
+	chainID     uint64
+	replace     bool
+	cache       bool
+	verify      bool
+	nodeChainID uint64 // Chain ID returned by the node, set after a successful verification.
 }
 
 // ChainIDProviderOptions is the options for NewChainIDProvider.
@@ -37,6 +39,15 @@ type ChainIDProviderOptions struct {
 	//
 	// If ChainID is set, this option is ignored.
 	Cache bool
+
+	// Verify is true if the chain ID of the transaction should be compared
+	// with the chain ID returned by the node. It is either the ChainID or,
+	// unless Replace is set, the chain ID already set on the transaction,
+	// for example by the rpc.WithChainID option. If they differ, an error is
+	// returned to prevent signing transactions for the wrong network. The
+	// node is queried until the check succeeds, after which its chain ID is
+	// cached.
+	Verify bool
 }
 
 // NewChainIDProvider returns a new ChainIDProvider.
@@ -48,13 +59,19 @@ func NewChainIDProvider(opts ChainIDProviderOptions) *ChainIDProvider {
 		chainID: opts.ChainID,
 		replace: opts.Replace,
 		cache:   opts.Cache,
+		verify:  opts.Verify,
 	}
 }
 
 // Modify implements the rpc.TXModifier interface.
 func (p *ChainIDProvider) Modify(ctx context.Context, client rpc.RPC, tx *types.Transaction) error {
 	if !p.replace && tx.ChainID != nil {
-		return nil
+		if !p.verify {
+			return nil
+		}
+		p.mu.Lock()
+		defer p.mu.Unlock()
+		return p.verifyChainID(ctx, client, *tx.ChainID)
 	}
 	if !p.cache {
 		chainID, err := client.ChainID(ctx)
@@ -66,17 +83,43 @@ func (p *ChainIDProvider) Modify(ctx context.Context, client rpc.RPC, tx *types.
 	}
 	p.mu.Lock()
 	defer p.mu.Unlock()
-	var cid uint64
 	if p.chainID != 0 {
-		cid = p.chainID
+		if p.verify {
+			if err := p.verifyChainID(ctx, client, p.chainID); err != nil {
+				return err
+			}
+		}
 	} else {
 		chainID, err := client.ChainID(ctx)
 		if err != nil {
 			return fmt.Errorf("chain ID provider: %w", err)
 		}
 		p.chainID = chainID
-		cid = chainID
 	}
+	cid := p.chainID
 	tx.ChainID = &cid
 	return nil
 }
+
+// verifyChainID compares the given chain ID with the chain ID returned by
+// the node. The mutex must be locked by the caller.
+func (p *ChainIDProvider) verifyChainID(ctx context.Context, client rpc.RPC, chainID uint64) error {
+	nodeChainID := p.nodeChainID
+	if nodeChainID == 0 {
+		var err error
+		nodeChainID, err = client.ChainID(ctx)
+		if err != nil {
+			return fmt.Errorf("chain ID provider: %w", err)
+		}
+	}
+	if chainID != nodeChainID {
+		return fmt.Errorf("chain ID provider: chain ID mismatch, expected %d, node returned %d", chainID, nodeChainID)
+	}
+	p.nodeChainID = nodeChainID
+	return nil
+}
+
+// TXOnly implements the rpc.TXOnlyModifier interface.
+func (p *ChainIDProvider) TXOnly() bool {
+	return true
+}
@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/defiweb/go-eth/types"
 )
@@ -84,6 +85,66 @@ func TestChainIDSetter_Modify(t *testing.T) {
 	})
 }
 
+func TestChainIDSetter_Verify(t *testing.T) {
+	ctx := context.Background()
+	fromAddress := types.MustAddressFromHex("0x1234567890abcdef1234567890abcdef12345678")
+
+	t.Run("matching chain ID", func(t *testing.T) {
+		tx := &types.Transaction{Call: types.Call{From: &fromAddress}}
+		rpcMock := new(mockRPC)
+		rpcMock.On("ChainID", ctx).Return(uint64(1), nil).Once()
+
+		provider := NewChainIDProvider(ChainIDProviderOptions{
+			ChainID: 1,
+			Verify:  true,
+		})
+		require.NoError(t, provider.Modify(ctx, rpcMock, tx))
+		require.NoError(t, provider.Modify(ctx, rpcMock, tx))
+
+		assert.Equal(t, uint64(1), *tx.ChainID)
+		rpcMock.AssertExpectations(t)
+	})
+
+	t.Run("mismatched chain ID", func(t *testing.T) {
+		tx := &types.Transaction{Call: types.Call{From: &fromAddress}}
+		rpcMock := new(mockRPC)
+		rpcMock.On("ChainID", ctx).Return(uint64(5), nil)
+
+		provider := NewChainIDProvider(ChainIDProviderOptions{
+			ChainID: 1,
+			Verify:  true,
+		})
+		require.Error(t, provider.Modify(ctx, rpcMock, tx))
+		require.Error(t, provider.Modify(ctx, rpcMock, tx))
+
+		assert.Nil(t, tx.ChainID)
+		rpcMock.AssertNumberOfCalls(t, "ChainID", 2)
+	})
+
+	t.Run("chain ID set on transaction", func(t *testing.T) {
+		rpcMock := new(mockRPC)
+		rpcMock.On("ChainID", ctx).Return(uint64(1), nil).Twice()
+
+		provider := NewChainIDProvider(ChainIDProviderOptions{
+			Verify: true,
+		})
+
+		// The chain ID is verified even if it is already set, for example
+		// by the rpc.WithChainID option.
+		tx := &types.Transaction{Call: types.Call{From: &fromAddress}, ChainID: uint64Ptr(5)}
+		require.Error(t, provider.Modify(ctx, rpcMock, tx))
+		assert.Equal(t, uint64(5), *tx.ChainID)
+
+		// The node's chain ID is cached after a successful verification.
+		tx = &types.Transaction{Call: types.Call{From: &fromAddress}, ChainID: uint64Ptr(1)}
+		require.NoError(t, provider.Modify(ctx, rpcMock, tx))
+		require.NoError(t, provider.Modify(ctx, rpcMock, tx))
+		tx = &types.Transaction{Call: types.Call{From: &fromAddress}, ChainID: uint64Ptr(5)}
+		require.Error(t, provider.Modify(ctx, rpcMock, tx))
+		rpcMock.AssertExpectations(t)
+	})
+}
+
 func uint64Ptr(i uint64) *uint64 {
 	return &i
 }
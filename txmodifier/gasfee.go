@@ -73,7 +73,11 @@ func (e *LegacyGasFeeEstimator) Modify(ctx context.Context, client rpc.RPC, tx *
 // EIP1559GasFeeEstimator is a transaction modifier that estimates gas fee
 // using the rpc.GasPrice and rpc.MaxPriorityFeePerGas methods.
 //
-// It sets transaction type to types.DynamicFeeTxType.
+// If the UseBaseFee option is set, the base fee of the latest block is used
+// instead of the rpc.GasPrice method.
+//
+// It sets transaction type to types.DynamicFeeTxType, unless the transaction
+// is of types.BlobTxType, which already uses EIP-1559 fees.
 type EIP1559GasFeeEstimator struct {
 	gasPriceMultiplier          float64
 	priorityFeePerGasMultiplier float64
@@ -82,6 +86,7 @@ type EIP1559GasFeeEstimator struct {
 	minPriorityFeePerGas        *big.Int
 	maxPriorityFeePerGas        *big.Int
 	replace                     bool
+	useBaseFee                  bool
 }
 
 // EIP1559GasFeeEstimatorOptions is the options for NewEIP1559GasFeeEstimator.
@@ -93,6 +98,14 @@ type EIP1559GasFeeEstimatorOptions struct {
 	MinPriorityFeePerGas        *big.Int // MinPriorityFeePerGas is the minimum priority fee per gas, or nil if there is no lower bound.
 	MaxPriorityFeePerGas        *big.Int // MaxPriorityFeePerGas is the maximum priority fee per gas, or nil if there is no upper bound.
 	Replace                     bool     // Replace is true if the gas price should be replaced even if it is already set.
+
+	// UseBaseFee is true if the max fee per gas should be calculated from
+	// the base fee of the latest block using types.FeeCap, instead
+	// of using the gas price returned by the node. The GasPriceMultiplier is
+	// applied to the calculated value.
+	//
+	// It is useful on chains that do not provide a reliable gas price.
+	UseBaseFee bool
 }
 
 // NewEIP1559GasFeeEstimator returns a new EIP1559GasFeeEstimator.
@@ -108,6 +121,7 @@ func NewEIP1559GasFeeEstimator(opts EIP1559GasFeeEstimatorOptions) *EIP1559GasFe
 		minPriorityFeePerGas:        opts.MinPriorityFeePerGas,
 		maxPriorityFeePerGas:        opts.MaxPriorityFeePerGas,
 		replace:                     opts.Replace,
+		useBaseFee:                  opts.UseBaseFee,
 	}
 }
 
@@ -116,14 +130,30 @@ func (e *EIP1559GasFeeEstimator) Modify(ctx context.Context, client rpc.RPC, tx
 	if !e.replace && tx.MaxFeePerGas != nil && tx.MaxPriorityFeePerGas != nil {
 		return nil
 	}
-	maxFeePerGas, err := client.GasPrice(ctx)
-	if err != nil {
-		return fmt.Errorf("EIP-1559 gas fee estimator: failed to get gas price: %w", err)
+	var (
+		maxFeePerGas *big.Int
+		err          error
+	)
+	if !e.useBaseFee {
+		maxFeePerGas, err = client.GasPrice(ctx)
+		if err != nil {
+			return fmt.Errorf("EIP-1559 gas fee estimator: failed to get gas price: %w", err)
+		}
 	}
 	priorityFeePerGas, err := client.MaxPriorityFeePerGas(ctx)
 	if err != nil {
 		return fmt.Errorf("EIP-1559 gas fee estimator: failed to get max priority fee per gas: %w", err)
 	}
+	if e.useBaseFee {
+		block, err := client.BlockByNumber(ctx, types.LatestBlockNumber, false)
+		if err != nil {
+			return fmt.Errorf("EIP-1559 gas fee estimator: failed to get latest block: %w", err)
+		}
+		if block.BaseFeePerGas == nil {
+			return fmt.Errorf("EIP-1559 gas fee estimator: latest block has no base fee")
+		}
+		maxFeePerGas = types.FeeCap(block.BaseFeePerGas, priorityFeePerGas)
+	}
 	maxFeePerGas, _ = new(big.Float).Mul(new(big.Float).SetInt(maxFeePerGas), big.NewFloat(e.gasPriceMultiplier)).Int(nil)
 	priorityFeePerGas, _ = new(big.Float).Mul(new(big.Float).SetInt(priorityFeePerGas), big.NewFloat(e.priorityFeePerGasMultiplier)).Int(nil)
 	if e.minGasPrice != nil && maxFeePerGas.Cmp(e.minGasPrice) < 0 {
@@ -144,6 +174,64 @@ func (e *EIP1559GasFeeEstimator) Modify(ctx context.Context, client rpc.RPC, tx
 	tx.GasPrice = nil
 	tx.MaxFeePerGas = maxFeePerGas
 	tx.MaxPriorityFeePerGas = priorityFeePerGas
-	tx.Type = types.DynamicFeeTxType
+	if tx.Type == types.LegacyTxType || tx.Type == types.AccessListTxType {
+		tx.Type = types.DynamicFeeTxType
+	}
+	return nil
+}
+
+// BlobFeeEstimator is a transaction modifier that estimates the blob gas fee
+// using the rpc.BlobBaseFee method.
+//
+// It sets the MaxFeePerBlobGas field of transactions of type
+// types.BlobTxType. Other transactions are left unchanged.
+type BlobFeeEstimator struct {
+	multiplier      float64
+	minBlobGasPrice *big.Int
+	maxBlobGasPrice *big.Int
+	replace         bool
+}
+
+// BlobFeeEstimatorOptions is the options for NewBlobFeeEstimator.
+type BlobFeeEstimatorOptions struct {
+	Multiplier      float64  // Multiplier is applied to the blob base fee.
+	MinBlobGasPrice *big.Int // MinBlobGasPrice is the minimum fee per blob gas, or nil if there is no lower bound.
+	MaxBlobGasPrice *big.Int // MaxBlobGasPrice is the maximum fee per blob gas, or nil if there is no upper bound.
+	Replace         bool     // Replace is true if the fee per blob gas should be replaced even if it is already set.
+}
+
+// NewBlobFeeEstimator returns a new BlobFeeEstimator.
+//
+// To use this modifier, add it using the WithTXModifiers option when creating
+// a new rpc.Client.
+func NewBlobFeeEstimator(opts BlobFeeEstimatorOptions) *BlobFeeEstimator {
+	return &BlobFeeEstimator{
+		multiplier:      opts.Multiplier,
+		minBlobGasPrice: opts.MinBlobGasPrice,
+		maxBlobGasPrice: opts.MaxBlobGasPrice,
+		replace:         opts.Replace,
+	}
+}
+
+// Modify implements the rpc.TXModifier interface.
+func (e *BlobFeeEstimator) Modify(ctx context.Context, client rpc.RPC, tx *types.Transaction) error {
+	if tx.Type != types.BlobTxType {
+		return nil
+	}
+	if !e.replace && tx.MaxFeePerBlobGas != nil {
+		return nil
+	}
+	blobBaseFee, err := client.BlobBaseFee(ctx)
+	if err != nil {
+		return fmt.Errorf("blob fee estimator: failed to get blob base fee: %w", err)
+	}
+	blobBaseFee, _ = new(big.Float).Mul(new(big.Float).SetInt(blobBaseFee), big.NewFloat(e.multiplier)).Int(nil)
+	if e.minBlobGasPrice != nil && blobBaseFee.Cmp(e.minBlobGasPrice) < 0 {
+		blobBaseFee = e.minBlobGasPrice
+	}
+	if e.maxBlobGasPrice != nil && blobBaseFee.Cmp(e.maxBlobGasPrice) > 0 {
+		blobBaseFee = e.maxBlobGasPrice
+	}
+	tx.MaxFeePerBlobGas = blobBaseFee
 	return nil
 }
@@ -7,6 +7,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"github.com/defiweb/go-eth/types"
 )
@@ -201,4 +202,129 @@ func TestEIP1559GasFeeEstimator_Modify(t *testing.T) {
 		assert.Equal(t, big.NewInt(500), tx.MaxFeePerGas)
 		assert.Equal(t, big.NewInt(500), tx.MaxPriorityFeePerGas) // should not be higher than tx.MaxFeePerGas
 	})
+
+	t.Run("base fee from latest block", func(t *testing.T) {
+		tx := &types.Transaction{}
+		rpcMock := new(mockRPC)
+		rpcMock.On("MaxPriorityFeePerGas", ctx).Return(big.NewInt(5), nil)
+		rpcMock.On("BlockByNumber", ctx, types.LatestBlockNumber, false).Return(&types.Block{BaseFeePerGas: big.NewInt(100)}, nil)
+
+		estimator := NewEIP1559GasFeeEstimator(EIP1559GasFeeEstimatorOptions{
+			GasPriceMultiplier:          1.5,
+			PriorityFeePerGasMultiplier: 1.0,
+			UseBaseFee:                  true,
+		})
+		err := estimator.Modify(ctx, rpcMock, tx)
+
+		assert.NoError(t, err)
+		assert.Equal(t, big.NewInt(307), tx.MaxFeePerGas) // (2 * 100 + 5) * 1.5
+		assert.Equal(t, big.NewInt(5), tx.MaxPriorityFeePerGas)
+		assert.Equal(t, types.DynamicFeeTxType, tx.Type)
+		rpcMock.AssertNotCalled(t, "GasPrice", ctx)
+	})
+
+	t.Run("base fee missing in latest block", func(t *testing.T) {
+		tx := &types.Transaction{}
+		rpcMock := new(mockRPC)
+		rpcMock.On("MaxPriorityFeePerGas", ctx).Return(big.NewInt(5), nil)
+		rpcMock.On("BlockByNumber", ctx, types.LatestBlockNumber, false).Return(&types.Block{}, nil)
+
+		estimator := NewEIP1559GasFeeEstimator(EIP1559GasFeeEstimatorOptions{
+			GasPriceMultiplier:          1.0,
+			PriorityFeePerGasMultiplier: 1.0,
+			UseBaseFee:                  true,
+		})
+		err := estimator.Modify(ctx, rpcMock, tx)
+
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "no base fee")
+	})
+}
+
+func TestEIP1559GasFeeEstimator_BlobTransaction(t *testing.T) {
+	ctx := context.Background()
+	tx := (&types.Transaction{}).
+		SetType(types.BlobTxType).
+		SetBlobVersionedHashes([]types.Hash{types.MustHashFromHex("0x01", types.PadLeft)})
+	rpcMock := new(mockRPC)
+	rpcMock.On("GasPrice", ctx).Return(big.NewInt(1000), nil)
+	rpcMock.On("MaxPriorityFeePerGas", ctx).Return(big.NewInt(5), nil)
+	rpcMock.On("BlobBaseFee", ctx).Return(big.NewInt(100), nil)
+
+	require.NoError(t, NewEIP1559GasFeeEstimator(EIP1559GasFeeEstimatorOptions{
+		GasPriceMultiplier:          1,
+		PriorityFeePerGasMultiplier: 1,
+	}).Modify(ctx, rpcMock, tx))
+	require.NoError(t, NewBlobFeeEstimator(BlobFeeEstimatorOptions{
+		Multiplier: 1,
+	}).Modify(ctx, rpcMock, tx))
+
+	assert.Equal(t, types.BlobTxType, tx.Type)
+	assert.Equal(t, big.NewInt(1000), tx.MaxFeePerGas)
+	assert.Equal(t, big.NewInt(5), tx.MaxPriorityFeePerGas)
+	assert.Equal(t, big.NewInt(100), tx.MaxFeePerBlobGas)
+}
+
+func TestBlobFeeEstimator_Modify(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("successful blob fee estimation", func(t *testing.T) {
+		tx := (&types.Transaction{}).SetType(types.BlobTxType)
+		rpcMock := new(mockRPC)
+		rpcMock.On("BlobBaseFee", ctx).Return(big.NewInt(1000), nil)
+		estimator := NewBlobFeeEstimator(BlobFeeEstimatorOptions{
+			Multiplier:      2,
+			MinBlobGasPrice: big.NewInt(500),
+			MaxBlobGasPrice: big.NewInt(5000),
+		})
+		err := estimator.Modify(ctx, rpcMock, tx)
+
+		assert.NoError(t, err)
+		assert.Equal(t, big.NewInt(2000), tx.MaxFeePerBlobGas)
+	})
+
+	t.Run("blob fee estimation error", func(t *testing.T) {
+		tx := (&types.Transaction{}).SetType(types.BlobTxType)
+		rpcMock := new(mockRPC)
+		rpcMock.On("BlobBaseFee", ctx).Return((*big.Int)(nil), errors.New("rpc error"))
+		estimator := NewBlobFeeEstimator(BlobFeeEstimatorOptions{Multiplier: 1})
+		err := estimator.Modify(ctx, rpcMock, tx)
+
+		assert.Error(t, err)
+	})
+
+	t.Run("clamping", func(t *testing.T) {
+		tx := (&types.Transaction{}).SetType(types.BlobTxType)
+		rpcMock := new(mockRPC)
+		rpcMock.On("BlobBaseFee", ctx).Return(big.NewInt(1), nil)
+		estimator := NewBlobFeeEstimator(BlobFeeEstimatorOptions{
+			Multiplier:      1,
+			MinBlobGasPrice: big.NewInt(10),
+		})
+		err := estimator.Modify(ctx, rpcMock, tx)
+
+		assert.NoError(t, err)
+		assert.Equal(t, big.NewInt(10), tx.MaxFeePerBlobGas)
+	})
+
+	t.Run("non-blob transaction", func(t *testing.T) {
+		tx := (&types.Transaction{}).SetType(types.DynamicFeeTxType)
+		rpcMock := new(mockRPC)
+		estimator := NewBlobFeeEstimator(BlobFeeEstimatorOptions{Multiplier: 1})
+		err := estimator.Modify(ctx, rpcMock, tx)
+
+		assert.NoError(t, err)
+		assert.Nil(t, tx.MaxFeePerBlobGas)
+		rpcMock.AssertNotCalled(t, "BlobBaseFee", ctx)
+	})
+
+	t.Run("already set", func(t *testing.T) {
+		tx := (&types.Transaction{}).SetType(types.BlobTxType).SetMaxFeePerBlobGas(big.NewInt(42))
+		rpcMock := new(mockRPC)
+		estimator := NewBlobFeeEstimator(BlobFeeEstimatorOptions{Multiplier: 1})
+		err := estimator.Modify(ctx, rpcMock, tx)
+
+		assert.NoError(t, err)
+		assert.Equal(t, big.NewInt(42), tx.MaxFeePerBlobGas)
+	})
 }
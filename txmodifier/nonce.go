@@ -58,3 +58,8 @@ func (p *NonceProvider) Modify(ctx context.Context, client rpc.RPC, tx *types.Tr
 	tx.Nonce = &pendingNonce
 	return nil
 }
+
+// TXOnly implements the rpc.TXOnlyModifier interface.
+func (p *NonceProvider) TXOnly() bool {
+	return true
+}
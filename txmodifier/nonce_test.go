@@ -2,11 +2,14 @@ package txmodifier
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
+	"github.com/defiweb/go-eth/rpc"
 	"github.com/defiweb/go-eth/types"
 )
 
@@ -67,3 +70,25 @@ func TestNonceProvider_Modify(t *testing.T) {
 		assert.Contains(t, err.Error(), "nonce provider")
 	})
 }
+
+func TestNonceProvider_PrepareCall(t *testing.T) {
+	var methods []string
+	client, err := rpc.NewClient(
+		rpc.WithTransport(transportFunc(func(_ context.Context, result any, method string, _ ...any) error {
+			methods = append(methods, method)
+			return json.Unmarshal([]byte(`"0x5208"`), result)
+		})),
+		rpc.WithTXModifiers(
+			NewNonceProvider(NonceProviderOptions{}),
+			NewChainIDProvider(ChainIDProviderOptions{}),
+		),
+	)
+	require.NoError(t, err)
+
+	// The nonce and chain ID providers are not applied to calls, so a call
+	// without the from address can be prepared.
+	call, err := client.PrepareCall(context.Background(), types.NewCall().SetTo(types.ZeroAddress))
+	require.NoError(t, err)
+	assert.Equal(t, uint64(21000), *call.GasLimit)
+	assert.Equal(t, []string{"eth_estimateGas"}, methods)
+}
@@ -10,6 +10,13 @@ import (
 	"github.com/defiweb/go-eth/types"
 )
 
+// transportFunc is a transport.Transport implemented by a function.
+type transportFunc func(ctx context.Context, result any, method string, args ...any) error
+
+func (f transportFunc) Call(ctx context.Context, result any, method string, args ...any) error {
+	return f(ctx, result, method, args...)
+}
+
 type mockRPC struct {
 	rpc.Client
 	mock.Mock
@@ -35,6 +42,16 @@ func (m *mockRPC) MaxPriorityFeePerGas(ctx context.Context) (*big.Int, error) {
 	return args.Get(0).(*big.Int), args.Error(1)
 }
 
+func (m *mockRPC) BlobBaseFee(ctx context.Context) (*big.Int, error) {
+	args := m.Called(ctx)
+	return args.Get(0).(*big.Int), args.Error(1)
+}
+
+func (m *mockRPC) BlockByNumber(ctx context.Context, number types.BlockNumber, full bool) (*types.Block, error) {
+	args := m.Called(ctx, number, full)
+	return args.Get(0).(*types.Block), args.Error(1)
+}
+
 func (m *mockRPC) GetTransactionCount(ctx context.Context, address types.Address, block types.BlockNumber) (uint64, error) {
 	args := m.Called(ctx, address, block)
 	return args.Get(0).(uint64), args.Error(1)
@@ -0,0 +1,45 @@
+package txmodifier
+
+import (
+	"context"
+
+	"github.com/defiweb/go-eth/rpc"
+	"github.com/defiweb/go-eth/types"
+)
+
+// TypeInferrer is a transaction modifier that sets the transaction type based
+// on the fields set on the transaction, as described in the
+// types.Transaction.InferType method. For example, a transaction with an
+// access list but without EIP-1559 fee fields becomes an AccessListTxType
+// transaction.
+//
+// The modifier should be added after modifiers that set the fee fields, so
+// the type reflects the final fields of the transaction.
+//
+// To use this modifier, add it using the WithTXModifiers option when creating
+// a new rpc.Client.
+type TypeInferrer struct {
+	replace bool
+}
+
+// TypeInferrerOptions is the options for NewTypeInferrer.
+type TypeInferrerOptions struct {
+	// Replace is true if the transaction type should be replaced even if it
+	// is already set. Because LegacyTxType is the zero value, the legacy
+	// type is always treated as not set.
+	Replace bool
+}
+
+// NewTypeInferrer returns a new TypeInferrer.
+func NewTypeInferrer(opts TypeInferrerOptions) *TypeInferrer {
+	return &TypeInferrer{replace: opts.Replace}
+}
+
+// Modify implements the rpc.TXModifier interface.
+func (p *TypeInferrer) Modify(_ context.Context, _ rpc.RPC, tx *types.Transaction) error {
+	if !p.replace && tx.Type != types.LegacyTxType {
+		return nil
+	}
+	tx.Type = tx.InferType()
+	return nil
+}
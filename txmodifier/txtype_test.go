@@ -0,0 +1,50 @@
+package txmodifier
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/defiweb/go-eth/types"
+)
+
+func TestTypeInferrer_Modify(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("access list", func(t *testing.T) {
+		tx := types.NewTransaction().
+			SetGasPrice(big.NewInt(1)).
+			SetAccessList(types.AccessList{})
+
+		require.NoError(t, NewTypeInferrer(TypeInferrerOptions{}).Modify(ctx, new(mockRPC), tx))
+		assert.Equal(t, types.AccessListTxType, tx.Type)
+	})
+
+	t.Run("legacy", func(t *testing.T) {
+		tx := types.NewTransaction().SetGasPrice(big.NewInt(1))
+
+		require.NoError(t, NewTypeInferrer(TypeInferrerOptions{}).Modify(ctx, new(mockRPC), tx))
+		assert.Equal(t, types.LegacyTxType, tx.Type)
+	})
+
+	t.Run("do not replace type", func(t *testing.T) {
+		tx := types.NewTransaction().
+			SetAccessList(types.AccessList{}).
+			SetType(types.DynamicFeeTxType)
+
+		require.NoError(t, NewTypeInferrer(TypeInferrerOptions{}).Modify(ctx, new(mockRPC), tx))
+		assert.Equal(t, types.DynamicFeeTxType, tx.Type)
+	})
+
+	t.Run("replace type", func(t *testing.T) {
+		tx := types.NewTransaction().
+			SetAccessList(types.AccessList{}).
+			SetType(types.DynamicFeeTxType)
+
+		require.NoError(t, NewTypeInferrer(TypeInferrerOptions{Replace: true}).Modify(ctx, new(mockRPC), tx))
+		assert.Equal(t, types.AccessListTxType, tx.Type)
+	})
+}
@@ -0,0 +1,201 @@
+package types
+
+import (
+	"crypto/sha256"
+	"fmt"
+
+	"github.com/defiweb/go-rlp"
+)
+
+const (
+	// BlobLength is the length of a single EIP-4844 blob in bytes.
+	BlobLength = 131072
+
+	// KZGCommitmentLength is the length of a KZG commitment in bytes.
+	KZGCommitmentLength = 48
+
+	// KZGProofLength is the length of a KZG proof in bytes.
+	KZGProofLength = 48
+
+	// blobCommitmentVersionKZG is the version byte of versioned hashes of
+	// KZG commitments.
+	blobCommitmentVersionKZG = 0x01
+)
+
+// BlobSidecar contains the blobs of an EIP-4844 transaction, together with
+// their KZG commitments and proofs. The sidecar is not a part of the signed
+// transaction, it is only sent along with it using the network wrapper
+// encoding. See Transaction.EncodeNetworkWrapper.
+//
+// Commitments and proofs are not computed nor verified, they must be provided
+// by the caller.
+type BlobSidecar struct {
+	Blobs       [][]byte // Blobs is the list of blobs, each BlobLength bytes long.
+	Commitments [][]byte // Commitments is the list of KZG commitments to the blobs.
+	Proofs      [][]byte // Proofs is the list of KZG proofs of the blobs.
+}
+
+// Copy returns a deep copy of the sidecar.
+func (s *BlobSidecar) Copy() *BlobSidecar {
+	if s == nil {
+		return nil
+	}
+	return &BlobSidecar{
+		Blobs:       copyBytesList(s.Blobs),
+		Commitments: copyBytesList(s.Commitments),
+		Proofs:      copyBytesList(s.Proofs),
+	}
+}
+
+// VersionedHashes returns the versioned hashes of the blob commitments, as
+// defined in EIP-4844. They must be equal to the BlobVersionedHashes of the
+// transaction.
+func (s *BlobSidecar) VersionedHashes() []Hash {
+	hashes := make([]Hash, len(s.Commitments))
+	for i, c := range s.Commitments {
+		hashes[i] = Hash(sha256.Sum256(c))
+		hashes[i][0] = blobCommitmentVersionKZG
+	}
+	return hashes
+}
+
+// Validate checks whether the sidecar is consistent with the given versioned
+// hashes. It checks the number and lengths of the blobs, commitments and
+// proofs, and whether the commitments match the versioned hashes.
+func (s *BlobSidecar) Validate(hashes []Hash) error {
+	if len(s.Blobs) != len(hashes) || len(s.Commitments) != len(hashes) || len(s.Proofs) != len(hashes) {
+		return fmt.Errorf(
+			"invalid blob sidecar: got %d blobs, %d commitments and %d proofs for %d versioned hashes",
+			len(s.Blobs), len(s.Commitments), len(s.Proofs), len(hashes),
+		)
+	}
+	for i := range hashes {
+		if len(s.Blobs[i]) != BlobLength {
+			return fmt.Errorf("invalid blob sidecar: blob %d has invalid length: %d", i, len(s.Blobs[i]))
+		}
+		if len(s.Commitments[i]) != KZGCommitmentLength {
+			return fmt.Errorf("invalid blob sidecar: commitment %d has invalid length: %d", i, len(s.Commitments[i]))
+		}
+		if len(s.Proofs[i]) != KZGProofLength {
+			return fmt.Errorf("invalid blob sidecar: proof %d has invalid length: %d", i, len(s.Proofs[i]))
+		}
+	}
+	for i, h := range s.VersionedHashes() {
+		if h != hashes[i] {
+			return fmt.Errorf("invalid blob sidecar: commitment %d does not match versioned hash %s", i, hashes[i])
+		}
+	}
+	return nil
+}
+
+// EncodeNetworkWrapper encodes a blob transaction together with its sidecar
+// using the network wrapper form defined in EIP-4844:
+//
+//	0x03 || rlp([tx_payload_body, blobs, commitments, proofs])
+//
+// This form is expected by eth_sendRawTransaction for blob transactions.
+// The transaction must be of BlobTxType and must have the Sidecar field set.
+func (t Transaction) EncodeNetworkWrapper() ([]byte, error) {
+	if t.Type != BlobTxType {
+		return nil, fmt.Errorf("network wrapper is only supported for blob transactions")
+	}
+	if t.Sidecar == nil {
+		return nil, fmt.Errorf("blob transaction has no sidecar")
+	}
+	if err := t.Sidecar.Validate(t.BlobVersionedHashes); err != nil {
+		return nil, err
+	}
+	tx, err := t.EncodeRLP()
+	if err != nil {
+		return nil, err
+	}
+	var (
+		body        = rlp.RLP(tx[1:])
+		blobs       = bytesList(t.Sidecar.Blobs)
+		commitments = bytesList(t.Sidecar.Commitments)
+		proofs      = bytesList(t.Sidecar.Proofs)
+	)
+	bin, err := rlp.NewList(&body, &blobs, &commitments, &proofs).EncodeRLP()
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte{byte(BlobTxType)}, bin...), nil
+}
+
+// DecodeNetworkWrapper decodes a blob transaction encoded using the network
+// wrapper form, as returned by EncodeNetworkWrapper. The sidecar is decoded
+// into the Sidecar field.
+func (t *Transaction) DecodeNetworkWrapper(data []byte) (int, error) {
+	if len(data) == 0 || data[0] != byte(BlobTxType) {
+		return 0, fmt.Errorf("network wrapper is only supported for blob transactions")
+	}
+	var (
+		body        = &rlp.RLP{}
+		blobs       = &bytesList{}
+		commitments = &bytesList{}
+		proofs      = &bytesList{}
+	)
+	n, err := rlp.DecodeTo(data[1:], rlp.NewList(body, blobs, commitments, proofs))
+	if err != nil {
+		return 0, err
+	}
+	if !body.IsList() {
+		return 0, fmt.Errorf("invalid network wrapper: transaction payload is not a list")
+	}
+	tx := &Transaction{}
+	if _, err := tx.DecodeRLP(append([]byte{byte(BlobTxType)}, *body...)); err != nil {
+		return 0, err
+	}
+	tx.Sidecar = &BlobSidecar{
+		Blobs:       *blobs,
+		Commitments: *commitments,
+		Proofs:      *proofs,
+	}
+	if err := tx.Sidecar.Validate(tx.BlobVersionedHashes); err != nil {
+		return 0, err
+	}
+	*t = *tx
+	return n + 1, nil
+}
+
+// bytesList is a RLP list of byte strings.
+type bytesList [][]byte
+
+func (b bytesList) EncodeRLP() ([]byte, error) {
+	l := rlp.NewList()
+	for _, item := range b {
+		l.Append(rlp.NewBytes(item))
+	}
+	return rlp.Encode(l)
+}
+
+func (b *bytesList) DecodeRLP(data []byte) (int, error) {
+	d, n, err := rlp.Decode(data)
+	if err != nil {
+		return 0, err
+	}
+	l, err := d.GetList()
+	if err != nil {
+		return 0, err
+	}
+	*b = make(bytesList, 0, len(l))
+	for _, item := range l {
+		v, err := item.GetBytes()
+		if err != nil {
+			return 0, err
+		}
+		*b = append(*b, v)
+	}
+	return n, nil
+}
+
+func copyBytesList(l [][]byte) [][]byte {
+	if l == nil {
+		return nil
+	}
+	cpy := make([][]byte, len(l))
+	for i, b := range l {
+		cpy[i] = append([]byte(nil), b...)
+	}
+	return cpy
+}
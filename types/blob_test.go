@@ -0,0 +1,99 @@
+package types
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testBlobTransaction(blobs int) *Transaction {
+	sidecar := &BlobSidecar{}
+	for i := 0; i < blobs; i++ {
+		sidecar.Blobs = append(sidecar.Blobs, bytes.Repeat([]byte{byte(i + 1)}, BlobLength))
+		sidecar.Commitments = append(sidecar.Commitments, bytes.Repeat([]byte{byte(i + 0x10)}, KZGCommitmentLength))
+		sidecar.Proofs = append(sidecar.Proofs, bytes.Repeat([]byte{byte(i + 0x20)}, KZGProofLength))
+	}
+	tx := NewTransaction().
+		SetType(BlobTxType).
+		SetChainID(1).
+		SetNonce(1).
+		SetTo(MustAddressFromHex("0x1111111111111111111111111111111111111111")).
+		SetGasLimit(21000).
+		SetValue(big.NewInt(1)).
+		SetMaxFeePerGas(big.NewInt(100)).
+		SetMaxPriorityFeePerGas(big.NewInt(10)).
+		SetMaxFeePerBlobGas(big.NewInt(1)).
+		SetBlobVersionedHashes(sidecar.VersionedHashes()).
+		SetSignature(SignatureFromVRS(big.NewInt(1), big.NewInt(2), big.NewInt(3)))
+	tx.Sidecar = sidecar
+	return tx
+}
+
+func TestBlobSidecar_VersionedHashes(t *testing.T) {
+	sidecar := &BlobSidecar{Commitments: [][]byte{make([]byte, KZGCommitmentLength)}}
+	// sha256 of 48 zero bytes with the first byte replaced by the version.
+	assert.Equal(t, []Hash{
+		MustHashFromHex("0x01b0761f87b081d5cf10757ccc89f12be355c70e2e29df288b65b30710dcbcd1", PadNone),
+	}, sidecar.VersionedHashes())
+}
+
+func TestTransaction_NetworkWrapper(t *testing.T) {
+	tx := testBlobTransaction(2)
+
+	wrapper, err := tx.EncodeNetworkWrapper()
+	require.NoError(t, err)
+	assert.Equal(t, byte(BlobTxType), wrapper[0])
+
+	decoded := new(Transaction)
+	n, err := decoded.DecodeNetworkWrapper(wrapper)
+	require.NoError(t, err)
+	assert.Equal(t, len(wrapper), n)
+	assert.Equal(t, tx, decoded)
+
+	// The transaction hash does not depend on the sidecar.
+	raw, err := tx.Raw()
+	require.NoError(t, err)
+	rawDecoded, err := decoded.Raw()
+	require.NoError(t, err)
+	assert.Equal(t, raw, rawDecoded)
+
+	// The copy includes the sidecar.
+	cpy := tx.Copy()
+	assert.Equal(t, tx.Sidecar, cpy.Sidecar)
+	cpy.Sidecar.Blobs[0][0] = 0xff
+	assert.NotEqual(t, tx.Sidecar.Blobs[0][0], cpy.Sidecar.Blobs[0][0])
+}
+
+func TestTransaction_NetworkWrapper_Invalid(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(tx *Transaction)
+	}{
+		{name: "not a blob transaction", modify: func(tx *Transaction) { tx.Type = DynamicFeeTxType }},
+		{name: "missing sidecar", modify: func(tx *Transaction) { tx.Sidecar = nil }},
+		{name: "missing blob", modify: func(tx *Transaction) { tx.Sidecar.Blobs = tx.Sidecar.Blobs[:1] }},
+		{name: "invalid blob length", modify: func(tx *Transaction) { tx.Sidecar.Blobs[0] = tx.Sidecar.Blobs[0][:1] }},
+		{name: "invalid proof length", modify: func(tx *Transaction) { tx.Sidecar.Proofs[1] = nil }},
+		{name: "commitment mismatch", modify: func(tx *Transaction) { tx.Sidecar.Commitments[0][0] ^= 0xff }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tx := testBlobTransaction(2)
+			tt.modify(tx)
+			_, err := tx.EncodeNetworkWrapper()
+			assert.Error(t, err)
+		})
+	}
+
+	// Plain blob transaction encoding is not a network wrapper.
+	raw, err := testBlobTransaction(1).Raw()
+	require.NoError(t, err)
+	_, err = new(Transaction).DecodeNetworkWrapper(raw)
+	assert.Error(t, err)
+
+	_, err = new(Transaction).DecodeNetworkWrapper(nil)
+	assert.Error(t, err)
+}
@@ -0,0 +1,363 @@
+package types
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/defiweb/go-rlp"
+
+	"github.com/defiweb/go-eth/hexutil"
+)
+
+var (
+	// EmptyRootHash is the root hash of an empty Merkle-Patricia trie.
+	EmptyRootHash = MustHashFromHex("0x56e81f171bcc55a6ff8345e692c0f86e5b48e01b996cadc001622fb5e363b421", PadNone)
+
+	// EmptyCodeHash is the Keccak256 hash of an empty contract code.
+	EmptyCodeHash = MustHashFromHex("0xc5d2460186f7233c927e7db2dcc703c0e500b653ca82273b7bfad8045d85a470", PadNone)
+)
+
+// AccountProof represents the result of the eth_getProof call.
+type AccountProof struct {
+	Address      Address        // Address is the address of the account.
+	AccountProof []Bytes        // AccountProof is the list of trie nodes from the state root to the account.
+	Balance      *big.Int       // Balance is the balance of the account.
+	CodeHash     Hash           // CodeHash is the hash of the account code.
+	Nonce        uint64         // Nonce is the nonce of the account.
+	StorageHash  Hash           // StorageHash is the root hash of the account storage trie.
+	StorageProof []StorageProof // StorageProof is the list of proofs for the requested storage keys.
+}
+
+// StorageProof represents a proof of a single storage slot returned by
+// the eth_getProof call.
+type StorageProof struct {
+	Key   Hash     // Key is the storage slot.
+	Value *big.Int // Value is the value of the storage slot.
+	Proof []Bytes  // Proof is the list of trie nodes from the storage root to the slot.
+}
+
+func (p AccountProof) MarshalJSON() ([]byte, error) {
+	return json.Marshal(&jsonAccountProof{
+		Address:      p.Address,
+		AccountProof: p.AccountProof,
+		Balance:      NumberFromBigInt(p.Balance),
+		CodeHash:     p.CodeHash,
+		Nonce:        NumberFromUint64(p.Nonce),
+		StorageHash:  p.StorageHash,
+		StorageProof: p.StorageProof,
+	})
+}
+
+func (p *AccountProof) UnmarshalJSON(data []byte) error {
+	proof := &jsonAccountProof{}
+	if err := json.Unmarshal(data, proof); err != nil {
+		return err
+	}
+	p.Address = proof.Address
+	p.AccountProof = proof.AccountProof
+	p.Balance = proof.Balance.Big()
+	p.CodeHash = proof.CodeHash
+	p.Nonce = proof.Nonce.Big().Uint64()
+	p.StorageHash = proof.StorageHash
+	p.StorageProof = proof.StorageProof
+	return nil
+}
+
+func (p StorageProof) MarshalJSON() ([]byte, error) {
+	return json.Marshal(&jsonStorageProof{
+		Key:   p.Key.String(),
+		Value: NumberFromBigInt(p.Value),
+		Proof: p.Proof,
+	})
+}
+
+func (p *StorageProof) UnmarshalJSON(data []byte) error {
+	proof := &jsonStorageProof{}
+	if err := json.Unmarshal(data, proof); err != nil {
+		return err
+	}
+	// Some nodes return the key in the same form as it was requested,
+	// so it may be a number shorter than 32 bytes, e.g. "0x1".
+	key, err := hexutil.HexToBigInt(proof.Key)
+	if err != nil {
+		return err
+	}
+	if key.Sign() < 0 || key.BitLen() > HashLength*8 {
+		return fmt.Errorf("invalid storage key %s", proof.Key)
+	}
+	p.Key = bigToHash(key)
+	p.Value = proof.Value.Big()
+	p.Proof = proof.Proof
+	return nil
+}
+
+type jsonAccountProof struct {
+	Address      Address        `json:"address"`
+	AccountProof []Bytes        `json:"accountProof"`
+	Balance      Number         `json:"balance"`
+	CodeHash     Hash           `json:"codeHash"`
+	Nonce        Number         `json:"nonce"`
+	StorageHash  Hash           `json:"storageHash"`
+	StorageProof []StorageProof `json:"storageProof"`
+}
+
+type jsonStorageProof struct {
+	Key   string  `json:"key"`
+	Value Number  `json:"value"`
+	Proof []Bytes `json:"proof"`
+}
+
+// VerifyAccountProof verifies the account proof, as returned by the
+// eth_getProof call, against the given state root. It confirms that the
+// nonce, balance, storage hash and code hash of the account match the
+// values in the state trie. Storage proofs included in the account proof
+// are verified against the account storage hash.
+//
+// If the proof shows that the account does not exist, the claimed account
+// must be empty.
+func VerifyAccountProof(stateRoot Hash, proof *AccountProof) error {
+	value, err := verifyTrieProof(stateRoot, keccak256(proof.Address.Bytes()), proof.AccountProof)
+	if err != nil {
+		return fmt.Errorf("account proof: %w", err)
+	}
+	if value == nil {
+		if !isEmptyAccount(proof) {
+			return errors.New("account proof: account does not exist")
+		}
+	} else {
+		nonce, balance, storageHash, codeHash, err := decodeAccount(value)
+		if err != nil {
+			return fmt.Errorf("account proof: %w", err)
+		}
+		switch {
+		case nonce != proof.Nonce:
+			return errors.New("account proof: nonce mismatch")
+		case balance.Cmp(bigOrZero(proof.Balance)) != 0:
+			return errors.New("account proof: balance mismatch")
+		case storageHash != proof.StorageHash:
+			return errors.New("account proof: storage hash mismatch")
+		case codeHash != proof.CodeHash:
+			return errors.New("account proof: code hash mismatch")
+		}
+	}
+	storageRoot := proof.StorageHash
+	if storageRoot.IsZero() {
+		storageRoot = EmptyRootHash
+	}
+	for i := range proof.StorageProof {
+		if err := VerifyStorageProof(storageRoot, &proof.StorageProof[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// VerifyStorageProof verifies the storage proof, as returned by the
+// eth_getProof call, against the given storage root. It confirms that the
+// value of the storage slot matches the value in the storage trie. Slots
+// that are not present in the trie must have a zero value.
+func VerifyStorageProof(storageRoot Hash, proof *StorageProof) error {
+	value, err := verifyTrieProof(storageRoot, keccak256(proof.Key.Bytes()), proof.Proof)
+	if err != nil {
+		return fmt.Errorf("storage proof for slot %s: %w", proof.Key, err)
+	}
+	slot := new(big.Int)
+	if value != nil {
+		r, _, err := rlp.Decode(value)
+		if err != nil {
+			return fmt.Errorf("storage proof for slot %s: %w", proof.Key, err)
+		}
+		b, err := r.GetBytes()
+		if err != nil {
+			return fmt.Errorf("storage proof for slot %s: %w", proof.Key, err)
+		}
+		slot.SetBytes(b)
+	}
+	if slot.Cmp(bigOrZero(proof.Value)) != 0 {
+		return fmt.Errorf("storage proof for slot %s: value mismatch", proof.Key)
+	}
+	return nil
+}
+
+// verifyTrieProof walks the Merkle-Patricia trie proof from the root to the
+// given key and returns the value stored under the key. If the proof shows
+// that the key is not present in the trie, nil is returned.
+func verifyTrieProof(root Hash, key Hash, proof []Bytes) ([]byte, error) {
+	if root == EmptyRootHash && len(proof) == 0 {
+		return nil, nil
+	}
+	var (
+		path   = keyToNibbles(key.Bytes())
+		ref    = root.Bytes() // Reference to the next node, hash or inline node.
+		inline = false        // Whether ref is an inline node.
+		idx    = 0            // Index of the next proof node.
+		err    error
+	)
+	for {
+		node := ref
+		if !inline {
+			if len(ref) != HashLength {
+				return nil, errors.New("invalid node reference")
+			}
+			if idx >= len(proof) {
+				return nil, errors.New("proof is too short")
+			}
+			node = proof[idx]
+			idx++
+			if keccak256(node) != MustHashFromBytes(ref, PadNone) {
+				return nil, fmt.Errorf("invalid hash of proof node %d", idx-1)
+			}
+		}
+		var items []*rlp.RLP
+		items, err = decodeTrieNode(node)
+		if err != nil {
+			return nil, fmt.Errorf("invalid proof node %d: %w", idx-1, err)
+		}
+		switch len(items) {
+		case 17: // Branch node.
+			if len(path) == 0 {
+				return nil, errors.New("unexpected branch node at the end of the key")
+			}
+			ref, inline, err = nodeReference(items[path[0]])
+			if err != nil {
+				return nil, err
+			}
+			path = path[1:]
+			if len(ref) == 0 {
+				return nil, checkProofEnd(idx, proof)
+			}
+		case 2: // Extension or leaf node.
+			b, err := items[0].GetBytes()
+			if err != nil {
+				return nil, err
+			}
+			nodePath, leaf, err := decodeCompactPath(b)
+			if err != nil {
+				return nil, err
+			}
+			if leaf {
+				if !bytes.Equal(nodePath, path) {
+					return nil, checkProofEnd(idx, proof)
+				}
+				if err := checkProofEnd(idx, proof); err != nil {
+					return nil, err
+				}
+				return items[1].GetBytes()
+			}
+			if !bytes.HasPrefix(path, nodePath) {
+				return nil, checkProofEnd(idx, proof)
+			}
+			path = path[len(nodePath):]
+			if ref, inline, err = nodeReference(items[1]); err != nil {
+				return nil, err
+			}
+		default:
+			return nil, fmt.Errorf("invalid proof node %d: unexpected number of items", idx-1)
+		}
+	}
+}
+
+// decodeTrieNode decodes the trie node into a list of items.
+func decodeTrieNode(node []byte) ([]*rlp.RLP, error) {
+	r, n, err := rlp.Decode(node)
+	if err != nil {
+		return nil, err
+	}
+	if n != len(node) {
+		return nil, errors.New("unexpected trailing data")
+	}
+	return r.GetList()
+}
+
+// nodeReference returns the reference to a child node. The reference is
+// either a hash of the node, an inline node or an empty slice if there is
+// no child node.
+func nodeReference(item *rlp.RLP) (ref []byte, inline bool, err error) {
+	if item.IsList() {
+		return item.Bytes(), true, nil
+	}
+	ref, err = item.GetBytes()
+	return ref, false, err
+}
+
+// checkProofEnd verifies that all proof nodes were used.
+func checkProofEnd(idx int, proof []Bytes) error {
+	if idx != len(proof) {
+		return errors.New("proof contains unused nodes")
+	}
+	return nil
+}
+
+// decodeCompactPath decodes the hex-prefix encoded path of a leaf or an
+// extension node.
+func decodeCompactPath(b []byte) (path []byte, leaf bool, err error) {
+	if len(b) == 0 {
+		return nil, false, errors.New("empty node path")
+	}
+	flag := b[0] >> 4
+	if flag > 3 {
+		return nil, false, errors.New("invalid node path prefix")
+	}
+	nibbles := keyToNibbles(b)
+	if flag&1 == 1 {
+		path = nibbles[1:]
+	} else {
+		path = nibbles[2:]
+	}
+	return path, flag&2 == 2, nil
+}
+
+// keyToNibbles splits the key into 4-bit nibbles.
+func keyToNibbles(key []byte) []byte {
+	nibbles := make([]byte, len(key)*2)
+	for i, b := range key {
+		nibbles[i*2] = b >> 4
+		nibbles[i*2+1] = b & 0x0f
+	}
+	return nibbles
+}
+
+// decodeAccount decodes the RLP encoded account from the state trie.
+func decodeAccount(value []byte) (nonce uint64, balance *big.Int, storageHash, codeHash Hash, err error) {
+	items, err := decodeTrieNode(value)
+	if err != nil {
+		return 0, nil, Hash{}, Hash{}, err
+	}
+	if len(items) != 4 {
+		return 0, nil, Hash{}, Hash{}, errors.New("invalid account encoding")
+	}
+	if nonce, err = items[0].GetUint(); err != nil {
+		return 0, nil, Hash{}, Hash{}, err
+	}
+	if balance, err = items[1].GetBigInt(); err != nil {
+		return 0, nil, Hash{}, Hash{}, err
+	}
+	if _, err = storageHash.DecodeRLP(items[2].Bytes()); err != nil {
+		return 0, nil, Hash{}, Hash{}, err
+	}
+	if _, err = codeHash.DecodeRLP(items[3].Bytes()); err != nil {
+		return 0, nil, Hash{}, Hash{}, err
+	}
+	return nonce, balance, storageHash, codeHash, nil
+}
+
+// isEmptyAccount returns true if the proof claims an empty account. Nodes
+// return either zero hashes or hashes of empty values for storage and code
+// of non-existent accounts.
+func isEmptyAccount(proof *AccountProof) bool {
+	return proof.Nonce == 0 &&
+		bigOrZero(proof.Balance).Sign() == 0 &&
+		(proof.StorageHash.IsZero() || proof.StorageHash == EmptyRootHash) &&
+		(proof.CodeHash.IsZero() || proof.CodeHash == EmptyCodeHash)
+}
+
+// bigOrZero returns x or zero if x is nil.
+func bigOrZero(x *big.Int) *big.Int {
+	if x == nil {
+		return new(big.Int)
+	}
+	return x
+}
@@ -0,0 +1,266 @@
+package types
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/defiweb/go-rlp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Helpers for building Merkle-Patricia trie nodes in tests.
+
+func testCompactPath(nibbles []byte, leaf bool) []byte {
+	flag := byte(0)
+	if leaf {
+		flag = 2
+	}
+	var b []byte
+	if len(nibbles)%2 == 1 {
+		b = append(b, (flag+1)<<4|nibbles[0])
+		nibbles = nibbles[1:]
+	} else {
+		b = append(b, flag<<4)
+	}
+	for i := 0; i < len(nibbles); i += 2 {
+		b = append(b, nibbles[i]<<4|nibbles[i+1])
+	}
+	return b
+}
+
+func testLeafNode(t *testing.T, nibbles []byte, value []byte) []byte {
+	b, err := rlp.NewList(rlp.NewBytes(testCompactPath(nibbles, true)), rlp.NewBytes(value)).EncodeRLP()
+	require.NoError(t, err)
+	return b
+}
+
+func testExtensionNode(t *testing.T, nibbles []byte, child []byte) []byte {
+	b, err := rlp.NewList(rlp.NewBytes(testCompactPath(nibbles, false)), rlp.NewBytes(keccak256(child).Bytes())).EncodeRLP()
+	require.NoError(t, err)
+	return b
+}
+
+func testBranchNode(t *testing.T, children map[byte][]byte) []byte {
+	l := rlp.NewList()
+	for i := byte(0); i < 16; i++ {
+		if c, ok := children[i]; ok {
+			l.Append(rlp.NewBytes(keccak256(c).Bytes()))
+		} else {
+			l.Append(rlp.NewBytes(nil))
+		}
+	}
+	l.Append(rlp.NewBytes(nil))
+	b, err := l.EncodeRLP()
+	require.NoError(t, err)
+	return b
+}
+
+func testStorageValue(t *testing.T, v int64) []byte {
+	b, err := rlp.NewBigInt(big.NewInt(v)).EncodeRLP()
+	require.NoError(t, err)
+	return b
+}
+
+func TestVerifyStorageProof(t *testing.T) {
+	// Find two slots whose hashed keys share the first nibble, so the trie
+	// contains an extension node, a branch node and two leaf nodes.
+	var slotA, slotB Hash
+	var keyA, keyB []byte
+	for i := int64(1); keyB == nil; i++ {
+		for j := int64(0); j < i; j++ {
+			a := bigToHash(big.NewInt(j))
+			b := bigToHash(big.NewInt(i))
+			ka := keyToNibbles(keccak256(a.Bytes()).Bytes())
+			kb := keyToNibbles(keccak256(b.Bytes()).Bytes())
+			if ka[0] == kb[0] && ka[1] != kb[1] {
+				slotA, slotB, keyA, keyB = a, b, ka, kb
+				break
+			}
+		}
+	}
+
+	leafA := testLeafNode(t, keyA[2:], testStorageValue(t, 0x1234))
+	leafB := testLeafNode(t, keyB[2:], testStorageValue(t, 0x5678))
+	branch := testBranchNode(t, map[byte][]byte{keyA[1]: leafA, keyB[1]: leafB})
+	ext := testExtensionNode(t, keyA[:1], branch)
+	root := keccak256(ext)
+
+	// Find a slot that is not in the trie.
+	var slotC Hash
+	for i := int64(0); ; i++ {
+		c := bigToHash(big.NewInt(1000 + i))
+		if keyToNibbles(keccak256(c.Bytes()).Bytes())[0] != keyA[0] {
+			slotC = c
+			break
+		}
+	}
+
+	tests := []struct {
+		name    string
+		proof   StorageProof
+		wantErr bool
+	}{
+		{
+			name:  "slot A",
+			proof: StorageProof{Key: slotA, Value: big.NewInt(0x1234), Proof: []Bytes{ext, branch, leafA}},
+		},
+		{
+			name:  "slot B",
+			proof: StorageProof{Key: slotB, Value: big.NewInt(0x5678), Proof: []Bytes{ext, branch, leafB}},
+		},
+		{
+			name:  "absent slot",
+			proof: StorageProof{Key: slotC, Value: big.NewInt(0), Proof: []Bytes{ext}},
+		},
+		{
+			name:    "absent slot with non-zero value",
+			proof:   StorageProof{Key: slotC, Value: big.NewInt(1), Proof: []Bytes{ext}},
+			wantErr: true,
+		},
+		{
+			name:    "value mismatch",
+			proof:   StorageProof{Key: slotA, Value: big.NewInt(0x5678), Proof: []Bytes{ext, branch, leafA}},
+			wantErr: true,
+		},
+		{
+			name:    "wrong leaf",
+			proof:   StorageProof{Key: slotA, Value: big.NewInt(0x5678), Proof: []Bytes{ext, branch, leafB}},
+			wantErr: true,
+		},
+		{
+			name:    "missing node",
+			proof:   StorageProof{Key: slotA, Value: big.NewInt(0x1234), Proof: []Bytes{ext, branch}},
+			wantErr: true,
+		},
+		{
+			name:    "unused node",
+			proof:   StorageProof{Key: slotA, Value: big.NewInt(0x1234), Proof: []Bytes{ext, branch, leafA, leafB}},
+			wantErr: true,
+		},
+		{
+			name: "tampered leaf",
+			proof: StorageProof{Key: slotA, Value: big.NewInt(0x1235), Proof: []Bytes{
+				ext, branch, testLeafNode(t, keyA[2:], testStorageValue(t, 0x1235)),
+			}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := VerifyStorageProof(root, &tt.proof)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestVerifyAccountProof(t *testing.T) {
+	addr := MustAddressFromHex("0x1111111111111111111111111111111111111111")
+	codeHash := keccak256([]byte{0x60, 0x00})
+
+	// Storage trie with a single slot.
+	slot := bigToHash(big.NewInt(0))
+	storageLeaf := testLeafNode(t, keyToNibbles(keccak256(slot.Bytes()).Bytes()), testStorageValue(t, 42))
+	storageRoot := keccak256(storageLeaf)
+
+	// State trie with a single account.
+	account, err := rlp.NewList(
+		rlp.NewUint(7),
+		rlp.NewBigInt(big.NewInt(1e18)),
+		rlp.NewBytes(storageRoot.Bytes()),
+		rlp.NewBytes(codeHash.Bytes()),
+	).EncodeRLP()
+	require.NoError(t, err)
+	accountLeaf := testLeafNode(t, keyToNibbles(keccak256(addr.Bytes()).Bytes()), account)
+	stateRoot := keccak256(accountLeaf)
+
+	valid := func() *AccountProof {
+		return &AccountProof{
+			Address:      addr,
+			AccountProof: []Bytes{accountLeaf},
+			Balance:      big.NewInt(1e18),
+			CodeHash:     codeHash,
+			Nonce:        7,
+			StorageHash:  storageRoot,
+			StorageProof: []StorageProof{{Key: slot, Value: big.NewInt(42), Proof: []Bytes{storageLeaf}}},
+		}
+	}
+
+	t.Run("valid", func(t *testing.T) {
+		assert.NoError(t, VerifyAccountProof(stateRoot, valid()))
+	})
+	t.Run("wrong state root", func(t *testing.T) {
+		assert.Error(t, VerifyAccountProof(storageRoot, valid()))
+	})
+	t.Run("nonce mismatch", func(t *testing.T) {
+		p := valid()
+		p.Nonce = 8
+		assert.Error(t, VerifyAccountProof(stateRoot, p))
+	})
+	t.Run("balance mismatch", func(t *testing.T) {
+		p := valid()
+		p.Balance = big.NewInt(2e18)
+		assert.Error(t, VerifyAccountProof(stateRoot, p))
+	})
+	t.Run("code hash mismatch", func(t *testing.T) {
+		p := valid()
+		p.CodeHash = EmptyCodeHash
+		assert.Error(t, VerifyAccountProof(stateRoot, p))
+	})
+	t.Run("storage value mismatch", func(t *testing.T) {
+		p := valid()
+		p.StorageProof[0].Value = big.NewInt(43)
+		assert.Error(t, VerifyAccountProof(stateRoot, p))
+	})
+	t.Run("absent account", func(t *testing.T) {
+		p := &AccountProof{
+			Address:      MustAddressFromHex("0x2222222222222222222222222222222222222222"),
+			AccountProof: []Bytes{accountLeaf},
+			Balance:      big.NewInt(0),
+			CodeHash:     EmptyCodeHash,
+			StorageHash:  EmptyRootHash,
+		}
+		assert.NoError(t, VerifyAccountProof(stateRoot, p))
+		p.Balance = big.NewInt(1)
+		assert.Error(t, VerifyAccountProof(stateRoot, p))
+	})
+	t.Run("empty trie", func(t *testing.T) {
+		p := &AccountProof{Address: addr, Balance: big.NewInt(0)}
+		assert.NoError(t, VerifyAccountProof(EmptyRootHash, p))
+	})
+}
+
+func TestAccountProof_JSON(t *testing.T) {
+	const input = `{
+		"address": "0x1111111111111111111111111111111111111111",
+		"accountProof": ["0x01", "0x02"],
+		"balance": "0x64",
+		"codeHash": "0xc5d2460186f7233c927e7db2dcc703c0e500b653ca82273b7bfad8045d85a470",
+		"nonce": "0x7",
+		"storageHash": "0x56e81f171bcc55a6ff8345e692c0f86e5b48e01b996cadc001622fb5e363b421",
+		"storageProof": [{"key": "0x1", "value": "0x2a", "proof": ["0x03"]}]
+	}`
+	var p AccountProof
+	require.NoError(t, json.Unmarshal([]byte(input), &p))
+	assert.Equal(t, MustAddressFromHex("0x1111111111111111111111111111111111111111"), p.Address)
+	assert.Equal(t, []Bytes{{0x01}, {0x02}}, p.AccountProof)
+	assert.Equal(t, big.NewInt(100), p.Balance)
+	assert.Equal(t, EmptyCodeHash, p.CodeHash)
+	assert.Equal(t, uint64(7), p.Nonce)
+	assert.Equal(t, EmptyRootHash, p.StorageHash)
+	require.Len(t, p.StorageProof, 1)
+	assert.Equal(t, MustHashFromHex("0x01", PadLeft), p.StorageProof[0].Key)
+	assert.Equal(t, big.NewInt(42), p.StorageProof[0].Value)
+	assert.Equal(t, []Bytes{{0x03}}, p.StorageProof[0].Proof)
+
+	j, err := json.Marshal(p)
+	require.NoError(t, err)
+	var p2 AccountProof
+	require.NoError(t, json.Unmarshal(j, &p2))
+	assert.Equal(t, p, p2)
+}
@@ -3,8 +3,10 @@ package types
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"math/big"
+	"strconv"
 	"time"
 
 	"github.com/defiweb/go-rlp"
@@ -14,7 +16,7 @@ import (
 type Call struct {
 	From     *Address // From is the sender address.
 	To       *Address // To is the recipient address. nil means contract creation.
-	GasLimit *uint64  // GasLimit is the gas limit, if 0, there is no limit.
+	GasLimit *uint64  // GasLimit is the gas limit, if nil or 0, there is no limit.
 	GasPrice *big.Int // GasPrice is the gas price in wei per gas unit.
 	Value    *big.Int // Value is the amount of wei to send.
 	Input    []byte   // Input is the input data.
@@ -132,6 +134,14 @@ func (c Call) Copy() *Call {
 	}
 }
 
+// MarshalJSON implements the json.Marshaler interface.
+//
+// The output is deterministic. Fields are always emitted in the following
+// order: from, to, gas, gasPrice, maxFeePerGas, maxPriorityFeePerGas, value,
+// data, accessList. Unset fields are omitted.
+//
+// A zero gas limit and a zero value are treated as unset and omitted from the
+// output, because some nodes reject calls with "gas" or "value" set to "0x0".
 func (c Call) MarshalJSON() ([]byte, error) {
 	call := &jsonCall{
 		From:       c.From,
@@ -139,7 +149,7 @@ func (c Call) MarshalJSON() ([]byte, error) {
 		Data:       c.Input,
 		AccessList: c.AccessList,
 	}
-	if c.GasLimit != nil {
+	if c.GasLimit != nil && *c.GasLimit != 0 {
 		call.GasLimit = NumberFromUint64Ptr(*c.GasLimit)
 	}
 	if c.GasPrice != nil {
@@ -151,13 +161,32 @@ func (c Call) MarshalJSON() ([]byte, error) {
 	if c.MaxPriorityFeePerGas != nil {
 		call.MaxPriorityFeePerGas = NumberFromBigIntPtr(c.MaxPriorityFeePerGas)
 	}
-	if c.Value != nil {
+	if c.Value != nil && c.Value.Sign() != 0 {
 		value := NumberFromBigInt(c.Value)
 		call.Value = &value
 	}
 	return json.Marshal(call)
 }
 
+// CacheKey returns a key that identifies the call executed at the given
+// block. It is the Keccak256 hash of the canonical JSON representation of
+// the call, as returned by MarshalJSON, followed by the block number or tag.
+//
+// Two calls that produce the same eth_call request have the same key. Note
+// that results of calls made at tags such as "latest" may change between
+// blocks, so keys based on tags should be used with care.
+func (c Call) CacheKey(block BlockNumber) (Hash, error) {
+	j, err := c.MarshalJSON()
+	if err != nil {
+		return ZeroHash, err
+	}
+	b, err := block.MarshalText()
+	if err != nil {
+		return ZeroHash, err
+	}
+	return keccak256(j, []byte{0}, b), nil
+}
+
 func (c *Call) UnmarshalJSON(data []byte) error {
 	call := &jsonCall{}
 	if err := json.Unmarshal(data, call); err != nil {
@@ -206,8 +235,83 @@ const (
 	LegacyTxType TransactionType = iota
 	AccessListTxType
 	DynamicFeeTxType
+	BlobTxType
 )
 
+// String returns the name of the transaction type.
+func (t TransactionType) String() string {
+	switch t {
+	case LegacyTxType:
+		return "legacy"
+	case AccessListTxType:
+		return "access list"
+	case DynamicFeeTxType:
+		return "dynamic fee"
+	case BlobTxType:
+		return "blob"
+	default:
+		return fmt.Sprintf("unknown (%d)", uint64(t))
+	}
+}
+
+// ErrUnsupportedTxType is returned when a transaction of an unknown type is
+// encoded, decoded, validated or signed. All returned errors are of the
+// *UnsupportedTxTypeError type, which holds the transaction type.
+var ErrUnsupportedTxType = errors.New("unsupported transaction type")
+
+// UnsupportedTxTypeError is returned when a transaction of an unknown type
+// is encoded, decoded, validated or signed. It matches ErrUnsupportedTxType when used
+// with errors.Is.
+type UnsupportedTxTypeError struct {
+	Type TransactionType
+}
+
+// Error implements the error interface.
+func (e *UnsupportedTxTypeError) Error() string {
+	return fmt.Sprintf("%s: %d", ErrUnsupportedTxType, uint64(e.Type))
+}
+
+// Is returns true if the target is ErrUnsupportedTxType.
+func (e *UnsupportedTxTypeError) Is(target error) bool {
+	return target == ErrUnsupportedTxType
+}
+
+// DefaultChainID is the chain ID used to encode and sign typed transactions
+// that do not have the ChainID field set.
+//
+// Legacy transactions without a chain ID are encoded and signed without
+// EIP-155 replay protection, so this value is not used for them.
+//
+// It is recommended to always set the chain ID explicitly, for example using
+// the rpc.WithChainID client option, instead of relying on this value.
+const DefaultChainID uint64 = 1
+
+// FeeData holds the gas price fields of a transaction.
+//
+// It contains either the legacy GasPrice field or the EIP-1559
+// MaxFeePerGas and MaxPriorityFeePerGas fields.
+type FeeData struct {
+	GasPrice *big.Int // GasPrice is the gas price in wei per gas unit.
+
+	// EIP-1559 fields:
+	MaxFeePerGas         *big.Int // MaxFeePerGas is the maximum fee per gas the sender is willing to pay.
+	MaxPriorityFeePerGas *big.Int // MaxPriorityFeePerGas is the maximum priority fee per gas the sender is willing to pay.
+}
+
+// IsDynamicFee returns true if the fee data contains EIP-1559 fields.
+func (f FeeData) IsDynamicFee() bool {
+	return f.MaxFeePerGas != nil || f.MaxPriorityFeePerGas != nil
+}
+
+// FeeCap returns the maximum fee per gas for an EIP-1559 transaction,
+// calculated as 2 * baseFee + tip. Doubling the base fee keeps the
+// transaction valid for at least six consecutive full blocks, because the
+// base fee may increase by at most 12.5% per block.
+func FeeCap(baseFee, tip *big.Int) *big.Int {
+	maxFee := new(big.Int).Mul(baseFee, big.NewInt(2))
+	return maxFee.Add(maxFee, tip)
+}
+
 // Transaction represents a transaction.
 type Transaction struct {
 	Call
@@ -218,7 +322,20 @@ type Transaction struct {
 	Signature *Signature      // Signature of the transaction.
 
 	// EIP-2930 fields:
+	//
+	// If ChainID is nil, typed transactions use DefaultChainID, and legacy
+	// transactions are signed without EIP-155 replay protection. Legacy
+	// transactions with chain ID 0 are also signed without EIP-155.
 	ChainID *uint64 // ChainID is the chain ID of the transaction.
+
+	// EIP-4844 fields:
+	MaxFeePerBlobGas    *big.Int // MaxFeePerBlobGas is the maximum fee per blob gas the sender is willing to pay.
+	BlobVersionedHashes []Hash   // BlobVersionedHashes is the list of versioned hashes of the blobs.
+
+	// Sidecar contains the blobs of a blob transaction. It is not a part of
+	// the JSON and RLP representations of the transaction, and it is only
+	// used by EncodeNetworkWrapper and DecodeNetworkWrapper.
+	Sidecar *BlobSidecar
 }
 
 func NewTransaction() *Transaction {
@@ -290,6 +407,133 @@ func (t *Transaction) SetChainID(chainID uint64) *Transaction {
 	return t
 }
 
+func (t *Transaction) SetMaxFeePerBlobGas(maxFeePerBlobGas *big.Int) *Transaction {
+	t.MaxFeePerBlobGas = maxFeePerBlobGas
+	return t
+}
+
+func (t *Transaction) SetBlobVersionedHashes(hashes []Hash) *Transaction {
+	t.BlobVersionedHashes = hashes
+	return t
+}
+
+// SetFeeData sets the gas price fields from the given fee data and infers
+// the transaction type.
+//
+// If fee data contains EIP-1559 fields, the type is set to DynamicFeeTxType
+// and the GasPrice field is cleared. Transactions of BlobTxType keep their
+// type, because they already use EIP-1559 fees. Otherwise, the GasPrice field is set,
+// EIP-1559 fields are cleared, and the type is set to LegacyTxType, or to
+// AccessListTxType if an access list is provided.
+func (t *Transaction) SetFeeData(fd FeeData) *Transaction {
+	if fd.IsDynamicFee() {
+		t.GasPrice = nil
+		t.MaxFeePerGas = fd.MaxFeePerGas
+		t.MaxPriorityFeePerGas = fd.MaxPriorityFeePerGas
+		if t.Type != BlobTxType {
+			t.Type = DynamicFeeTxType
+		}
+		return t
+	}
+	t.GasPrice = fd.GasPrice
+	t.MaxFeePerGas = nil
+	t.MaxPriorityFeePerGas = nil
+	switch {
+	case t.AccessList != nil:
+		t.Type = AccessListTxType
+	default:
+		t.Type = LegacyTxType
+	}
+	return t
+}
+
+// InferType returns the transaction type based on the fields set on the
+// transaction. The Type field is not taken into account.
+//
+// The type is chosen as follows:
+//   - BlobTxType if MaxFeePerBlobGas or BlobVersionedHashes are set
+//   - DynamicFeeTxType if MaxFeePerGas or MaxPriorityFeePerGas are set
+//   - AccessListTxType if AccessList is set, but no EIP-1559 fee fields
+//   - LegacyTxType otherwise
+//
+// Note, that an empty but non-nil access list is considered to be set, so a
+// type-1 transaction may be created without any access list entries.
+func (t *Transaction) InferType() TransactionType {
+	switch {
+	case t.MaxFeePerBlobGas != nil || len(t.BlobVersionedHashes) > 0:
+		return BlobTxType
+	case t.MaxFeePerGas != nil || t.MaxPriorityFeePerGas != nil:
+		return DynamicFeeTxType
+	case t.AccessList != nil:
+		return AccessListTxType
+	default:
+		return LegacyTxType
+	}
+}
+
+// Validate checks whether the fields set on the transaction are consistent
+// with its type. It does not check whether the transaction is complete, as
+// missing fields may be filled in by the node or by transaction modifiers.
+//
+// The following rules are checked:
+//   - legacy and access list transactions must not set EIP-1559 fee fields
+//   - dynamic fee and blob transactions must not set GasPrice
+//   - legacy transactions must not set an access list
+//   - only blob transactions may set blob fields, and blob transactions
+//     must have at least one blob hash and a recipient
+//   - MaxPriorityFeePerGas must not exceed MaxFeePerGas
+//   - fee and value fields must not be negative
+//   - if To is nil, the transaction creates a contract and Input must
+//     contain the contract code
+func (t *Transaction) Validate() error {
+	switch t.Type {
+	case LegacyTxType, AccessListTxType:
+		if t.MaxFeePerGas != nil || t.MaxPriorityFeePerGas != nil {
+			return fmt.Errorf("invalid transaction: %s transaction must not set MaxFeePerGas or MaxPriorityFeePerGas", t.Type)
+		}
+		if t.Type == LegacyTxType && len(t.AccessList) > 0 {
+			return fmt.Errorf("invalid transaction: %s transaction must not set AccessList", t.Type)
+		}
+	case DynamicFeeTxType, BlobTxType:
+		if t.GasPrice != nil {
+			return fmt.Errorf("invalid transaction: %s transaction must not set GasPrice", t.Type)
+		}
+		if t.MaxFeePerGas != nil && t.MaxPriorityFeePerGas != nil && t.MaxPriorityFeePerGas.Cmp(t.MaxFeePerGas) > 0 {
+			return fmt.Errorf("invalid transaction: MaxPriorityFeePerGas is greater than MaxFeePerGas")
+		}
+	default:
+		return fmt.Errorf("invalid transaction: %w", &UnsupportedTxTypeError{Type: t.Type})
+	}
+	if t.Type == BlobTxType {
+		if len(t.BlobVersionedHashes) == 0 {
+			return fmt.Errorf("invalid transaction: %s transaction must have at least one blob hash", t.Type)
+		}
+		if t.To == nil {
+			return fmt.Errorf("invalid transaction: %s transaction cannot create a contract", t.Type)
+		}
+	} else if t.MaxFeePerBlobGas != nil || len(t.BlobVersionedHashes) > 0 {
+		return fmt.Errorf("invalid transaction: %s transaction must not set blob fields", t.Type)
+	}
+	for _, f := range []struct {
+		name  string
+		value *big.Int
+	}{
+		{"GasPrice", t.GasPrice},
+		{"MaxFeePerGas", t.MaxFeePerGas},
+		{"MaxPriorityFeePerGas", t.MaxPriorityFeePerGas},
+		{"MaxFeePerBlobGas", t.MaxFeePerBlobGas},
+		{"Value", t.Value},
+	} {
+		if f.value != nil && f.value.Sign() < 0 {
+			return fmt.Errorf("invalid transaction: %s must not be negative", f.name)
+		}
+	}
+	if t.To == nil && len(t.Input) == 0 {
+		return fmt.Errorf("invalid transaction: contract creation transaction must have input data")
+	}
+	return nil
+}
+
 // Raw returns the raw transaction data that could be sent to the network.
 func (t Transaction) Raw() ([]byte, error) {
 	return t.EncodeRLP()
@@ -297,9 +541,11 @@ func (t Transaction) Raw() ([]byte, error) {
 
 func (t *Transaction) Copy() *Transaction {
 	var (
-		nonce     *uint64
-		signature *Signature
-		chainID   *uint64
+		nonce            *uint64
+		signature        *Signature
+		chainID          *uint64
+		maxFeePerBlobGas *big.Int
+		blobHashes       []Hash
 	)
 	if t.Nonce != nil {
 		nonce = new(uint64)
@@ -312,17 +558,33 @@ func (t *Transaction) Copy() *Transaction {
 		chainID = new(uint64)
 		*chainID = *t.ChainID
 	}
+	if t.MaxFeePerBlobGas != nil {
+		maxFeePerBlobGas = new(big.Int).Set(t.MaxFeePerBlobGas)
+	}
+	if t.BlobVersionedHashes != nil {
+		blobHashes = make([]Hash, len(t.BlobVersionedHashes))
+		copy(blobHashes, t.BlobVersionedHashes)
+	}
 	return &Transaction{
-		Call:      *t.Call.Copy(),
-		Type:      t.Type,
-		Nonce:     nonce,
-		Signature: signature,
-		ChainID:   chainID,
+		Call:                *t.Call.Copy(),
+		Type:                t.Type,
+		Nonce:               nonce,
+		Signature:           signature,
+		ChainID:             chainID,
+		MaxFeePerBlobGas:    maxFeePerBlobGas,
+		BlobVersionedHashes: blobHashes,
+		Sidecar:             t.Sidecar.Copy(),
 	}
 }
 
 func (t Transaction) MarshalJSON() ([]byte, error) {
 	transaction := &jsonTransaction{}
+	if t.Type != LegacyTxType {
+		transaction.Type = NumberFromUint64Ptr(uint64(t.Type))
+		if t.ChainID != nil {
+			transaction.ChainID = NumberFromUint64Ptr(*t.ChainID)
+		}
+	}
 	transaction.To = t.To
 	transaction.From = t.From
 	if t.GasLimit != nil {
@@ -345,11 +607,11 @@ func (t Transaction) MarshalJSON() ([]byte, error) {
 		transaction.Value = NumberFromBigIntPtr(t.Value)
 	}
 	transaction.AccessList = t.AccessList
-	if t.Signature != nil {
-		transaction.V = NumberFromBigIntPtr(t.Signature.V)
-		transaction.R = NumberFromBigIntPtr(t.Signature.R)
-		transaction.S = NumberFromBigIntPtr(t.Signature.S)
+	if t.MaxFeePerBlobGas != nil {
+		transaction.MaxFeePerBlobGas = NumberFromBigIntPtr(t.MaxFeePerBlobGas)
 	}
+	transaction.BlobVersionedHashes = t.BlobVersionedHashes
+	transaction.setSignature(t.Type, t.Signature)
 	return json.Marshal(transaction)
 }
 
@@ -358,6 +620,13 @@ func (t *Transaction) UnmarshalJSON(data []byte) error {
 	if err := json.Unmarshal(data, transaction); err != nil {
 		return err
 	}
+	if transaction.Type != nil {
+		t.Type = TransactionType(transaction.Type.Big().Uint64())
+	}
+	if transaction.ChainID != nil {
+		chainID := transaction.ChainID.Big().Uint64()
+		t.ChainID = &chainID
+	}
 	t.To = transaction.To
 	t.From = transaction.From
 	if transaction.GasLimit != nil {
@@ -382,16 +651,18 @@ func (t *Transaction) UnmarshalJSON(data []byte) error {
 		t.Value = transaction.Value.Big()
 	}
 	t.AccessList = transaction.AccessList
-	if transaction.V != nil && transaction.R != nil && transaction.S != nil {
-		t.Signature = SignatureFromVRSPtr(transaction.V.Big(), transaction.R.Big(), transaction.S.Big())
+	if transaction.MaxFeePerBlobGas != nil {
+		t.MaxFeePerBlobGas = transaction.MaxFeePerBlobGas.Big()
 	}
+	t.BlobVersionedHashes = transaction.BlobVersionedHashes
+	t.Signature = jsonSignature(t.Type, transaction.V, transaction.YParity, transaction.R, transaction.S)
 	return nil
 }
 
 //nolint:funlen
 func (t Transaction) EncodeRLP() ([]byte, error) {
 	var (
-		chainID              = uint64(1)
+		chainID              = DefaultChainID
 		nonce                = uint64(0)
 		gasPrice             = big.NewInt(0)
 		gasLimit             = uint64(0)
@@ -400,6 +671,8 @@ func (t Transaction) EncodeRLP() ([]byte, error) {
 		to                   = ([]byte)(nil)
 		value                = big.NewInt(0)
 		accessList           = (AccessList)(nil)
+		maxFeePerBlobGas     = big.NewInt(0)
+		blobHashes           = (BlobHashList)(nil)
 		v                    = big.NewInt(0)
 		r                    = big.NewInt(0)
 		s                    = big.NewInt(0)
@@ -431,6 +704,12 @@ func (t Transaction) EncodeRLP() ([]byte, error) {
 	if t.AccessList != nil {
 		accessList = t.AccessList
 	}
+	if t.MaxFeePerBlobGas != nil {
+		maxFeePerBlobGas = t.MaxFeePerBlobGas
+	}
+	if t.BlobVersionedHashes != nil {
+		blobHashes = t.BlobVersionedHashes
+	}
 	if t.Signature != nil {
 		v = t.Signature.V
 		r = t.Signature.R
@@ -486,8 +765,29 @@ func (t Transaction) EncodeRLP() ([]byte, error) {
 			return nil, err
 		}
 		return append([]byte{byte(t.Type)}, bin...), nil
+	case BlobTxType:
+		bin, err := rlp.NewList(
+			rlp.NewUint(chainID),
+			rlp.NewUint(nonce),
+			rlp.NewBigInt(maxPriorityFeePerGas),
+			rlp.NewBigInt(maxFeePerGas),
+			rlp.NewUint(gasLimit),
+			rlp.NewBytes(to),
+			rlp.NewBigInt(value),
+			rlp.NewBytes(t.Input),
+			&accessList,
+			rlp.NewBigInt(maxFeePerBlobGas),
+			&blobHashes,
+			rlp.NewBigInt(v),
+			rlp.NewBigInt(r),
+			rlp.NewBigInt(s),
+		).EncodeRLP()
+		if err != nil {
+			return nil, err
+		}
+		return append([]byte{byte(t.Type)}, bin...), nil
 	default:
-		return nil, fmt.Errorf("unknown transaction type: %d", t.Type)
+		return nil, &UnsupportedTxTypeError{Type: t.Type}
 	}
 }
 
@@ -508,6 +808,8 @@ func (t *Transaction) DecodeRLP(data []byte) (int, error) {
 		value                = &rlp.BigIntItem{}
 		input                = &rlp.StringItem{}
 		accessList           = &AccessList{}
+		maxFeePerBlobGas     = &rlp.BigIntItem{}
+		blobHashes           = &BlobHashList{}
 		v                    = &rlp.BigIntItem{}
 		r                    = &rlp.BigIntItem{}
 		s                    = &rlp.BigIntItem{}
@@ -559,8 +861,27 @@ func (t *Transaction) DecodeRLP(data []byte) (int, error) {
 			r,
 			s,
 		)
+	case data[0] == byte(BlobTxType):
+		t.Type = BlobTxType
+		data = data[1:]
+		list = rlp.NewList(
+			chainID,
+			nonce,
+			maxPriorityFeePerGas,
+			maxFeePerGas,
+			gasLimit,
+			to,
+			value,
+			input,
+			accessList,
+			maxFeePerBlobGas,
+			blobHashes,
+			v,
+			r,
+			s,
+		)
 	default:
-		return 0, fmt.Errorf("invalid transaction type: %d", data[0])
+		return 0, &UnsupportedTxTypeError{Type: TransactionType(data[0])}
 	}
 	if _, err := rlp.DecodeTo(data, list); err != nil {
 		return 0, err
@@ -579,6 +900,10 @@ func (t *Transaction) DecodeRLP(data []byte) (int, error) {
 	if len(*accessList) > 0 {
 		t.AccessList = *accessList
 	}
+	if t.Type == BlobTxType {
+		t.MaxFeePerBlobGas = maxFeePerBlobGas.X
+		t.BlobVersionedHashes = *blobHashes
+	}
 	if v.X.Sign() != 0 || r.X.Sign() != 0 || s.X.Sign() != 0 {
 		t.Signature = &Signature{
 			V: v.X,
@@ -586,9 +911,66 @@ func (t *Transaction) DecodeRLP(data []byte) (int, error) {
 			S: s.X,
 		}
 	}
+	if t.Type == LegacyTxType {
+		// Legacy transactions do not contain the chain ID. If the transaction
+		// is signed with EIP-155 replay protection, the chain ID is derived
+		// from the V value as (V - 35) / 2.
+		t.ChainID = nil
+		if v.X.Cmp(big.NewInt(35)) >= 0 {
+			chainID := new(big.Int).Rsh(new(big.Int).Sub(v.X, big.NewInt(35)), 1)
+			if !chainID.IsUint64() {
+				return 0, fmt.Errorf("invalid chain ID derived from V value %s", v.X)
+			}
+			id := chainID.Uint64()
+			t.ChainID = &id
+		}
+	}
 	return len(data), nil
 }
 
+// DecodeRLPStrict works like DecodeRLP, but it also rejects transactions
+// with invalid or malleable signatures. It should be used when decoding
+// transactions from untrusted sources.
+//
+// Unsigned transactions are accepted. See Signature.Validate for the list of
+// checks performed on the signature. Additionally, the V value of legacy
+// transactions must be 27 or 28, or ChainID * 2 + 35 or 36 with a non-zero
+// chain ID, and the V value of typed transactions must be 0 or 1.
+func (t *Transaction) DecodeRLPStrict(data []byte) (int, error) {
+	n, err := t.DecodeRLP(data)
+	if err != nil {
+		return 0, err
+	}
+	if t.Signature == nil {
+		return n, nil
+	}
+	if err := t.Signature.Validate(); err != nil {
+		return 0, err
+	}
+	switch {
+	case t.Type == LegacyTxType && !isValidLegacyV(t.Signature.V, t.ChainID):
+		return 0, fmt.Errorf("invalid signature: invalid V value %s for legacy transaction", t.Signature.V)
+	case t.Type != LegacyTxType && t.Signature.V.Cmp(big.NewInt(1)) > 0:
+		return 0, fmt.Errorf("invalid signature: invalid V value %s for typed transaction", t.Signature.V)
+	}
+	return n, nil
+}
+
+// isValidLegacyV returns true if v is a valid V value of a legacy transaction
+// signature. Transactions without replay protection use 27 or 28, and
+// EIP-155 transactions use chainID * 2 + 35 or 36.
+func isValidLegacyV(v *big.Int, chainID *uint64) bool {
+	if v.Cmp(big.NewInt(27)) == 0 || v.Cmp(big.NewInt(28)) == 0 {
+		return true
+	}
+	if chainID == nil || *chainID == 0 {
+		return false
+	}
+	x := new(big.Int).SetUint64(*chainID)
+	x.Lsh(x, 1).Add(x, big.NewInt(35))
+	return v.Cmp(x) == 0 || v.Cmp(x.Add(x, big.NewInt(1))) == 0
+}
+
 // Hash returns the hash of the transaction (transaction ID).
 func (t Transaction) Hash(h HashFunc) (Hash, error) {
 	raw, err := t.Raw()
@@ -598,7 +980,89 @@ func (t Transaction) Hash(h HashFunc) (Hash, error) {
 	return h(raw), nil
 }
 
+// TxHash returns the hash of the transaction (transaction ID) calculated
+// using the Keccak256 hash function. It is equivalent to calling Hash with
+// crypto.Keccak256.
+func (t Transaction) TxHash() (Hash, error) {
+	return t.Hash(keccak256)
+}
+
+// AsMessage returns the transaction as a message that can be executed by an
+// EVM implementation.
+//
+// The sender is recovered from the signature using the given recoverer,
+// usually crypto.ECRecoverer. The recoverer is taken instead of a HashFunc,
+// because recovering the sender requires secp256k1, which is implemented in
+// the crypto package. If the recoverer is nil or the transaction is not
+// signed, the From field is used instead, which allows simulating unsigned
+// transactions.
+//
+// The baseFee is the base fee of the block in which the message is executed.
+// It is used to calculate the effective gas price of dynamic fee and blob
+// transactions as min(MaxFeePerGas, baseFee + MaxPriorityFeePerGas). If
+// baseFee is nil, MaxFeePerGas is used as the gas price.
+func (t Transaction) AsMessage(r TransactionRecoverer, baseFee *big.Int) (Message, error) {
+	from := t.From
+	if r != nil && t.Signature != nil {
+		addr, err := r.RecoverTransaction(&t)
+		if err != nil {
+			return Message{}, fmt.Errorf("cannot recover transaction sender: %w", err)
+		}
+		from = addr
+	}
+	if from == nil {
+		return Message{}, fmt.Errorf("unknown transaction sender")
+	}
+	msg := Message{
+		From:     *from,
+		Value:    new(big.Int),
+		Data:     append([]byte(nil), t.Input...),
+		GasPrice: new(big.Int),
+	}
+	if t.To != nil {
+		to := *t.To
+		msg.To = &to
+	}
+	if t.AccessList != nil {
+		msg.AccessList = t.AccessList.Copy()
+	}
+	if t.Nonce != nil {
+		msg.Nonce = *t.Nonce
+	}
+	if t.Value != nil {
+		msg.Value.Set(t.Value)
+	}
+	if t.GasLimit != nil {
+		msg.GasLimit = *t.GasLimit
+	}
+	switch t.Type {
+	case LegacyTxType, AccessListTxType:
+		if t.GasPrice != nil {
+			msg.GasPrice.Set(t.GasPrice)
+		}
+	case DynamicFeeTxType, BlobTxType:
+		if t.MaxFeePerGas == nil {
+			return Message{}, fmt.Errorf("missing MaxFeePerGas")
+		}
+		msg.GasPrice.Set(t.MaxFeePerGas)
+		if baseFee != nil {
+			tip := t.MaxPriorityFeePerGas
+			if tip == nil {
+				tip = new(big.Int)
+			}
+			if price := new(big.Int).Add(baseFee, tip); price.Cmp(msg.GasPrice) < 0 {
+				msg.GasPrice = price
+			}
+		}
+	default:
+		return Message{}, &UnsupportedTxTypeError{Type: t.Type}
+	}
+	return msg, nil
+}
+
 type jsonTransaction struct {
+	Type                 *Number    `json:"type,omitempty"`
+	ChainID              *Number    `json:"chainId,omitempty"`
 	From                 *Address   `json:"from,omitempty"`
 	To                   *Address   `json:"to,omitempty"`
 	GasLimit             *Number    `json:"gas,omitempty"`
@@ -609,11 +1073,33 @@ type jsonTransaction struct {
 	Nonce                *Number    `json:"nonce,omitempty"`
 	Value                *Number    `json:"value,omitempty"`
 	AccessList           AccessList `json:"accessList,omitempty"`
+	MaxFeePerBlobGas     *Number    `json:"maxFeePerBlobGas,omitempty"`
+	BlobVersionedHashes  []Hash     `json:"blobVersionedHashes,omitempty"`
 	V                    *Number    `json:"v,omitempty"`
+	YParity              *Number    `json:"yParity,omitempty"`
 	R                    *Number    `json:"r,omitempty"`
 	S                    *Number    `json:"s,omitempty"`
 }
 
+// TransactionRecoverer recovers the sender of a signed transaction. It is
+// implemented by crypto.Recoverer.
+type TransactionRecoverer interface {
+	RecoverTransaction(tx *Transaction) (*Address, error)
+}
+
+// Message is a transaction normalized for execution by an EVM
+// implementation. See Transaction.AsMessage.
+type Message struct {
+	From       Address    // From is the sender of the message.
+	To         *Address   // To is the recipient of the message, nil for contract creation.
+	Nonce      uint64     // Nonce is the nonce of the sender.
+	Value      *big.Int   // Value is the amount of wei sent with the message.
+	Data       []byte     // Data is the input data of the message.
+	GasLimit   uint64     // GasLimit is the maximum amount of gas the message can use.
+	GasPrice   *big.Int   // GasPrice is the effective gas price paid per unit of gas.
+	AccessList AccessList // AccessList is the list of pre-warmed addresses and storage keys.
+}
+
 // OnChainTransaction represents a transaction that is included in a block.
 type OnChainTransaction struct {
 	Transaction
@@ -635,6 +1121,12 @@ type jsonOnChainTransaction struct {
 
 func (t OnChainTransaction) MarshalJSON() ([]byte, error) {
 	transaction := &jsonOnChainTransaction{}
+	if t.Type != LegacyTxType {
+		transaction.Type = NumberFromUint64Ptr(uint64(t.Type))
+	}
+	if t.ChainID != nil {
+		transaction.ChainID = NumberFromUint64Ptr(*t.ChainID)
+	}
 	transaction.To = t.To
 	transaction.From = t.From
 	if t.GasLimit != nil {
@@ -657,11 +1149,11 @@ func (t OnChainTransaction) MarshalJSON() ([]byte, error) {
 		transaction.Value = NumberFromBigIntPtr(t.Value)
 	}
 	transaction.AccessList = t.AccessList
-	if t.Signature != nil {
-		transaction.V = NumberFromBigIntPtr(t.Signature.V)
-		transaction.R = NumberFromBigIntPtr(t.Signature.R)
-		transaction.S = NumberFromBigIntPtr(t.Signature.S)
+	if t.MaxFeePerBlobGas != nil {
+		transaction.MaxFeePerBlobGas = NumberFromBigIntPtr(t.MaxFeePerBlobGas)
 	}
+	transaction.BlobVersionedHashes = t.BlobVersionedHashes
+	transaction.setSignature(t.Type, t.Signature)
 	transaction.Hash = t.Hash
 	transaction.BlockHash = t.BlockHash
 	if t.BlockNumber != nil {
@@ -678,6 +1170,13 @@ func (t *OnChainTransaction) UnmarshalJSON(data []byte) error {
 	if err := json.Unmarshal(data, transaction); err != nil {
 		return err
 	}
+	if transaction.Type != nil {
+		t.Type = TransactionType(transaction.Type.Big().Uint64())
+	}
+	if transaction.ChainID != nil {
+		chainID := transaction.ChainID.Big().Uint64()
+		t.ChainID = &chainID
+	}
 	t.To = transaction.To
 	t.From = transaction.From
 	if transaction.GasLimit != nil {
@@ -702,9 +1201,11 @@ func (t *OnChainTransaction) UnmarshalJSON(data []byte) error {
 		t.Value = transaction.Value.Big()
 	}
 	t.AccessList = transaction.AccessList
-	if transaction.V != nil && transaction.R != nil && transaction.S != nil {
-		t.Signature = SignatureFromVRSPtr(transaction.V.Big(), transaction.R.Big(), transaction.S.Big())
+	if transaction.MaxFeePerBlobGas != nil {
+		t.MaxFeePerBlobGas = transaction.MaxFeePerBlobGas.Big()
 	}
+	t.BlobVersionedHashes = transaction.BlobVersionedHashes
+	t.Signature = jsonSignature(t.Type, transaction.V, transaction.YParity, transaction.R, transaction.S)
 	t.Hash = transaction.Hash
 	t.BlockHash = transaction.BlockHash
 	if transaction.BlockNumber != nil {
@@ -717,6 +1218,36 @@ func (t *OnChainTransaction) UnmarshalJSON(data []byte) error {
 	return nil
 }
 
+// setSignature sets the signature fields of the JSON-RPC transaction. For
+// typed transactions, the yParity field is set along with the v field.
+func (t *jsonTransaction) setSignature(typ TransactionType, sig *Signature) {
+	if sig == nil {
+		return
+	}
+	t.V = NumberFromBigIntPtr(sig.V)
+	t.R = NumberFromBigIntPtr(sig.R)
+	t.S = NumberFromBigIntPtr(sig.S)
+	if typ != LegacyTxType && sig.V != nil && sig.V.IsUint64() && sig.V.Uint64() <= 1 {
+		t.YParity = NumberFromBigIntPtr(sig.V)
+	}
+}
+
+// jsonSignature returns the transaction signature from the JSON-RPC fields.
+//
+// For typed transactions, the yParity field is preferred over the v field,
+// because some nodes return only the yParity field, and the v field is kept
+// only for backward compatibility. It returns nil if the signature is
+// incomplete.
+func jsonSignature(typ TransactionType, v, yParity, r, s *Number) *Signature {
+	if typ != LegacyTxType && yParity != nil {
+		v = yParity
+	}
+	if v == nil || r == nil || s == nil {
+		return nil
+	}
+	return SignatureFromVRSPtr(v.Big(), r.Big(), s.Big())
+}
+
 // AccessList is an EIP-2930 access list.
 type AccessList []AccessTuple
 
@@ -726,6 +1257,49 @@ type AccessTuple struct {
 	StorageKeys []Hash  `json:"storageKeys"`
 }
 
+// NewAccessList returns a new empty access list.
+func NewAccessList() *AccessList {
+	return &AccessList{}
+}
+
+// Add adds the given address and storage keys to the access list.
+//
+// If the address is already in the list, the storage keys are appended to the
+// existing tuple. Storage keys that are already present are skipped.
+func (a *AccessList) Add(addr Address, keys ...Hash) *AccessList {
+	for i := range *a {
+		tuple := &(*a)[i]
+		if tuple.Address != addr {
+			continue
+		}
+		for _, key := range keys {
+			if !tuple.containsKey(key) {
+				tuple.StorageKeys = append(tuple.StorageKeys, key)
+			}
+		}
+		return a
+	}
+	tuple := AccessTuple{Address: addr, StorageKeys: []Hash{}}
+	for _, key := range keys {
+		if !tuple.containsKey(key) {
+			tuple.StorageKeys = append(tuple.StorageKeys, key)
+		}
+	}
+	*a = append(*a, tuple)
+	return a
+}
+
+// Contains returns true if the access list contains the given storage key
+// for the given address.
+func (a AccessList) Contains(addr Address, key Hash) bool {
+	for _, tuple := range a {
+		if tuple.Address == addr && tuple.containsKey(key) {
+			return true
+		}
+	}
+	return false
+}
+
 func (a *AccessList) Copy() AccessList {
 	if a == nil {
 		return nil
@@ -774,6 +1348,16 @@ func (a *AccessTuple) Copy() AccessTuple {
 	}
 }
 
+// containsKey returns true if the tuple contains the given storage key.
+func (a *AccessTuple) containsKey(key Hash) bool {
+	for _, k := range a.StorageKeys {
+		if k == key {
+			return true
+		}
+	}
+	return false
+}
+
 func (a AccessTuple) EncodeRLP() ([]byte, error) {
 	h := rlp.NewList()
 	for _, hash := range a.StorageKeys {
@@ -812,28 +1396,70 @@ func (a *AccessTuple) DecodeRLP(data []byte) (int, error) {
 	return n, nil
 }
 
+// BlobHashList is a list of EIP-4844 blob versioned hashes. It is encoded as
+// an RLP list of hashes.
+type BlobHashList []Hash
+
+func (h BlobHashList) EncodeRLP() ([]byte, error) {
+	l := rlp.NewList()
+	for _, hash := range h {
+		hash := hash
+		l.Append(&hash)
+	}
+	return rlp.Encode(l)
+}
+
+func (h *BlobHashList) DecodeRLP(data []byte) (int, error) {
+	d, n, err := rlp.Decode(data)
+	if err != nil {
+		return 0, err
+	}
+	l, err := d.GetList()
+	if err != nil {
+		return 0, err
+	}
+	*h = make(BlobHashList, 0, len(l))
+	for _, item := range l {
+		var hash Hash
+		if err := item.DecodeTo(&hash); err != nil {
+			return 0, err
+		}
+		*h = append(*h, hash)
+	}
+	return n, nil
+}
+
 // TransactionReceipt represents transaction receipt.
 type TransactionReceipt struct {
-	TransactionHash   Hash     // TransactionHash is the hash of the transaction.
-	TransactionIndex  uint64   // TransactionIndex is the index of the transaction in the block.
-	BlockHash         Hash     // BlockHash is the hash of the block.
-	BlockNumber       *big.Int // BlockNumber is the number of the block.
-	From              Address  // From is the sender of the transaction.
-	To                Address  // To is the recipient of the transaction.
-	CumulativeGasUsed uint64   // CumulativeGasUsed is the total amount of gas used when this transaction was executed in the block.
-	EffectiveGasPrice *big.Int // EffectiveGasPrice is the effective gas price of the transaction.
-	GasUsed           uint64   // GasUsed is the amount of gas used by this specific transaction alone.
-	ContractAddress   *Address // ContractAddress is the contract address created, if the transaction was a contract creation, otherwise nil.
-	Logs              []Log    // Logs is the list of logs generated by the transaction.
-	LogsBloom         []byte   // LogsBloom is the bloom filter for the logs of the transaction.
-	Root              *Hash    // Root is the root of the state trie after the transaction.
-	Status            *uint64  // Status is the status of the transaction.
+	TransactionHash   Hash            // TransactionHash is the hash of the transaction.
+	TransactionIndex  uint64          // TransactionIndex is the index of the transaction in the block.
+	Type              TransactionType // Type is the EIP-2718 type of the transaction.
+	BlockHash         Hash            // BlockHash is the hash of the block.
+	BlockNumber       *big.Int        // BlockNumber is the number of the block.
+	From              Address         // From is the sender of the transaction.
+	To                Address         // To is the recipient of the transaction.
+	CumulativeGasUsed uint64          // CumulativeGasUsed is the total amount of gas used when this transaction was executed in the block.
+	EffectiveGasPrice *big.Int        // EffectiveGasPrice is the effective gas price of the transaction.
+	GasUsed           uint64          // GasUsed is the amount of gas used by this specific transaction alone.
+	ContractAddress   *Address        // ContractAddress is the contract address created, if the transaction was a contract creation, otherwise nil.
+	Logs              []Log           // Logs is the list of logs generated by the transaction.
+	LogsBloom         []byte          // LogsBloom is the bloom filter for the logs of the transaction.
+	Root              *Hash           // Root is the root of the state trie after the transaction.
+	Status            *uint64         // Status is the status of the transaction.
+
+	// L2 fields, nil if not returned by the node:
+	L1Fee        *big.Int // L1Fee is the fee paid for posting the transaction to L1 (OP-stack).
+	L1GasUsed    *big.Int // L1GasUsed is the amount of L1 gas used to post the transaction (OP-stack).
+	L1GasPrice   *big.Int // L1GasPrice is the L1 gas price used to calculate the L1 fee (OP-stack).
+	L1FeeScalar  *float64 // L1FeeScalar is the scalar applied to the L1 fee (OP-stack, pre-Ecotone).
+	GasUsedForL1 *big.Int // GasUsedForL1 is the amount of L2 gas spent to cover the L1 fee (Arbitrum).
 }
 
 func (t TransactionReceipt) MarshalJSON() ([]byte, error) {
 	receipt := &jsonTransactionReceipt{
 		TransactionHash:   t.TransactionHash,
 		TransactionIndex:  NumberFromUint64(t.TransactionIndex),
+		Type:              NumberFromUint64(uint64(t.Type)),
 		BlockHash:         t.BlockHash,
 		BlockNumber:       NumberFromBigInt(t.BlockNumber),
 		From:              t.From,
@@ -850,6 +1476,22 @@ func (t TransactionReceipt) MarshalJSON() ([]byte, error) {
 		status := NumberFromUint64(*t.Status)
 		receipt.Status = &status
 	}
+	if t.L1Fee != nil {
+		receipt.L1Fee = NumberFromBigIntPtr(t.L1Fee)
+	}
+	if t.L1GasUsed != nil {
+		receipt.L1GasUsed = NumberFromBigIntPtr(t.L1GasUsed)
+	}
+	if t.L1GasPrice != nil {
+		receipt.L1GasPrice = NumberFromBigIntPtr(t.L1GasPrice)
+	}
+	if t.L1FeeScalar != nil {
+		scalar := strconv.FormatFloat(*t.L1FeeScalar, 'f', -1, 64)
+		receipt.L1FeeScalar = &scalar
+	}
+	if t.GasUsedForL1 != nil {
+		receipt.GasUsedForL1 = NumberFromBigIntPtr(t.GasUsedForL1)
+	}
 	return json.Marshal(receipt)
 }
 
@@ -860,6 +1502,7 @@ func (t *TransactionReceipt) UnmarshalJSON(data []byte) error {
 	}
 	t.TransactionHash = receipt.TransactionHash
 	t.TransactionIndex = receipt.TransactionIndex.Big().Uint64()
+	t.Type = TransactionType(receipt.Type.Big().Uint64())
 	t.BlockHash = receipt.BlockHash
 	t.BlockNumber = receipt.BlockNumber.Big()
 	t.From = receipt.From
@@ -875,12 +1518,32 @@ func (t *TransactionReceipt) UnmarshalJSON(data []byte) error {
 		status := receipt.Status.Big().Uint64()
 		t.Status = &status
 	}
+	if receipt.L1Fee != nil {
+		t.L1Fee = receipt.L1Fee.Big()
+	}
+	if receipt.L1GasUsed != nil {
+		t.L1GasUsed = receipt.L1GasUsed.Big()
+	}
+	if receipt.L1GasPrice != nil {
+		t.L1GasPrice = receipt.L1GasPrice.Big()
+	}
+	if receipt.L1FeeScalar != nil {
+		scalar, err := strconv.ParseFloat(*receipt.L1FeeScalar, 64)
+		if err != nil {
+			return fmt.Errorf("invalid l1FeeScalar: %w", err)
+		}
+		t.L1FeeScalar = &scalar
+	}
+	if receipt.GasUsedForL1 != nil {
+		t.GasUsedForL1 = receipt.GasUsedForL1.Big()
+	}
 	return nil
 }
 
 type jsonTransactionReceipt struct {
 	TransactionHash   Hash     `json:"transactionHash"`
 	TransactionIndex  Number   `json:"transactionIndex"`
+	Type              Number   `json:"type"`
 	BlockHash         Hash     `json:"blockHash"`
 	BlockNumber       Number   `json:"blockNumber"`
 	From              Address  `json:"from"`
@@ -893,6 +1556,11 @@ type jsonTransactionReceipt struct {
 	LogsBloom         Bytes    `json:"logsBloom"`
 	Root              *Hash    `json:"root"`
 	Status            *Number  `json:"status"`
+	L1Fee             *Number  `json:"l1Fee,omitempty"`
+	L1GasUsed         *Number  `json:"l1GasUsed,omitempty"`
+	L1GasPrice        *Number  `json:"l1GasPrice,omitempty"`
+	L1FeeScalar       *string  `json:"l1FeeScalar,omitempty"`
+	GasUsedForL1      *Number  `json:"gasUsedForL1,omitempty"`
 }
 
 type Block struct {
@@ -917,29 +1585,49 @@ type Block struct {
 	Transactions      []OnChainTransaction // Transactions is the list of transactions in the block.
 	TransactionHashes []Hash               // TransactionHashes is the list of transaction hashes in the block.
 	ExtraData         []byte               // ExtraData is the "extra data" field of this block.
+
+	// EIP-1559 fields:
+	BaseFeePerGas *big.Int // BaseFeePerGas is the base fee per gas, nil for pre-London blocks.
+}
+
+// FindTransaction returns the transaction with the given hash. It returns nil
+// if the transaction is not found or if the block was fetched without full
+// transaction objects.
+func (b *Block) FindTransaction(hash Hash) *OnChainTransaction {
+	for i := range b.Transactions {
+		if tx := &b.Transactions[i]; tx.Hash != nil && *tx.Hash == hash {
+			return tx
+		}
+	}
+	return nil
 }
 
 func (b Block) MarshalJSON() ([]byte, error) {
 	block := &jsonBlock{
-		Number:           NumberFromBigInt(b.Number),
-		Hash:             b.Hash,
-		ParentHash:       b.ParentHash,
-		StateRoot:        b.StateRoot,
-		ReceiptsRoot:     b.ReceiptsRoot,
-		TransactionsRoot: b.TransactionsRoot,
-		MixHash:          b.MixHash,
-		Sha3Uncles:       b.Sha3Uncles,
-		Nonce:            nonceFromBigInt(b.Nonce),
-		Miner:            b.Miner,
-		LogsBloom:        bloomFromBytes(b.LogsBloom),
-		Difficulty:       NumberFromBigInt(b.Difficulty),
-		TotalDifficulty:  NumberFromBigInt(b.TotalDifficulty),
-		Size:             NumberFromUint64(b.Size),
-		GasLimit:         NumberFromUint64(b.GasLimit),
-		GasUsed:          NumberFromUint64(b.GasUsed),
-		Timestamp:        NumberFromUint64(uint64(b.Timestamp.Unix())),
-		Uncles:           b.Uncles,
-		ExtraData:        b.ExtraData,
+		jsonBlockHeader: jsonBlockHeader{
+			Number:           NumberFromBigInt(b.Number),
+			Hash:             b.Hash,
+			ParentHash:       b.ParentHash,
+			StateRoot:        b.StateRoot,
+			ReceiptsRoot:     b.ReceiptsRoot,
+			TransactionsRoot: b.TransactionsRoot,
+			MixHash:          b.MixHash,
+			Sha3Uncles:       b.Sha3Uncles,
+			Nonce:            nonceFromBigInt(b.Nonce),
+			Miner:            b.Miner,
+			LogsBloom:        bloomFromBytes(b.LogsBloom),
+			Difficulty:       NumberFromBigInt(b.Difficulty),
+			TotalDifficulty:  NumberFromBigInt(b.TotalDifficulty),
+			Size:             NumberFromUint64(b.Size),
+			GasLimit:         NumberFromUint64(b.GasLimit),
+			GasUsed:          NumberFromUint64(b.GasUsed),
+			Timestamp:        NumberFromUint64(uint64(b.Timestamp.Unix())),
+			Uncles:           b.Uncles,
+			ExtraData:        b.ExtraData,
+		},
+	}
+	if b.BaseFeePerGas != nil {
+		block.BaseFeePerGas = NumberFromBigIntPtr(b.BaseFeePerGas)
 	}
 	if len(b.Transactions) > 0 {
 		block.Transactions.Objects = b.Transactions
@@ -955,51 +1643,81 @@ func (b *Block) UnmarshalJSON(data []byte) error {
 	if err := json.Unmarshal(data, block); err != nil {
 		return err
 	}
-	b.Number = block.Number.Big()
-	b.Hash = block.Hash
-	b.ParentHash = block.ParentHash
-	b.StateRoot = block.StateRoot
-	b.ReceiptsRoot = block.ReceiptsRoot
-	b.TransactionsRoot = block.TransactionsRoot
-	b.MixHash = block.MixHash
-	b.Sha3Uncles = block.Sha3Uncles
-	b.Nonce = block.Nonce.Big()
-	b.Miner = block.Miner
-	b.LogsBloom = block.LogsBloom.Bytes()
-	b.Difficulty = block.Difficulty.Big()
-	b.TotalDifficulty = block.TotalDifficulty.Big()
-	b.Size = block.Size.Big().Uint64()
-	b.GasLimit = block.GasLimit.Big().Uint64()
-	b.GasUsed = block.GasUsed.Big().Uint64()
-	b.Timestamp = time.Unix(block.Timestamp.Big().Int64(), 0)
-	b.Uncles = block.Uncles
-	b.ExtraData = block.ExtraData
+	b.setHeader(&block.jsonBlockHeader)
 	b.Transactions = block.Transactions.Objects
 	b.TransactionHashes = block.Transactions.Hashes
 	return nil
 }
 
+// UnmarshalHeaderJSON is like UnmarshalJSON, but it decodes only the block
+// header. The transactions field is not parsed, and the Transactions and
+// TransactionHashes fields are set to nil.
+//
+// It is useful for decoding messages that contain only the block header, such
+// as the ones sent by the newHeads subscription.
+func (b *Block) UnmarshalHeaderJSON(data []byte) error {
+	header := &jsonBlockHeader{}
+	if err := json.Unmarshal(data, header); err != nil {
+		return err
+	}
+	b.setHeader(header)
+	b.Transactions = nil
+	b.TransactionHashes = nil
+	return nil
+}
+
+func (b *Block) setHeader(header *jsonBlockHeader) {
+	b.Number = header.Number.Big()
+	b.Hash = header.Hash
+	b.ParentHash = header.ParentHash
+	b.StateRoot = header.StateRoot
+	b.ReceiptsRoot = header.ReceiptsRoot
+	b.TransactionsRoot = header.TransactionsRoot
+	b.MixHash = header.MixHash
+	b.Sha3Uncles = header.Sha3Uncles
+	b.Nonce = header.Nonce.Big()
+	b.Miner = header.Miner
+	b.LogsBloom = header.LogsBloom.Bytes()
+	b.Difficulty = header.Difficulty.Big()
+	b.TotalDifficulty = header.TotalDifficulty.Big()
+	b.Size = header.Size.Big().Uint64()
+	b.GasLimit = header.GasLimit.Big().Uint64()
+	b.GasUsed = header.GasUsed.Big().Uint64()
+	b.Timestamp = time.Unix(header.Timestamp.Big().Int64(), 0)
+	b.Uncles = header.Uncles
+	b.ExtraData = header.ExtraData
+	b.BaseFeePerGas = nil
+	if header.BaseFeePerGas != nil {
+		b.BaseFeePerGas = header.BaseFeePerGas.Big()
+	}
+}
+
 type jsonBlock struct {
-	Number           Number                `json:"number"`
-	Hash             Hash                  `json:"hash"`
-	ParentHash       Hash                  `json:"parentHash"`
-	StateRoot        Hash                  `json:"stateRoot"`
-	ReceiptsRoot     Hash                  `json:"receiptsRoot"`
-	TransactionsRoot Hash                  `json:"transactionsRoot"`
-	MixHash          Hash                  `json:"mixHash"`
-	Sha3Uncles       Hash                  `json:"sha3Uncles"`
-	Nonce            hexNonce              `json:"nonce"`
-	Miner            Address               `json:"miner"`
-	LogsBloom        hexBloom              `json:"logsBloom"`
-	Difficulty       Number                `json:"difficulty"`
-	TotalDifficulty  Number                `json:"totalDifficulty"`
-	Size             Number                `json:"size"`
-	GasLimit         Number                `json:"gasLimit"`
-	GasUsed          Number                `json:"gasUsed"`
-	Timestamp        Number                `json:"timestamp"`
-	Uncles           []Hash                `json:"uncles"`
-	ExtraData        Bytes                 `json:"extraData"`
-	Transactions     jsonBlockTransactions `json:"transactions"`
+	jsonBlockHeader
+	Transactions jsonBlockTransactions `json:"transactions"`
+}
+
+type jsonBlockHeader struct {
+	Number           Number   `json:"number"`
+	Hash             Hash     `json:"hash"`
+	ParentHash       Hash     `json:"parentHash"`
+	StateRoot        Hash     `json:"stateRoot"`
+	ReceiptsRoot     Hash     `json:"receiptsRoot"`
+	TransactionsRoot Hash     `json:"transactionsRoot"`
+	MixHash          Hash     `json:"mixHash"`
+	Sha3Uncles       Hash     `json:"sha3Uncles"`
+	Nonce            hexNonce `json:"nonce"`
+	Miner            Address  `json:"miner"`
+	LogsBloom        hexBloom `json:"logsBloom"`
+	Difficulty       Number   `json:"difficulty"`
+	TotalDifficulty  Number   `json:"totalDifficulty"`
+	Size             Number   `json:"size"`
+	GasLimit         Number   `json:"gasLimit"`
+	GasUsed          Number   `json:"gasUsed"`
+	Timestamp        Number   `json:"timestamp"`
+	Uncles           []Hash   `json:"uncles"`
+	ExtraData        Bytes    `json:"extraData"`
+	BaseFeePerGas    *Number  `json:"baseFeePerGas,omitempty"`
 }
 
 type jsonBlockTransactions struct {
@@ -1014,22 +1732,103 @@ func (b *jsonBlockTransactions) MarshalJSON() ([]byte, error) {
 	return json.Marshal(b.Hashes)
 }
 
+// UnmarshalJSON decodes a list of either transaction objects or transaction
+// hashes. The format is detected by the first JSON token of the first
+// element.
 func (b *jsonBlockTransactions) UnmarshalJSON(data []byte) error {
-	if len(data) == 0 {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	if tok == nil {
 		return nil
 	}
-	if bytes.IndexByte(data[1:], '{') >= 0 {
+	if d, ok := tok.(json.Delim); !ok || d != '[' {
+		return fmt.Errorf("invalid block transactions: expected array, got %v", tok)
+	}
+	if !dec.More() {
+		return nil
+	}
+	tok, err = dec.Token()
+	if err != nil {
+		return err
+	}
+	if d, ok := tok.(json.Delim); ok && d == '{' {
 		return json.Unmarshal(data, &b.Objects)
 	}
 	return json.Unmarshal(data, &b.Hashes)
 }
 
+// TraceResult represents a single result of the trace_call and
+// trace_callMany methods supported by Erigon, Nethermind and OpenEthereum
+// derived nodes.
+//
+// Only the fields for the requested trace types are set. Traces are
+// returned as raw JSON, because their format differs between nodes.
+type TraceResult struct {
+	Output    Bytes           `json:"output"`              // Output is the return data of the call.
+	StateDiff json.RawMessage `json:"stateDiff,omitempty"` // StateDiff is set if the "stateDiff" trace type is requested.
+	Trace     json.RawMessage `json:"trace,omitempty"`     // Trace is set if the "trace" trace type is requested.
+	VMTrace   json.RawMessage `json:"vmTrace,omitempty"`   // VMTrace is set if the "vmTrace" trace type is requested.
+}
+
 // FeeHistory represents the result of the feeHistory Client call.
 type FeeHistory struct {
 	OldestBlock   uint64       // OldestBlock is the oldest block number for which the base fee and gas used are returned.
 	Reward        [][]*big.Int // Reward is the reward for each block in the range [OldestBlock, LatestBlock].
-	BaseFeePerGas []*big.Int   // BaseFeePerGas is the base fee per gas for each block in the range [OldestBlock, LatestBlock].
+	BaseFeePerGas []*big.Int   // BaseFeePerGas is the base fee per gas for each block in the range [OldestBlock, LatestBlock + 1].
 	GasUsedRatio  []float64    // GasUsedRatio is the gas used ratio for each block in the range [OldestBlock, LatestBlock].
+
+	// RewardPercentiles are the percentiles for which the rewards were
+	// requested, one for each column of Reward. It is not a part of the JSON
+	// representation. It is set by rpc.Client.FeeHistory, but it must be set
+	// manually if the fee history is unmarshalled from JSON.
+	RewardPercentiles []float64
+}
+
+// NextBaseFee returns the projected base fee per gas of the block following
+// the newest block in the history. It returns nil if the history contains no
+// base fees.
+func (f FeeHistory) NextBaseFee() *big.Int {
+	if len(f.BaseFeePerGas) == 0 {
+		return nil
+	}
+	return f.BaseFeePerGas[len(f.BaseFeePerGas)-1]
+}
+
+// SuggestTip returns the average of the rewards for the given percentile,
+// which must be one of the RewardPercentiles. Blocks without rewards, such as
+// empty blocks, are skipped. It returns nil if the percentile was not
+// requested or if there are no rewards.
+//
+// Because RewardPercentiles is not a part of the JSON representation,
+// SuggestTip always returns nil for a fee history unmarshalled from JSON,
+// unless RewardPercentiles is set afterwards.
+func (f FeeHistory) SuggestTip(percentile float64) *big.Int {
+	col := -1
+	for i, p := range f.RewardPercentiles {
+		if p == percentile {
+			col = i
+			break
+		}
+	}
+	if col < 0 {
+		return nil
+	}
+	sum := new(big.Int)
+	n := int64(0)
+	for _, reward := range f.Reward {
+		if col >= len(reward) || reward[col] == nil {
+			continue
+		}
+		sum.Add(sum, reward[col])
+		n++
+	}
+	if n == 0 {
+		return nil
+	}
+	return sum.Div(sum, big.NewInt(n))
 }
 
 func (f FeeHistory) MarshalJSON() ([]byte, error) {
@@ -1197,6 +1996,27 @@ func (q *FilterLogsQuery) AddTopics(topics ...[]Hash) *FilterLogsQuery {
 	return q
 }
 
+// MaxTopicPositions is the maximum number of topic positions in a log, and
+// therefore in a FilterLogsQuery.
+const MaxTopicPositions = 4
+
+// SetTopicPosition sets the topics at the given position. A log matches the
+// position if its topic is equal to any of the given values. If there are
+// fewer topic positions than pos, the missing positions are filled with nil,
+// which matches any topic. The values are copied.
+//
+// It panics if pos is negative or not less than MaxTopicPositions.
+func (q *FilterLogsQuery) SetTopicPosition(pos int, values ...Hash) *FilterLogsQuery {
+	if pos < 0 || pos >= MaxTopicPositions {
+		panic(fmt.Sprintf("types: topic position %d out of range [0, %d)", pos, MaxTopicPositions))
+	}
+	for len(q.Topics) <= pos {
+		q.Topics = append(q.Topics, nil)
+	}
+	q.Topics[pos] = append([]Hash(nil), values...)
+	return q
+}
+
 func (q *FilterLogsQuery) SetBlockHash(blockHash *Hash) *FilterLogsQuery {
 	q.BlockHash = blockHash
 	return q
@@ -1,6 +1,8 @@
 package types
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
 	"math/big"
 	"testing"
@@ -104,6 +106,24 @@ func TestTransaction_RLP(t1 *testing.T) {
 				SetMaxFeePerGas(big.NewInt(2000000000)),
 			want: hexutil.MustHexToBytes("02f8770101843b9aca008477359400830186a0942222222222222222222222222222222222222222880de0b6b3a76400008401020304c06fa0a3a7b12762dbc5df6cfbedbecdf8a821929c6112d2634abbb0d99dc63ad91490a08051b2c8c7d159db49ad19bd01026156eedab2f3d8c1dfdd07d21c07a4bbdd84"),
 		},
+		// Blob transaction:
+		{
+			tx: (&Transaction{}).
+				SetType(BlobTxType).
+				SetFrom(MustAddressFromHex("0x1111111111111111111111111111111111111111")).
+				SetTo(MustAddressFromHex("0x2222222222222222222222222222222222222222")).
+				SetGasLimit(100000).
+				SetInput([]byte{1, 2, 3, 4}).
+				SetNonce(1).
+				SetValue(big.NewInt(0)).
+				SetSignature(MustSignatureFromHex("0xa3a7b12762dbc5df6cfbedbecdf8a821929c6112d2634abbb0d99dc63ad914908051b2c8c7d159db49ad19bd01026156eedab2f3d8c1dfdd07d21c07a4bbdd8401")).
+				SetChainID(1).
+				SetMaxPriorityFeePerGas(big.NewInt(1000000000)).
+				SetMaxFeePerGas(big.NewInt(2000000000)).
+				SetMaxFeePerBlobGas(big.NewInt(3000000000)).
+				SetBlobVersionedHashes([]Hash{MustHashFromHex("0x0133333333333333333333333333333333333333333333333333333333333333", PadNone)}),
+			want: hexutil.MustHexToBytes("03f8960101843b9aca008477359400830186a0942222222222222222222222222222222222222222808401020304c084b2d05e00e1a0013333333333333333333333333333333333333333333333333333333333333301a0a3a7b12762dbc5df6cfbedbecdf8a821929c6112d2634abbb0d99dc63ad91490a08051b2c8c7d159db49ad19bd01026156eedab2f3d8c1dfdd07d21c07a4bbdd84"),
+		},
 		// Example from EIP-155:
 		{
 			tx: (&Transaction{}).
@@ -161,8 +181,881 @@ func equalTx(t *testing.T, expected, got *Transaction) {
 	}
 	assert.Equal(t, expected.MaxPriorityFeePerGas, got.MaxPriorityFeePerGas)
 	assert.Equal(t, expected.MaxFeePerGas, got.MaxFeePerGas)
+	assert.Equal(t, expected.MaxFeePerBlobGas, got.MaxFeePerBlobGas)
+	assert.Equal(t, expected.BlobVersionedHashes, got.BlobVersionedHashes)
 	for i, accessTuple := range expected.AccessList {
 		assert.Equal(t, accessTuple.Address, got.AccessList[i].Address)
 		assert.Equal(t, accessTuple.StorageKeys, got.AccessList[i].StorageKeys)
 	}
 }
+
+func TestTransaction_DecodeRLP_LegacyChainID(t *testing.T) {
+	tests := []struct {
+		raw     string
+		chainID *uint64
+	}{
+		// EIP-155 transaction from the EIP-155 specification, V = 37:
+		{
+			raw:     "0xf86c098504a817c800825208943535353535353535353535353535353535353535880de0b6b3a76400008025a028ef61340bd939bc2195fe537567866003e1a15d3c71ff63e1590620aa636276a067cbe9d8997f761aecb703304b3800ccf555c9f3dc64214b297fb1966a3b6d83",
+			chainID: func() *uint64 { v := uint64(1); return &v }(),
+		},
+		// EIP-155 transaction with chain ID 1337, V = 2709:
+		{
+			raw:     "0xf86e098504a817c800825208943535353535353535353535353535353535353535880de0b6b3a764000080820a95a014702a15dd7739397f25e3902a0c2bf6989e93888201139aac2c67a8f33a2f3fa04a10ba6cf47ace7e3c847e38583f5b1e1c7d8a862f4b43cd74480a03007363f7",
+			chainID: func() *uint64 { v := uint64(1337); return &v }(),
+		},
+		// Transaction without EIP-155 replay protection, V = 27:
+		{
+			raw:     "0xf86c098504a817c800825208943535353535353535353535353535353535353535880de0b6b3a7640000801ba02bfad43ba1b40e7f3ffb6342b1a6eecc700dd344fb0aba543aed5c10fd1a9470a0615bff48c483d368ed4f6e327a6ddd8831e544d0ca08f1345433e4ed204f8537",
+			chainID: nil,
+		},
+		// Unsigned transaction:
+		{
+			raw:     "0xc9808080808080808080",
+			chainID: nil,
+		},
+	}
+	for n, tt := range tests {
+		t.Run(fmt.Sprintf("case-%d", n+1), func(t *testing.T) {
+			var tx Transaction
+			_, err := tx.DecodeRLP(hexutil.MustHexToBytes(tt.raw))
+			require.NoError(t, err)
+			assert.Equal(t, LegacyTxType, tx.Type)
+			assert.Equal(t, tt.chainID, tx.ChainID)
+		})
+	}
+}
+
+func TestTransaction_DecodeRLP_UnsupportedType(t *testing.T) {
+	_, err := new(Transaction).DecodeRLP([]byte{0x7e, 0xc0})
+	require.Error(t, err)
+	assert.ErrorIs(t, err, ErrUnsupportedTxType)
+	assert.EqualError(t, err, "unsupported transaction type: 126")
+
+	var typErr *UnsupportedTxTypeError
+	require.True(t, errors.As(err, &typErr))
+	assert.Equal(t, TransactionType(0x7e), typErr.Type)
+
+	_, err = NewTransaction().SetType(TransactionType(5)).EncodeRLP()
+	assert.ErrorIs(t, err, ErrUnsupportedTxType)
+}
+
+func TestTransaction_AsMessage(t *testing.T) {
+	from := MustAddressFromHex("0x1111111111111111111111111111111111111111")
+	to := MustAddressFromHex("0x2222222222222222222222222222222222222222")
+	tests := []struct {
+		tx       *Transaction
+		baseFee  *big.Int
+		gasPrice *big.Int
+		wantErr  bool
+	}{
+		{
+			tx:       NewTransaction().SetType(LegacyTxType).SetGasPrice(big.NewInt(100)),
+			baseFee:  big.NewInt(10),
+			gasPrice: big.NewInt(100),
+		},
+		{
+			tx:       NewTransaction().SetType(AccessListTxType).SetGasPrice(big.NewInt(100)),
+			gasPrice: big.NewInt(100),
+		},
+		{
+			tx:       NewTransaction().SetType(DynamicFeeTxType).SetMaxFeePerGas(big.NewInt(100)).SetMaxPriorityFeePerGas(big.NewInt(5)),
+			baseFee:  big.NewInt(10),
+			gasPrice: big.NewInt(15),
+		},
+		{
+			tx:       NewTransaction().SetType(DynamicFeeTxType).SetMaxFeePerGas(big.NewInt(100)).SetMaxPriorityFeePerGas(big.NewInt(5)),
+			baseFee:  big.NewInt(99),
+			gasPrice: big.NewInt(100),
+		},
+		{
+			tx:       NewTransaction().SetType(DynamicFeeTxType).SetMaxFeePerGas(big.NewInt(100)).SetMaxPriorityFeePerGas(big.NewInt(5)),
+			gasPrice: big.NewInt(100),
+		},
+		{
+			tx:       NewTransaction().SetType(BlobTxType).SetMaxFeePerGas(big.NewInt(100)),
+			baseFee:  big.NewInt(10),
+			gasPrice: big.NewInt(10),
+		},
+		{
+			tx:      NewTransaction().SetType(DynamicFeeTxType),
+			wantErr: true,
+		},
+		{
+			tx:      NewTransaction().SetType(TransactionType(5)),
+			wantErr: true,
+		},
+	}
+	for n, tt := range tests {
+		t.Run(fmt.Sprintf("case-%d", n+1), func(t *testing.T) {
+			tx := tt.tx.
+				SetFrom(from).
+				SetTo(to).
+				SetNonce(3).
+				SetValue(big.NewInt(1000)).
+				SetGasLimit(21000).
+				SetInput([]byte{1, 2, 3})
+			msg, err := tx.AsMessage(nil, tt.baseFee)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, from, msg.From)
+			assert.Equal(t, &to, msg.To)
+			assert.Equal(t, uint64(3), msg.Nonce)
+			assert.Equal(t, big.NewInt(1000), msg.Value)
+			assert.Equal(t, uint64(21000), msg.GasLimit)
+			assert.Equal(t, []byte{1, 2, 3}, msg.Data)
+			assert.Equal(t, tt.gasPrice, msg.GasPrice)
+		})
+	}
+
+	// The sender must be known.
+	_, err := NewTransaction().SetTo(to).AsMessage(nil, nil)
+	assert.Error(t, err)
+}
+
+type recovererMock struct {
+	addr *Address
+	err  error
+}
+
+func (r recovererMock) RecoverTransaction(*Transaction) (*Address, error) {
+	return r.addr, r.err
+}
+
+func TestTransaction_AsMessage_Copy(t *testing.T) {
+	from := MustAddressFromHex("0x1111111111111111111111111111111111111111")
+	to := MustAddressFromHex("0x2222222222222222222222222222222222222222")
+	key := MustHashFromHex("0x01", PadLeft)
+	tx := NewTransaction().
+		SetType(AccessListTxType).
+		SetFrom(from).
+		SetTo(to).
+		SetGasPrice(big.NewInt(100)).
+		SetValue(big.NewInt(1)).
+		SetInput([]byte{1, 2, 3}).
+		SetAccessList(AccessList{{Address: to, StorageKeys: []Hash{key}}})
+
+	msg, err := tx.AsMessage(nil, nil)
+	require.NoError(t, err)
+
+	// Modifying the message must not modify the transaction.
+	*msg.To = from
+	msg.Value.SetInt64(2)
+	msg.Data[0] = 0xff
+	msg.AccessList[0].Address = from
+	msg.AccessList[0].StorageKeys[0] = Hash{}
+	assert.Equal(t, to, *tx.To)
+	assert.Equal(t, big.NewInt(1), tx.Value)
+	assert.Equal(t, []byte{1, 2, 3}, tx.Input)
+	assert.Equal(t, AccessList{{Address: to, StorageKeys: []Hash{key}}}, tx.AccessList)
+}
+
+func TestTransaction_AsMessage_Recover(t *testing.T) {
+	from := MustAddressFromHex("0x1111111111111111111111111111111111111111")
+	recovered := MustAddressFromHex("0x3333333333333333333333333333333333333333")
+	sig := SignatureFromVRS(big.NewInt(27), big.NewInt(1), big.NewInt(1))
+	tx := NewTransaction().
+		SetType(LegacyTxType).
+		SetGasPrice(big.NewInt(100)).
+		SetInput([]byte{1, 2, 3})
+
+	// The sender is recovered from the signature.
+	msg, err := tx.SetSignature(sig).AsMessage(recovererMock{addr: &recovered}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, recovered, msg.From)
+
+	// The data is not shared with the transaction.
+	msg.Data[0] = 0xff
+	assert.Equal(t, []byte{1, 2, 3}, tx.Input)
+
+	// Recovery errors are returned.
+	_, err = tx.AsMessage(recovererMock{err: errors.New("invalid signature")}, nil)
+	assert.Error(t, err)
+
+	// The From field is used for unsigned transactions.
+	tx.Signature = nil
+	msg, err = tx.SetFrom(from).AsMessage(recovererMock{err: errors.New("invalid signature")}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, from, msg.From)
+}
+
+func TestTransaction_TxHash(t *testing.T) {
+	// Signed transaction from the EIP-155 specification.
+	var tx Transaction
+	_, err := tx.DecodeRLP(hexutil.MustHexToBytes("0xf86c098504a817c800825208943535353535353535353535353535353535353535880de0b6b3a76400008025a028ef61340bd939bc2195fe537567866003e1a15d3c71ff63e1590620aa636276a067cbe9d8997f761aecb703304b3800ccf555c9f3dc64214b297fb1966a3b6d83"))
+	require.NoError(t, err)
+
+	h, err := tx.TxHash()
+	require.NoError(t, err)
+	assert.Equal(t, MustHashFromHex("0x33469b22e9f636356c4160a87eb19df52b7412e8eac32a4a55ffe88ea8350788", PadNone), h)
+
+	h2, err := tx.Hash(keccak256)
+	require.NoError(t, err)
+	assert.Equal(t, h, h2)
+}
+
+func TestCall_MarshalJSON(t *testing.T) {
+	tests := []struct {
+		name string
+		call *Call
+		want string
+	}{
+		{
+			name: "minimal read call",
+			call: NewCall().
+				SetTo(MustAddressFromHex("0x1111111111111111111111111111111111111111")).
+				SetInput(hexutil.MustHexToBytes("0x01020304")),
+			want: `{"to":"0x1111111111111111111111111111111111111111","data":"0x01020304"}`,
+		},
+		{
+			name: "zero gas limit and value",
+			call: NewCall().
+				SetTo(MustAddressFromHex("0x1111111111111111111111111111111111111111")).
+				SetGasLimit(0).
+				SetValue(big.NewInt(0)).
+				SetInput(hexutil.MustHexToBytes("0x01020304")),
+			want: `{"to":"0x1111111111111111111111111111111111111111","data":"0x01020304"}`,
+		},
+		{
+			name: "non-zero gas limit and value",
+			call: NewCall().
+				SetTo(MustAddressFromHex("0x1111111111111111111111111111111111111111")).
+				SetGasLimit(21000).
+				SetValue(big.NewInt(1)).
+				SetInput(hexutil.MustHexToBytes("0x01020304")),
+			want: `{"to":"0x1111111111111111111111111111111111111111","gas":"0x5208","value":"0x1","data":"0x01020304"}`,
+		},
+		{
+			name: "all fields",
+			call: NewCall().
+				SetAccessList(AccessList{}).
+				SetInput(hexutil.MustHexToBytes("0x01")).
+				SetValue(big.NewInt(1)).
+				SetMaxPriorityFeePerGas(big.NewInt(2)).
+				SetMaxFeePerGas(big.NewInt(3)).
+				SetGasPrice(big.NewInt(4)).
+				SetGasLimit(5).
+				SetTo(MustAddressFromHex("0x2222222222222222222222222222222222222222")).
+				SetFrom(MustAddressFromHex("0x1111111111111111111111111111111111111111")),
+			want: `{"from":"0x1111111111111111111111111111111111111111","to":"0x2222222222222222222222222222222222222222","gas":"0x5","gasPrice":"0x4","maxFeePerGas":"0x3","maxPriorityFeePerGas":"0x2","value":"0x1","data":"0x01"}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := json.Marshal(tt.call)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, string(got))
+		})
+	}
+}
+
+func TestCall_CacheKey(t *testing.T) {
+	newCall := func() *Call {
+		return NewCall().
+			SetTo(MustAddressFromHex("0x1111111111111111111111111111111111111111")).
+			SetInput(hexutil.MustHexToBytes("0x01020304"))
+	}
+
+	k1, err := newCall().CacheKey(LatestBlockNumber)
+	require.NoError(t, err)
+	k2, err := newCall().SetValue(big.NewInt(0)).CacheKey(LatestBlockNumber)
+	require.NoError(t, err)
+	k3, err := newCall().CacheKey(BlockNumberFromUint64(1))
+	require.NoError(t, err)
+	k4, err := newCall().SetValue(big.NewInt(1)).CacheKey(LatestBlockNumber)
+	require.NoError(t, err)
+
+	assert.Equal(t, k1, k2) // zero value is omitted from the request
+	assert.NotEqual(t, k1, k3)
+	assert.NotEqual(t, k1, k4)
+}
+
+func TestTransactionReceipt_L2FeesJSON(t *testing.T) {
+	data := []byte(`{
+		"type": "0x2",
+		"l1Fee": "0x2a",
+		"l1GasUsed": "0x640",
+		"l1GasPrice": "0x3b9aca00",
+		"l1FeeScalar": "0.684",
+		"gasUsedForL1": "0x10"
+	}`)
+
+	var receipt TransactionReceipt
+	require.NoError(t, json.Unmarshal(data, &receipt))
+	assert.Equal(t, big.NewInt(42), receipt.L1Fee)
+	assert.Equal(t, big.NewInt(1600), receipt.L1GasUsed)
+	assert.Equal(t, big.NewInt(1000000000), receipt.L1GasPrice)
+	require.NotNil(t, receipt.L1FeeScalar)
+	assert.Equal(t, 0.684, *receipt.L1FeeScalar)
+	assert.Equal(t, big.NewInt(16), receipt.GasUsedForL1)
+
+	out, err := json.Marshal(receipt)
+	require.NoError(t, err)
+	assert.Contains(t, string(out), `"l1Fee":"0x2a"`)
+	assert.Contains(t, string(out), `"l1FeeScalar":"0.684"`)
+	assert.Contains(t, string(out), `"gasUsedForL1":"0x10"`)
+
+	// L1 receipts do not contain the L2 fields.
+	var l1Receipt TransactionReceipt
+	require.NoError(t, json.Unmarshal([]byte(`{"type":"0x2"}`), &l1Receipt))
+	assert.Nil(t, l1Receipt.L1Fee)
+	assert.Nil(t, l1Receipt.L1FeeScalar)
+	out, err = json.Marshal(l1Receipt)
+	require.NoError(t, err)
+	assert.NotContains(t, string(out), "l1Fee")
+
+	assert.Error(t, json.Unmarshal([]byte(`{"l1FeeScalar":"foo"}`), &l1Receipt))
+}
+
+func TestBlock_UnmarshalHeaderJSON(t *testing.T) {
+	data := []byte(`{
+		"number": "0x11",
+		"hash": "0x2222222222222222222222222222222222222222222222222222222222222222",
+		"timestamp": "0x54e34e8e",
+		"baseFeePerGas": "0x7",
+		"transactions": ["0x1111111111111111111111111111111111111111111111111111111111111111"]
+	}`)
+
+	var full Block
+	require.NoError(t, json.Unmarshal(data, &full))
+	assert.Equal(t, big.NewInt(7), full.BaseFeePerGas)
+	assert.Len(t, full.TransactionHashes, 1)
+
+	var header Block
+	require.NoError(t, header.UnmarshalHeaderJSON(data))
+	assert.Equal(t, big.NewInt(0x11), header.Number)
+	assert.Equal(t, MustHashFromHex("0x2222222222222222222222222222222222222222222222222222222222222222", PadNone), header.Hash)
+	assert.Equal(t, big.NewInt(7), header.BaseFeePerGas)
+	assert.Nil(t, header.Transactions)
+	assert.Nil(t, header.TransactionHashes)
+
+	// Marshaled block must contain the base fee.
+	out, err := json.Marshal(full)
+	require.NoError(t, err)
+	assert.Contains(t, string(out), `"baseFeePerGas":"0x7"`)
+}
+
+func TestBlock_UnmarshalTransactionsJSON(t *testing.T) {
+	h1 := MustHashFromHex("0x1111111111111111111111111111111111111111111111111111111111111111", PadNone)
+	h2 := MustHashFromHex("0x2222222222222222222222222222222222222222222222222222222222222222", PadNone)
+	tests := []struct {
+		name       string
+		txs        string
+		wantObjs   int
+		wantHashes []Hash
+		wantErr    bool
+	}{
+		{name: "empty", txs: `[]`},
+		{name: "empty with whitespace", txs: ` [ ] `},
+		{name: "null", txs: `null`},
+		{name: "hashes", txs: `["` + h1.String() + `", "` + h2.String() + `"]`, wantHashes: []Hash{h1, h2}},
+		{name: "objects", txs: `[ {"hash": "` + h1.String() + `"}, {"hash": "` + h2.String() + `"} ]`, wantObjs: 2},
+		{name: "mixed", txs: `[{"hash": "` + h1.String() + `"}, "` + h2.String() + `"]`, wantErr: true},
+		{name: "invalid element", txs: `[1]`, wantErr: true},
+		{name: "invalid hash", txs: `["0x01"]`, wantErr: true},
+		{name: "not an array", txs: `{}`, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var b Block
+			err := json.Unmarshal([]byte(`{"transactions": `+tt.txs+`}`), &b)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Len(t, b.Transactions, tt.wantObjs)
+			assert.Equal(t, tt.wantHashes, b.TransactionHashes)
+		})
+	}
+}
+
+func TestBlock_FindTransaction(t *testing.T) {
+	h1 := MustHashFromHex("0x1111111111111111111111111111111111111111111111111111111111111111", PadNone)
+	h2 := MustHashFromHex("0x2222222222222222222222222222222222222222222222222222222222222222", PadNone)
+	b := Block{Transactions: []OnChainTransaction{{Hash: &h1}, {Hash: &h2}}}
+
+	tx := b.FindTransaction(h2)
+	require.NotNil(t, tx)
+	assert.Same(t, &b.Transactions[1], tx)
+	assert.Nil(t, b.FindTransaction(Hash{}))
+
+	// Blocks fetched without full transaction objects have no transactions.
+	assert.Nil(t, (&Block{TransactionHashes: []Hash{h1}}).FindTransaction(h1))
+}
+
+func TestFilterLogsQuery_SetTopicPosition(t *testing.T) {
+	h1 := MustHashFromHex("0x1111111111111111111111111111111111111111111111111111111111111111", PadNone)
+	h2 := MustHashFromHex("0x2222222222222222222222222222222222222222222222222222222222222222", PadNone)
+	h3 := MustHashFromHex("0x3333333333333333333333333333333333333333333333333333333333333333", PadNone)
+
+	q := NewFilterLogsQuery().SetTopicPosition(2, h2, h3)
+	assert.Equal(t, [][]Hash{nil, nil, {h2, h3}}, q.Topics)
+
+	q.SetTopicPosition(0, h1)
+	assert.Equal(t, [][]Hash{{h1}, nil, {h2, h3}}, q.Topics)
+
+	j, err := json.Marshal(q)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"address": null,
+		"topics": [
+			"0x1111111111111111111111111111111111111111111111111111111111111111",
+			[],
+			[
+				"0x2222222222222222222222222222222222222222222222222222222222222222",
+				"0x3333333333333333333333333333333333333333333333333333333333333333"
+			]
+		]
+	}`, string(j))
+
+	// The values are copied.
+	values := []Hash{h1}
+	q.SetTopicPosition(3, values...)
+	values[0] = h2
+	assert.Equal(t, []Hash{h1}, q.Topics[3])
+
+	// Only positions supported by eth_getLogs are accepted.
+	assert.Panics(t, func() { q.SetTopicPosition(-1, h1) })
+	assert.Panics(t, func() { q.SetTopicPosition(MaxTopicPositions, h1) })
+}
+
+func TestTransaction_InferType(t *testing.T) {
+	tests := []struct {
+		tx   *Transaction
+		want TransactionType
+	}{
+		{tx: NewTransaction(), want: LegacyTxType},
+		{tx: NewTransaction().SetGasPrice(big.NewInt(1)), want: LegacyTxType},
+		{tx: NewTransaction().SetGasPrice(big.NewInt(1)).SetAccessList(AccessList{}), want: AccessListTxType},
+		{tx: NewTransaction().SetMaxFeePerGas(big.NewInt(1)).SetAccessList(AccessList{}), want: DynamicFeeTxType},
+		{tx: NewTransaction().SetMaxPriorityFeePerGas(big.NewInt(1)), want: DynamicFeeTxType},
+		{tx: NewTransaction().SetMaxFeePerGas(big.NewInt(1)).SetMaxFeePerBlobGas(big.NewInt(1)), want: BlobTxType},
+		{tx: NewTransaction().SetBlobVersionedHashes([]Hash{{}}), want: BlobTxType},
+		{tx: NewTransaction().SetType(DynamicFeeTxType), want: LegacyTxType},
+	}
+	for n, tt := range tests {
+		t.Run(fmt.Sprintf("case-%d", n+1), func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.tx.InferType())
+		})
+	}
+}
+
+func TestTransaction_Validate(t *testing.T) {
+	to := MustAddressFromHex("0x3535353535353535353535353535353535353535")
+	blobHash := MustHashFromHex("0x0133333333333333333333333333333333333333333333333333333333333333", PadNone)
+	tests := []struct {
+		tx      *Transaction
+		wantErr string
+	}{
+		{tx: NewTransaction().SetType(LegacyTxType).SetTo(to).SetGasPrice(big.NewInt(1))},
+		{tx: NewTransaction().SetType(AccessListTxType).SetTo(to).SetAccessList(AccessList{{Address: to}})},
+		{tx: NewTransaction().SetType(DynamicFeeTxType).SetTo(to).SetMaxFeePerGas(big.NewInt(2)).SetMaxPriorityFeePerGas(big.NewInt(1))},
+		{tx: NewTransaction().SetType(BlobTxType).SetTo(to).SetBlobVersionedHashes([]Hash{blobHash})},
+		{tx: NewTransaction().SetType(LegacyTxType).SetInput([]byte{1})},
+		{
+			tx:      NewTransaction().SetType(LegacyTxType).SetTo(to).SetMaxFeePerGas(big.NewInt(1)),
+			wantErr: "legacy transaction must not set MaxFeePerGas or MaxPriorityFeePerGas",
+		},
+		{
+			tx:      NewTransaction().SetType(LegacyTxType).SetTo(to).SetAccessList(AccessList{{Address: to}}),
+			wantErr: "legacy transaction must not set AccessList",
+		},
+		{
+			tx:      NewTransaction().SetType(DynamicFeeTxType).SetTo(to).SetGasPrice(big.NewInt(1)),
+			wantErr: "dynamic fee transaction must not set GasPrice",
+		},
+		{
+			tx:      NewTransaction().SetType(DynamicFeeTxType).SetTo(to).SetMaxFeePerGas(big.NewInt(1)).SetMaxPriorityFeePerGas(big.NewInt(2)),
+			wantErr: "MaxPriorityFeePerGas is greater than MaxFeePerGas",
+		},
+		{
+			tx:      NewTransaction().SetType(BlobTxType).SetTo(to),
+			wantErr: "blob transaction must have at least one blob hash",
+		},
+		{
+			tx:      NewTransaction().SetType(BlobTxType).SetBlobVersionedHashes([]Hash{blobHash}),
+			wantErr: "blob transaction cannot create a contract",
+		},
+		{
+			tx:      NewTransaction().SetType(DynamicFeeTxType).SetTo(to).SetMaxFeePerBlobGas(big.NewInt(1)),
+			wantErr: "dynamic fee transaction must not set blob fields",
+		},
+		{
+			tx:      NewTransaction().SetType(LegacyTxType).SetTo(to).SetValue(big.NewInt(-1)),
+			wantErr: "Value must not be negative",
+		},
+		{
+			tx:      NewTransaction().SetType(LegacyTxType),
+			wantErr: "contract creation transaction must have input data",
+		},
+		{
+			tx:      NewTransaction().SetType(TransactionType(5)).SetTo(to),
+			wantErr: "unsupported transaction type: 5",
+		},
+	}
+	for n, tt := range tests {
+		t.Run(fmt.Sprintf("case-%d", n+1), func(t *testing.T) {
+			err := tt.tx.Validate()
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+			} else {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+			}
+		})
+	}
+
+	err := NewTransaction().SetType(TransactionType(5)).SetTo(to).Validate()
+	assert.ErrorIs(t, err, ErrUnsupportedTxType)
+}
+
+func TestTransaction_DecodeRLPStrict(t *testing.T) {
+	n, _ := new(big.Int).SetString("fffffffffffffffffffffffffffffffebaaedce6af48a03bbfd25e8cd0364141", 16)
+	r, _ := new(big.Int).SetString("18515461264373351373200002665853028612451056578545711640558177340181847433846", 10)
+	s, _ := new(big.Int).SetString("46948507304638947509940763649030358759909902576025900602547168820602576006531", 10)
+	newTx := func(typ TransactionType, sig Signature) *Transaction {
+		return (&Transaction{}).
+			SetType(typ).
+			SetChainID(1).
+			SetTo(MustAddressFromHex("0x3535353535353535353535353535353535353535")).
+			SetGasLimit(21000).
+			SetGasPrice(big.NewInt(20000000000)).
+			SetNonce(9).
+			SetValue(big.NewInt(1000000000000000000)).
+			SetSignature(sig)
+	}
+	tests := []struct {
+		name    string
+		tx      *Transaction
+		wantErr bool
+	}{
+		{
+			name: "valid legacy",
+			tx:   newTx(LegacyTxType, SignatureFromVRS(big.NewInt(37), r, s)),
+		},
+		{
+			name: "valid typed",
+			tx:   newTx(AccessListTxType, SignatureFromVRS(big.NewInt(0), r, s)),
+		},
+		{
+			name:    "malleable S",
+			tx:      newTx(LegacyTxType, SignatureFromVRS(big.NewInt(37), r, new(big.Int).Sub(n, s))),
+			wantErr: true,
+		},
+		{
+			name: "valid legacy without replay protection",
+			tx:   newTx(LegacyTxType, SignatureFromVRS(big.NewInt(28), r, s)),
+		},
+		{
+			name: "valid legacy with V=38",
+			tx:   newTx(LegacyTxType, SignatureFromVRS(big.NewInt(38), r, s)),
+		},
+		{
+			name:    "legacy with V=29",
+			tx:      newTx(LegacyTxType, SignatureFromVRS(big.NewInt(29), r, s)),
+			wantErr: true,
+		},
+		{
+			name:    "legacy with V=35",
+			tx:      newTx(LegacyTxType, SignatureFromVRS(big.NewInt(35), r, s)),
+			wantErr: true,
+		},
+		{
+			name:    "legacy with V=36",
+			tx:      newTx(LegacyTxType, SignatureFromVRS(big.NewInt(36), r, s)),
+			wantErr: true,
+		},
+		{
+			name:    "legacy with V=1",
+			tx:      newTx(LegacyTxType, SignatureFromVRS(big.NewInt(1), r, s)),
+			wantErr: true,
+		},
+		{
+			name:    "typed with V=27",
+			tx:      newTx(AccessListTxType, SignatureFromVRS(big.NewInt(27), r, s)),
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			raw, err := tt.tx.Raw()
+			require.NoError(t, err)
+
+			// Non-strict decoding must always succeed.
+			_, err = new(Transaction).DecodeRLP(raw)
+			require.NoError(t, err)
+
+			_, err = new(Transaction).DecodeRLPStrict(raw)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestTransaction_SetFeeData(t *testing.T) {
+	t.Run("legacy", func(t *testing.T) {
+		tx := NewTransaction().
+			SetMaxFeePerGas(big.NewInt(1)).
+			SetMaxPriorityFeePerGas(big.NewInt(1)).
+			SetType(DynamicFeeTxType).
+			SetFeeData(FeeData{GasPrice: big.NewInt(100)})
+		assert.Equal(t, LegacyTxType, tx.Type)
+		assert.Equal(t, big.NewInt(100), tx.GasPrice)
+		assert.Nil(t, tx.MaxFeePerGas)
+		assert.Nil(t, tx.MaxPriorityFeePerGas)
+	})
+	t.Run("access list", func(t *testing.T) {
+		tx := NewTransaction().
+			SetAccessList(AccessList{}).
+			SetFeeData(FeeData{GasPrice: big.NewInt(100)})
+		assert.Equal(t, AccessListTxType, tx.Type)
+		assert.Equal(t, big.NewInt(100), tx.GasPrice)
+	})
+	t.Run("dynamic fee", func(t *testing.T) {
+		tx := NewTransaction().
+			SetGasPrice(big.NewInt(1)).
+			SetFeeData(FeeData{MaxFeePerGas: big.NewInt(200), MaxPriorityFeePerGas: big.NewInt(2)})
+		assert.Equal(t, DynamicFeeTxType, tx.Type)
+		assert.Nil(t, tx.GasPrice)
+		assert.Equal(t, big.NewInt(200), tx.MaxFeePerGas)
+		assert.Equal(t, big.NewInt(2), tx.MaxPriorityFeePerGas)
+	})
+	t.Run("blob", func(t *testing.T) {
+		tx := NewTransaction().
+			SetType(BlobTxType).
+			SetFeeData(FeeData{MaxFeePerGas: big.NewInt(200), MaxPriorityFeePerGas: big.NewInt(2)})
+		assert.Equal(t, BlobTxType, tx.Type)
+		assert.Equal(t, big.NewInt(200), tx.MaxFeePerGas)
+		assert.Equal(t, big.NewInt(2), tx.MaxPriorityFeePerGas)
+	})
+}
+
+func TestFeeCap(t *testing.T) {
+	baseFee := big.NewInt(100)
+	assert.Equal(t, big.NewInt(205), FeeCap(baseFee, big.NewInt(5)))
+	assert.Equal(t, big.NewInt(100), baseFee)
+}
+
+func TestAccessList_Builder(t *testing.T) {
+	addr1 := MustAddressFromHex("0x1111111111111111111111111111111111111111")
+	addr2 := MustAddressFromHex("0x2222222222222222222222222222222222222222")
+	key1 := MustHashFromHex("0x01", PadLeft)
+	key2 := MustHashFromHex("0x02", PadLeft)
+
+	al := NewAccessList().
+		Add(addr1, key1).
+		Add(addr2).
+		Add(addr1, key1, key2)
+
+	require.Len(t, *al, 2)
+	assert.Equal(t, addr1, (*al)[0].Address)
+	assert.Equal(t, []Hash{key1, key2}, (*al)[0].StorageKeys)
+	assert.Equal(t, addr2, (*al)[1].Address)
+	assert.Equal(t, []Hash{}, (*al)[1].StorageKeys)
+
+	assert.True(t, al.Contains(addr1, key1))
+	assert.True(t, al.Contains(addr1, key2))
+	assert.False(t, al.Contains(addr2, key1))
+	assert.False(t, al.Contains(MustAddressFromHex("0x3333333333333333333333333333333333333333"), key1))
+}
+
+func TestAccessList_JSON(t *testing.T) {
+	al := NewAccessList().
+		Add(MustAddressFromHex("0x1111111111111111111111111111111111111111"), MustHashFromHex("0x01", PadLeft)).
+		Add(MustAddressFromHex("0x2222222222222222222222222222222222222222"))
+	want := `[
+		{"address":"0x1111111111111111111111111111111111111111","storageKeys":["0x0000000000000000000000000000000000000000000000000000000000000001"]},
+		{"address":"0x2222222222222222222222222222222222222222","storageKeys":[]}
+	]`
+
+	j, err := json.Marshal(al)
+	require.NoError(t, err)
+	assert.JSONEq(t, want, string(j))
+
+	var got AccessList
+	require.NoError(t, json.Unmarshal(j, &got))
+	assert.Equal(t, *al, got)
+}
+
+func TestAccessList_RLP(t *testing.T) {
+	al := NewAccessList().
+		Add(MustAddressFromHex("0x1111111111111111111111111111111111111111"), MustHashFromHex("0x01", PadLeft), MustHashFromHex("0x02", PadLeft)).
+		Add(MustAddressFromHex("0x2222222222222222222222222222222222222222"), MustHashFromHex("0x03", PadLeft))
+
+	b, err := al.EncodeRLP()
+	require.NoError(t, err)
+
+	var got AccessList
+	_, err = got.DecodeRLP(b)
+	require.NoError(t, err)
+	assert.Equal(t, *al, got)
+
+	b, err = AccessList{}.EncodeRLP()
+	require.NoError(t, err)
+	assert.Equal(t, []byte{0xc0}, b)
+}
+
+func TestOnChainTransaction_UnmarshalJSON(t *testing.T) {
+	t.Run("dynamic fee", func(t *testing.T) {
+		var tx OnChainTransaction
+		require.NoError(t, json.Unmarshal([]byte(`{
+			"type": "0x2",
+			"hash": "0x3333333333333333333333333333333333333333333333333333333333333333",
+			"from": "0x1111111111111111111111111111111111111111",
+			"to": "0x2222222222222222222222222222222222222222",
+			"gas": "0x5208",
+			"gasPrice": "0x3b9aca00",
+			"maxFeePerGas": "0x77359400",
+			"maxPriorityFeePerGas": "0x3b9aca00",
+			"nonce": "0x1",
+			"value": "0x0",
+			"input": "0x",
+			"accessList": [],
+			"yParity": "0x1",
+			"r": "0x1",
+			"s": "0x2"
+		}`), &tx))
+		assert.Equal(t, DynamicFeeTxType, tx.Type)
+		assert.Equal(t, big.NewInt(2000000000), tx.MaxFeePerGas)
+		assert.Equal(t, big.NewInt(1000000000), tx.MaxPriorityFeePerGas)
+		require.NotNil(t, tx.Signature)
+		assert.Equal(t, big.NewInt(1), tx.Signature.V)
+		assert.Equal(t, big.NewInt(1), tx.Signature.R)
+		assert.Equal(t, big.NewInt(2), tx.Signature.S)
+	})
+	t.Run("yParity preferred over v", func(t *testing.T) {
+		var tx OnChainTransaction
+		require.NoError(t, json.Unmarshal([]byte(`{
+			"type": "0x2",
+			"v": "0x1c",
+			"yParity": "0x1",
+			"r": "0x1",
+			"s": "0x2"
+		}`), &tx))
+		require.NotNil(t, tx.Signature)
+		assert.Equal(t, big.NewInt(1), tx.Signature.V)
+	})
+	t.Run("incomplete signature", func(t *testing.T) {
+		var tx OnChainTransaction
+		require.NoError(t, json.Unmarshal([]byte(`{
+			"type": "0x2",
+			"yParity": "0x1",
+			"r": "0x1"
+		}`), &tx))
+		assert.Nil(t, tx.Signature)
+	})
+	t.Run("legacy", func(t *testing.T) {
+		var tx OnChainTransaction
+		require.NoError(t, json.Unmarshal([]byte(`{
+			"type": "0x0",
+			"gasPrice": "0x3b9aca00",
+			"v": "0x25",
+			"r": "0x1",
+			"s": "0x2"
+		}`), &tx))
+		assert.Equal(t, LegacyTxType, tx.Type)
+		assert.Nil(t, tx.ChainID)
+		assert.Equal(t, big.NewInt(37), tx.Signature.V)
+	})
+	t.Run("chain id", func(t *testing.T) {
+		var tx OnChainTransaction
+		require.NoError(t, json.Unmarshal([]byte(`{
+			"type": "0x0",
+			"chainId": "0x1",
+			"gasPrice": "0x3b9aca00",
+			"v": "0x25",
+			"r": "0x1",
+			"s": "0x2"
+		}`), &tx))
+		require.NotNil(t, tx.ChainID)
+		assert.Equal(t, uint64(1), *tx.ChainID)
+
+		j, err := json.Marshal(tx)
+		require.NoError(t, err)
+		assert.Contains(t, string(j), `"chainId":"0x1"`)
+	})
+	t.Run("round trip", func(t *testing.T) {
+		tx := NewTransaction().
+			SetType(DynamicFeeTxType).
+			SetMaxFeePerGas(big.NewInt(2)).
+			SetMaxPriorityFeePerGas(big.NewInt(1))
+		j, err := json.Marshal(tx)
+		require.NoError(t, err)
+
+		var got Transaction
+		require.NoError(t, json.Unmarshal(j, &got))
+		assert.Equal(t, DynamicFeeTxType, got.Type)
+		assert.Equal(t, big.NewInt(2), got.MaxFeePerGas)
+		assert.Equal(t, big.NewInt(1), got.MaxPriorityFeePerGas)
+	})
+	t.Run("round trip with chain id and signature", func(t *testing.T) {
+		tx := NewTransaction().
+			SetType(DynamicFeeTxType).
+			SetChainID(1337).
+			SetMaxFeePerGas(big.NewInt(2)).
+			SetMaxPriorityFeePerGas(big.NewInt(1)).
+			SetSignature(SignatureFromVRS(big.NewInt(1), big.NewInt(2), big.NewInt(3)))
+		j, err := json.Marshal(tx)
+		require.NoError(t, err)
+		assert.Contains(t, string(j), `"chainId":"0x539"`)
+		assert.Contains(t, string(j), `"v":"0x1"`)
+		assert.Contains(t, string(j), `"yParity":"0x1"`)
+
+		var got Transaction
+		require.NoError(t, json.Unmarshal(j, &got))
+		assert.Equal(t, *tx, got)
+	})
+}
+
+func TestTransactionReceipt_JSON(t *testing.T) {
+	tests := []struct {
+		json string
+		want TransactionType
+	}{
+		{json: `{"type":"0x0"}`, want: LegacyTxType},
+		{json: `{"type":"0x1"}`, want: AccessListTxType},
+		{json: `{"type":"0x2"}`, want: DynamicFeeTxType},
+		{json: `{"type":"0x3"}`, want: BlobTxType},
+		{json: `{}`, want: LegacyTxType},
+	}
+	for n, tt := range tests {
+		t.Run(fmt.Sprintf("case-%d", n+1), func(t *testing.T) {
+			var receipt TransactionReceipt
+			require.NoError(t, json.Unmarshal([]byte(tt.json), &receipt))
+			assert.Equal(t, tt.want, receipt.Type)
+
+			// Type must survive a round trip.
+			data, err := json.Marshal(receipt)
+			require.NoError(t, err)
+			var decoded TransactionReceipt
+			require.NoError(t, json.Unmarshal(data, &decoded))
+			assert.Equal(t, tt.want, decoded.Type)
+		})
+	}
+}
+
+func TestFeeHistory(t *testing.T) {
+	var f FeeHistory
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"oldestBlock": "0x10",
+		"reward": [["0x1", "0x64"], ["0x3", "0xc8"], []],
+		"baseFeePerGas": ["0xa", "0xb", "0xc", "0xd"],
+		"gasUsedRatio": [0.5, 0.6, 0]
+	}`), &f))
+	f.RewardPercentiles = []float64{10, 90}
+
+	assert.Equal(t, big.NewInt(13), f.NextBaseFee())
+	assert.Equal(t, big.NewInt(2), f.SuggestTip(10))
+	assert.Equal(t, big.NewInt(150), f.SuggestTip(90))
+	assert.Nil(t, f.SuggestTip(50))
+
+	assert.Nil(t, FeeHistory{}.NextBaseFee())
+	assert.Nil(t, FeeHistory{RewardPercentiles: []float64{10}}.SuggestTip(10))
+}
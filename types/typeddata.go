@@ -0,0 +1,227 @@
+package types
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/defiweb/go-eth/hexutil"
+)
+
+// TypedData represents EIP-712 typed data, in the format used by the
+// eth_signTypedData_v4 method, e.g. by MetaMask.
+type TypedData struct {
+	// Types contains the definitions of the struct types used in the
+	// message. The EIP712Domain type may be included as well.
+	Types map[string][]TypedDataField
+
+	// PrimaryType is the name of the type of the message.
+	PrimaryType string
+
+	// Domain is the EIP-712 domain.
+	Domain TypedDataDomain
+
+	// Message is the message to sign. Numbers are stored as json.Number to
+	// preserve the precision of large integers.
+	Message map[string]any
+}
+
+// TypedDataField is a single field of a struct type in typed data.
+type TypedDataField struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
+
+// ParseTypedData parses typed data in the eth_signTypedData_v4 JSON format.
+//
+// It returns an error if the JSON is invalid or if the primary type is not
+// defined in the types.
+func ParseTypedData(data []byte) (*TypedData, error) {
+	td := &TypedData{}
+	if err := json.Unmarshal(data, td); err != nil {
+		return nil, err
+	}
+	if td.PrimaryType == "" {
+		return nil, errors.New("typed data: missing primary type")
+	}
+	if _, ok := td.Types[td.PrimaryType]; !ok {
+		return nil, fmt.Errorf("typed data: primary type %s is not defined", td.PrimaryType)
+	}
+	return td, nil
+}
+
+func (t TypedData) MarshalJSON() ([]byte, error) {
+	td := &jsonTypedData{
+		Types:       t.Types,
+		PrimaryType: t.PrimaryType,
+		Domain:      t.Domain,
+	}
+	if t.Message != nil {
+		msg, err := json.Marshal(t.Message)
+		if err != nil {
+			return nil, err
+		}
+		td.Message = msg
+	}
+	return json.Marshal(td)
+}
+
+func (t *TypedData) UnmarshalJSON(data []byte) error {
+	td := &jsonTypedData{}
+	if err := json.Unmarshal(data, td); err != nil {
+		return err
+	}
+	t.Types = td.Types
+	t.PrimaryType = td.PrimaryType
+	t.Domain = td.Domain
+	t.Message = nil
+	if len(td.Message) > 0 {
+		dec := json.NewDecoder(bytes.NewReader(td.Message))
+		dec.UseNumber()
+		if err := dec.Decode(&t.Message); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type jsonTypedData struct {
+	Types       map[string][]TypedDataField `json:"types"`
+	PrimaryType string                      `json:"primaryType"`
+	Domain      TypedDataDomain             `json:"domain"`
+	Message     json.RawMessage             `json:"message,omitempty"`
+}
+
+// TypedDataDomain represents the EIP-712 domain.
+//
+// All fields are optional. Fields with zero values are not included in the
+// EIP712Domain type and are not encoded.
+type TypedDataDomain struct {
+	Name              string   // Name is the user readable name of the signing domain.
+	Version           string   // Version is the current major version of the signing domain.
+	ChainID           *big.Int // ChainID is the EIP-155 chain ID.
+	VerifyingContract *Address // VerifyingContract is the address of the contract that will verify the signature.
+	Salt              *Hash    // Salt is a disambiguating salt for the protocol.
+}
+
+// Fields returns the fields of the EIP712Domain type, containing only the
+// fields that are set, in the order defined by EIP-712. It can be used as the
+// "EIP712Domain" entry of TypedData.Types.
+func (d *TypedDataDomain) Fields() []TypedDataField {
+	var fields []TypedDataField
+	if d.Name != "" {
+		fields = append(fields, TypedDataField{Name: "name", Type: "string"})
+	}
+	if d.Version != "" {
+		fields = append(fields, TypedDataField{Name: "version", Type: "string"})
+	}
+	if d.ChainID != nil {
+		fields = append(fields, TypedDataField{Name: "chainId", Type: "uint256"})
+	}
+	if d.VerifyingContract != nil {
+		fields = append(fields, TypedDataField{Name: "verifyingContract", Type: "address"})
+	}
+	if d.Salt != nil {
+		fields = append(fields, TypedDataField{Name: "salt", Type: "bytes32"})
+	}
+	return fields
+}
+
+// EncodeType returns the EIP-712 encoded EIP712Domain type, containing only
+// the fields that are set, e.g.:
+// "EIP712Domain(string name,string version,uint256 chainId)".
+func (d *TypedDataDomain) EncodeType() string {
+	fields := d.Fields()
+	encoded := make([]string, len(fields))
+	for i, f := range fields {
+		encoded[i] = f.Type + " " + f.Name
+	}
+	return "EIP712Domain(" + strings.Join(encoded, ",") + ")"
+}
+
+// Separator returns the EIP-712 domain separator, that is:
+// keccak256(typeHash || encodeData(domain)), where typeHash is the keccak256
+// hash of the encoded EIP712Domain type.
+//
+// The domain separator can be combined with a struct hash to compute the
+// digest to sign: keccak256("\x19\x01" || domainSeparator || structHash).
+func (d *TypedDataDomain) Separator() (Hash, error) {
+	if d.ChainID != nil && d.ChainID.Sign() < 0 {
+		return Hash{}, errors.New("typed data domain: negative chain ID")
+	}
+	if d.ChainID != nil && d.ChainID.BitLen() > 256 {
+		return Hash{}, errors.New("typed data domain: chain ID overflows uint256")
+	}
+	data := [][]byte{keccak256([]byte(d.EncodeType())).Bytes()}
+	if d.Name != "" {
+		data = append(data, keccak256([]byte(d.Name)).Bytes())
+	}
+	if d.Version != "" {
+		data = append(data, keccak256([]byte(d.Version)).Bytes())
+	}
+	if d.ChainID != nil {
+		data = append(data, bigToHash(d.ChainID).Bytes())
+	}
+	if d.VerifyingContract != nil {
+		data = append(data, MustHashFromBytes(d.VerifyingContract.Bytes(), PadLeft).Bytes())
+	}
+	if d.Salt != nil {
+		data = append(data, d.Salt.Bytes())
+	}
+	return keccak256(data...), nil
+}
+
+func (d TypedDataDomain) MarshalJSON() ([]byte, error) {
+	domain := &jsonTypedDataDomain{
+		Name:              d.Name,
+		Version:           d.Version,
+		VerifyingContract: d.VerifyingContract,
+		Salt:              d.Salt,
+	}
+	if d.ChainID != nil {
+		domain.ChainID = json.RawMessage(d.ChainID.String())
+	}
+	return json.Marshal(domain)
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface. The chain ID may
+// be a JSON number, a decimal string or a hex string prefixed with "0x".
+func (d *TypedDataDomain) UnmarshalJSON(data []byte) error {
+	domain := &jsonTypedDataDomain{}
+	if err := json.Unmarshal(data, domain); err != nil {
+		return err
+	}
+	d.Name = domain.Name
+	d.Version = domain.Version
+	d.VerifyingContract = domain.VerifyingContract
+	d.Salt = domain.Salt
+	d.ChainID = nil
+	if len(domain.ChainID) > 0 && !bytes.Equal(domain.ChainID, []byte("null")) {
+		var (
+			s       = string(naiveUnquote(domain.ChainID))
+			chainID *big.Int
+			ok      bool
+		)
+		if hexutil.Has0xPrefix(s) {
+			chainID, ok = new(big.Int).SetString(s[2:], 16)
+		} else {
+			chainID, ok = new(big.Int).SetString(s, 10)
+		}
+		if !ok || chainID.Sign() < 0 {
+			return fmt.Errorf("typed data domain: invalid chain ID %s", domain.ChainID)
+		}
+		d.ChainID = chainID
+	}
+	return nil
+}
+
+type jsonTypedDataDomain struct {
+	Name              string          `json:"name,omitempty"`
+	Version           string          `json:"version,omitempty"`
+	ChainID           json.RawMessage `json:"chainId,omitempty"`
+	VerifyingContract *Address        `json:"verifyingContract,omitempty"`
+	Salt              *Hash           `json:"salt,omitempty"`
+}
@@ -0,0 +1,157 @@
+package types
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_TypedDataDomain_Separator(t *testing.T) {
+	tests := []struct {
+		domain   TypedDataDomain
+		wantType string
+		want     Hash
+		wantErr  bool
+	}{
+		// Example from the EIP-712 specification:
+		{
+			domain: TypedDataDomain{
+				Name:              "Ether Mail",
+				Version:           "1",
+				ChainID:           big.NewInt(1),
+				VerifyingContract: MustAddressFromHexPtr("0xCcCCccccCCCCcCCCCCCcCcCccCcCCCcCcccccccC"),
+			},
+			wantType: "EIP712Domain(string name,string version,uint256 chainId,address verifyingContract)",
+			want:     MustHashFromHex("0xf2cee375fa42b42143804025fc449deafd50cc031ca257e0b194a650a912090f", PadNone),
+		},
+		{
+			domain:   TypedDataDomain{},
+			wantType: "EIP712Domain()",
+			want:     keccak256(keccak256([]byte("EIP712Domain()")).Bytes()),
+		},
+		{
+			domain: TypedDataDomain{
+				ChainID: big.NewInt(1),
+				Salt:    MustHashFromHexPtr("0x01", PadLeft),
+			},
+			wantType: "EIP712Domain(uint256 chainId,bytes32 salt)",
+			want: keccak256(
+				keccak256([]byte("EIP712Domain(uint256 chainId,bytes32 salt)")).Bytes(),
+				MustHashFromHex("0x01", PadLeft).Bytes(),
+				MustHashFromHex("0x01", PadLeft).Bytes(),
+			),
+		},
+		{
+			domain:  TypedDataDomain{ChainID: big.NewInt(-1)},
+			wantErr: true,
+		},
+	}
+	for n, tt := range tests {
+		t.Run(fmt.Sprintf("case-%d", n+1), func(t *testing.T) {
+			sep, err := tt.domain.Separator()
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantType, tt.domain.EncodeType())
+			assert.Equal(t, tt.want, sep)
+		})
+	}
+}
+
+const mailTypedDataJSON = `{
+	"types": {
+		"EIP712Domain": [
+			{"name": "name", "type": "string"},
+			{"name": "version", "type": "string"},
+			{"name": "chainId", "type": "uint256"},
+			{"name": "verifyingContract", "type": "address"}
+		],
+		"Person": [
+			{"name": "name", "type": "string"},
+			{"name": "wallet", "type": "address"}
+		],
+		"Mail": [
+			{"name": "from", "type": "Person"},
+			{"name": "to", "type": "Person"},
+			{"name": "contents", "type": "string"},
+			{"name": "amount", "type": "uint256"}
+		]
+	},
+	"primaryType": "Mail",
+	"domain": {
+		"name": "Ether Mail",
+		"version": "1",
+		"chainId": 1,
+		"verifyingContract": "0xcccccccccccccccccccccccccccccccccccccccc"
+	},
+	"message": {
+		"from": {"name": "Cow", "wallet": "0xcd2a3d9f938e13cd947ec05abc7fe734df8dd826"},
+		"to": {"name": "Bob", "wallet": "0xbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb"},
+		"contents": "Hello, Bob!",
+		"amount": 115792089237316195423570985008687907853269984665640564039457584007913129639935
+	}
+}`
+
+func Test_ParseTypedData(t *testing.T) {
+	td, err := ParseTypedData([]byte(mailTypedDataJSON))
+	require.NoError(t, err)
+
+	assert.Equal(t, "Mail", td.PrimaryType)
+	assert.Len(t, td.Types, 3)
+	assert.Equal(t, []TypedDataField{{Name: "name", Type: "string"}, {Name: "wallet", Type: "address"}}, td.Types["Person"])
+	assert.Equal(t, "Ether Mail", td.Domain.Name)
+	assert.Equal(t, big.NewInt(1), td.Domain.ChainID)
+	assert.Equal(t, "Hello, Bob!", td.Message["contents"])
+	assert.Equal(t, json.Number("115792089237316195423570985008687907853269984665640564039457584007913129639935"), td.Message["amount"])
+
+	sep, err := td.Domain.Separator()
+	require.NoError(t, err)
+	assert.Equal(t, MustHashFromHex("0xf2cee375fa42b42143804025fc449deafd50cc031ca257e0b194a650a912090f", PadNone), sep)
+
+	// Round trip:
+	j, err := json.Marshal(td)
+	require.NoError(t, err)
+	assert.JSONEq(t, mailTypedDataJSON, string(j))
+}
+
+func Test_ParseTypedData_Invalid(t *testing.T) {
+	tests := []string{
+		`{`,
+		`{"types": {"Mail": []}}`,
+		`{"types": {"Mail": []}, "primaryType": "Person"}`,
+		`{"types": {"Mail": []}, "primaryType": "Mail", "domain": {"chainId": "foo"}}`,
+		`{"types": {"Mail": []}, "primaryType": "Mail", "domain": {"chainId": -1}}`,
+	}
+	for n, tt := range tests {
+		t.Run(fmt.Sprintf("case-%d", n+1), func(t *testing.T) {
+			_, err := ParseTypedData([]byte(tt))
+			assert.Error(t, err)
+		})
+	}
+}
+
+func Test_TypedDataDomain_UnmarshalJSON_ChainID(t *testing.T) {
+	tests := []struct {
+		arg  string
+		want *big.Int
+	}{
+		{arg: `{"chainId": 10}`, want: big.NewInt(10)},
+		{arg: `{"chainId": "10"}`, want: big.NewInt(10)},
+		{arg: `{"chainId": "0xa"}`, want: big.NewInt(10)},
+		{arg: `{"chainId": null}`, want: nil},
+		{arg: `{}`, want: nil},
+	}
+	for n, tt := range tests {
+		t.Run(fmt.Sprintf("case-%d", n+1), func(t *testing.T) {
+			var d TypedDataDomain
+			require.NoError(t, json.Unmarshal([]byte(tt.arg), &d))
+			assert.Equal(t, tt.want, d.ChainID)
+		})
+	}
+}
@@ -1,7 +1,9 @@
 package types
 
 import (
+	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"math"
 	"math/big"
@@ -43,6 +45,21 @@ func AddressFromHex(h string) (a Address, err error) {
 	return a, err
 }
 
+// AddressFromHexChecked parses an address in hex format and returns an
+// Address type. If the address is in mixed case, it must have a valid
+// EIP-55 checksum. All lowercase and all uppercase addresses are accepted
+// without checksum validation.
+func AddressFromHexChecked(h string) (Address, error) {
+	a, err := AddressFromHex(h)
+	if err != nil {
+		return a, err
+	}
+	if isMixedCase(h) && !a.ValidChecksum(h) {
+		return Address{}, fmt.Errorf("invalid address checksum %s", h)
+	}
+	return a, nil
+}
+
 // AddressFromHexPtr parses an address in hex format and returns an *Address type.
 // It returns nil if the address is invalid.
 func AddressFromHexPtr(h string) *Address {
@@ -136,11 +153,26 @@ func (t Address) Checksum(h HashFunc) string {
 	return "0x" + string(hex)
 }
 
+// ValidChecksum returns true if the given hex string is the address with
+// a valid EIP-55 checksum. The hex string may be prefixed with "0x".
+func (t Address) ValidChecksum(h string) bool {
+	if hexutil.Has0xPrefix(h) {
+		h = h[2:]
+	}
+	return h == t.Checksum(keccak256)[2:]
+}
+
 // IsZero returns true if the address is the zero address.
 func (t Address) IsZero() bool {
 	return t == ZeroAddress
 }
 
+// Cmp compares two addresses byte-wise. It returns -1 if t < x, 0 if t == x
+// and +1 if t > x.
+func (t Address) Cmp(x Address) int {
+	return bytes.Compare(t[:], x[:])
+}
+
 func (t Address) MarshalJSON() ([]byte, error) {
 	return bytesMarshalJSON(t[:]), nil
 }
@@ -181,6 +213,41 @@ func (t *Address) DecodeRLP(data []byte) (int, error) {
 	return n, nil
 }
 
+// CreateAddress calculates the address of a contract created by the sender
+// with the given nonce using the CREATE opcode.
+//
+// The address is calculated as keccak256(rlp([sender, nonce]))[12:].
+func CreateAddress(sender Address, nonce uint64) Address {
+	b, err := rlp.NewList(rlp.NewBytes(sender[:]), rlp.NewUint(nonce)).EncodeRLP()
+	if err != nil {
+		// Encoding a list of bytes and an uint cannot fail.
+		panic(err)
+	}
+	var a Address
+	copy(a[:], keccak256(b).Bytes()[12:])
+	return a
+}
+
+// Create2Address calculates the address of a contract created by the sender
+// with the given salt and init code hash using the CREATE2 opcode, as defined
+// in EIP-1014.
+//
+// The address is calculated as
+// keccak256(0xff ++ sender ++ salt ++ initCodeHash)[12:].
+func Create2Address(sender Address, salt Hash, initCodeHash Hash) Address {
+	var a Address
+	copy(a[:], keccak256([]byte{0xff}, sender[:], salt[:], initCodeHash[:]).Bytes()[12:])
+	return a
+}
+
+// AddressSlice attaches the methods of sort.Interface to []Address, sorting
+// in increasing byte-wise order.
+type AddressSlice []Address
+
+func (s AddressSlice) Len() int           { return len(s) }
+func (s AddressSlice) Less(i, j int) bool { return s[i].Cmp(s[j]) < 0 }
+func (s AddressSlice) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
+
 //
 // Hash type:
 //
@@ -349,6 +416,51 @@ func (t Hash) IsZero() bool {
 	return t == ZeroHash
 }
 
+// Cmp compares two hashes byte-wise. It returns -1 if t < x, 0 if t == x
+// and +1 if t > x.
+func (t Hash) Cmp(x Hash) int {
+	return bytes.Compare(t[:], x[:])
+}
+
+// Uint256 interprets the hash as a big-endian unsigned 256-bit integer.
+func (t Hash) Uint256() *big.Int {
+	return new(big.Int).SetBytes(t[:])
+}
+
+// BigIntSigned interprets the lowest bits of the hash as a big-endian signed
+// integer in two's complement representation. Higher bits are ignored, which
+// allows reading signed values packed into storage slots. It is the inverse
+// of HashFromBigInt for negative numbers when bits is 256.
+//
+// It panics if bits is not in the range 1-256.
+func (t Hash) BigIntSigned(bits int) *big.Int {
+	if bits < 1 || bits > HashLength*8 {
+		panic(fmt.Sprintf("invalid bit size %d", bits))
+	}
+	one := big.NewInt(1)
+	mask := new(big.Int).Sub(new(big.Int).Lsh(one, uint(bits)), one)
+	x := new(big.Int).SetBytes(t[:])
+	x.And(x, mask)
+	if x.Bit(bits-1) == 1 {
+		x.Sub(x, new(big.Int).Lsh(one, uint(bits)))
+	}
+	return x
+}
+
+// Address returns the last 20 bytes of the hash as an address.
+//
+// This is how addresses are stored in a 32-byte storage slot or ABI word.
+func (t Hash) Address() Address {
+	var a Address
+	copy(a[:], t[HashLength-AddressLength:])
+	return a
+}
+
+// Bool returns true if the hash is not zero.
+func (t Hash) Bool() bool {
+	return !t.IsZero()
+}
+
 func (t Hash) MarshalJSON() ([]byte, error) {
 	return bytesMarshalJSON(t[:]), nil
 }
@@ -385,11 +497,23 @@ func (t *Hash) DecodeRLP(data []byte) (int, error) {
 	return n, nil
 }
 
+// HashSlice attaches the methods of sort.Interface to []Hash, sorting in
+// increasing byte-wise order.
+type HashSlice []Hash
+
+func (s HashSlice) Len() int           { return len(s) }
+func (s HashSlice) Less(i, j int) bool { return s[i].Cmp(s[j]) < 0 }
+func (s HashSlice) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
+
 //
 // BlockNumber type:
 //
 
 // BlockNumber is a type that can hold a block number or a tag.
+//
+// Tags are not numeric. They are stored internally as negative numbers, so
+// arithmetic on a tag would yield a meaningless result. Use IsTag to check
+// whether the value is a tag, and Add to safely compute block numbers.
 type BlockNumber struct{ x big.Int }
 
 const (
@@ -411,6 +535,7 @@ var (
 // BlockNumberFromHex converts a string to a BlockNumber type.
 // The string can be a hex number or one of the following strings:
 // "earliest", "latest", "safe", "finalized", "pending".
+// Negative numbers are not allowed, because block numbers cannot be negative.
 // If the string is not a valid block number, it returns an error.
 func BlockNumberFromHex(h string) (BlockNumber, error) {
 	b := &BlockNumber{}
@@ -506,7 +631,24 @@ func (t *BlockNumber) IsTag() bool {
 	return t.Big().Sign() < 0
 }
 
+// Add returns the block number increased by n. The n may be negative.
+//
+// It returns an error if the block number is a tag or if the result would be
+// negative.
+func (t *BlockNumber) Add(n int64) (BlockNumber, error) {
+	if t.IsTag() {
+		return BlockNumber{}, fmt.Errorf("cannot add to %s block tag", t.String())
+	}
+	x := new(big.Int).Add(&t.x, big.NewInt(n))
+	if x.Sign() < 0 {
+		return BlockNumber{}, fmt.Errorf("block number %s is negative", x.String())
+	}
+	return BlockNumber{x: *x}, nil
+}
+
 // Big returns the big.Int representation of the block number.
+//
+// For tags, the internal negative representation is returned.
 func (t *BlockNumber) Big() *big.Int {
 	return new(big.Int).Set(&t.x)
 }
@@ -580,6 +722,9 @@ func (t *BlockNumber) UnmarshalText(input []byte) error {
 		if err != nil {
 			return err
 		}
+		if u.Sign() < 0 {
+			return fmt.Errorf("block number cannot be negative")
+		}
 		if u.Cmp(big.NewInt(math.MaxInt64)) > 0 {
 			return fmt.Errorf("block number larger than int64")
 		}
@@ -592,6 +737,14 @@ func (t *BlockNumber) UnmarshalText(input []byte) error {
 // Signature type:
 //
 
+var (
+	// secp256k1N is the order of the secp256k1 curve.
+	secp256k1N, _ = new(big.Int).SetString("fffffffffffffffffffffffffffffffebaaedce6af48a03bbfd25e8cd0364141", 16)
+
+	// secp256k1HalfN is the half of the order of the secp256k1 curve.
+	secp256k1HalfN = new(big.Int).Rsh(secp256k1N, 1)
+)
+
 // Signature represents the transaction signature.
 type Signature struct {
 	V *big.Int
@@ -774,6 +927,72 @@ func (s Signature) Equal(c Signature) bool {
 	return sv.Cmp(cv) == 0 && sr.Cmp(cr) == 0 && ss.Cmp(cs) == 0
 }
 
+// Validate checks if the signature is valid and not malleable.
+//
+// R and S must be in the range [1, n-1], where n is the order of the
+// secp256k1 curve, and S must be in the lower half of the range, as required
+// by EIP-2. V must be 0, 1, 27, 28, or at least 35 for EIP-155 signatures.
+func (s Signature) Validate() error {
+	if s.V == nil || s.R == nil || s.S == nil {
+		return errors.New("invalid signature: missing V, R or S value")
+	}
+	if s.R.Sign() <= 0 || s.R.Cmp(secp256k1N) >= 0 {
+		return errors.New("invalid signature: R is out of range")
+	}
+	if s.S.Sign() <= 0 || s.S.Cmp(secp256k1N) >= 0 {
+		return errors.New("invalid signature: S is out of range")
+	}
+	if s.S.Cmp(secp256k1HalfN) > 0 {
+		return errors.New("invalid signature: S is in the upper half of the range")
+	}
+	if !s.V.IsUint64() {
+		return errors.New("invalid signature: V is out of range")
+	}
+	switch v := s.V.Uint64(); {
+	case v == 0, v == 1, v == 27, v == 28, v >= 35:
+	default:
+		return fmt.Errorf("invalid signature: invalid V value %d", v)
+	}
+	return nil
+}
+
+// VRS returns the V, R and S values of the signature in the format commonly
+// used by web frontends and Solidity's ecrecover function.
+//
+// The V value is normalized to 27 or 28, regardless of whether the signature
+// uses 0/1, 27/28 or EIP-155 V values. R and S are returned as 32-byte
+// big-endian values.
+func (s Signature) VRS() (uint64, Hash, Hash) {
+	return 27 + s.recoveryID(), bigToHash(s.R), bigToHash(s.S)
+}
+
+// EIP155V returns the V value of the signature encoded as specified in
+// EIP-155 for the given chain ID, that is, recoveryID + chainID * 2 + 35.
+func (s Signature) EIP155V(chainID uint64) *big.Int {
+	v := new(big.Int).SetUint64(chainID)
+	v.Mul(v, big.NewInt(2))
+	v.Add(v, big.NewInt(35))
+	v.Add(v, new(big.Int).SetUint64(s.recoveryID()))
+	return v
+}
+
+// recoveryID returns the recovery ID (0 or 1) of the signature. It supports
+// V values in the 0/1, 27/28 and EIP-155 formats.
+func (s Signature) recoveryID() uint64 {
+	if s.V == nil {
+		return 0
+	}
+	v := s.V
+	switch {
+	case v.Cmp(big.NewInt(35)) >= 0:
+		return new(big.Int).Mod(new(big.Int).Sub(v, big.NewInt(35)), big.NewInt(2)).Uint64()
+	case v.Cmp(big.NewInt(27)) >= 0:
+		return new(big.Int).Sub(v, big.NewInt(27)).Uint64() & 1
+	default:
+		return v.Uint64() & 1
+	}
+}
+
 func (s Signature) Copy() *Signature {
 	cpy := &Signature{}
 	if s.V != nil {
@@ -829,6 +1048,9 @@ func (s *Signature) UnmarshalText(input []byte) error {
 // Number represents a hex-encoded number. This type is used for marshaling
 // and unmarshalling JSON numbers. When possible, use big.Int or regular integers
 // instead.
+//
+// Unlike BlockNumber, Number may be negative. Negative numbers are encoded
+// with a minus sign before the "0x" prefix, e.g. "-0x1".
 type Number struct{ x big.Int }
 
 // NumberFromHex converts a hex string to a Number type.
@@ -945,7 +1167,19 @@ func (t *Number) UnmarshalText(input []byte) error {
 type Bytes []byte
 
 // BytesFromHex converts a hex string to a Bytes type.
+//
+// The "0x" prefix is optional. The input must contain an even number of hex
+// digits, except for "0x0", which is decoded as a single zero byte.
 func BytesFromHex(h string) (Bytes, error) {
+	d := h
+	if hexutil.Has0xPrefix(d) {
+		d = d[2:]
+	}
+	for i := 0; i < len(d); i++ {
+		if !hexutil.IsHexDigit(d[i]) {
+			return nil, fmt.Errorf("invalid hex string %q, invalid character %q", h, d[i])
+		}
+	}
 	return hexutil.HexToBytes(h)
 }
 
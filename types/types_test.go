@@ -2,12 +2,15 @@ package types
 
 import (
 	"fmt"
+	"math"
 	"math/big"
+	"sort"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
-	"golang.org/x/crypto/sha3"
+
+	"github.com/defiweb/go-eth/hexutil"
 )
 
 func Test_AddressType_Unmarshal(t *testing.T) {
@@ -74,6 +77,42 @@ func Test_AddressType_Marshal(t *testing.T) {
 	}
 }
 
+func Test_AddressFromHexChecked(t *testing.T) {
+	tests := []struct {
+		addr    string
+		wantErr bool
+	}{
+		{addr: "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed"},
+		{addr: "0xfB6916095ca1df60bB79Ce92cE3Ea74c37c5d359"},
+		{addr: "fB6916095ca1df60bB79Ce92cE3Ea74c37c5d359"},
+		{addr: "0x5aaeb6053f3e94c9b9a09f33669435e7ef1beaed"},
+		{addr: "0x5AAEB6053F3E94C9B9A09F33669435E7EF1BEAED"},
+		{addr: "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAeD", wantErr: true},
+		{addr: "0x5AAeb6053F3E94C9b9A09f33669435E7Ef1BeAed", wantErr: true},
+		{addr: "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeA", wantErr: true},
+	}
+	for n, tt := range tests {
+		t.Run(fmt.Sprintf("case-%d", n+1), func(t *testing.T) {
+			a, err := AddressFromHexChecked(tt.addr)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, MustAddressFromHex(tt.addr), a)
+		})
+	}
+}
+
+func Test_AddressType_ValidChecksum(t *testing.T) {
+	a := MustAddressFromHex("0x5aaeb6053f3e94c9b9a09f33669435e7ef1beaed")
+	assert.True(t, a.ValidChecksum("0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed"))
+	assert.True(t, a.ValidChecksum("5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed"))
+	assert.False(t, a.ValidChecksum("0x5aaeb6053f3e94c9b9a09f33669435e7ef1beaed"))
+	assert.False(t, a.ValidChecksum("0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAeD"))
+	assert.False(t, a.ValidChecksum("0xfB6916095ca1df60bB79Ce92cE3Ea74c37c5d359"))
+}
+
 func Test_AddressType_Checksum(t *testing.T) {
 	tests := []struct {
 		addr string
@@ -91,6 +130,72 @@ func Test_AddressType_Checksum(t *testing.T) {
 	}
 }
 
+func Test_CreateAddress(t *testing.T) {
+	tests := []struct {
+		sender string
+		nonce  uint64
+		want   string
+	}{
+		{sender: "0x6ac7ea33f8831ea9dcc53393aaa88b25a785dbf0", nonce: 0, want: "0xcd234a471b72ba2f1ccf0a70fcaba648a5eecd8d"},
+		{sender: "0x6ac7ea33f8831ea9dcc53393aaa88b25a785dbf0", nonce: 1, want: "0x343c43a37d37dff08ae8c4a11544c718abb4fcf8"},
+		{sender: "0x6ac7ea33f8831ea9dcc53393aaa88b25a785dbf0", nonce: 2, want: "0xf778b86fa74e846c4f0a1fbd1335fe81c00a0c91"},
+		{sender: "0x6ac7ea33f8831ea9dcc53393aaa88b25a785dbf0", nonce: 3, want: "0xfffd933a0bc612844eaf0c6fe3e5b8e9b6c1d19c"},
+	}
+	for n, tt := range tests {
+		t.Run(fmt.Sprintf("case-%d", n+1), func(t *testing.T) {
+			assert.Equal(t, MustAddressFromHex(tt.want), CreateAddress(MustAddressFromHex(tt.sender), tt.nonce))
+		})
+	}
+}
+
+func Test_Create2Address(t *testing.T) {
+	// Test vectors from EIP-1014.
+	tests := []struct {
+		sender   string
+		salt     string
+		initCode string
+		want     string
+	}{
+		{
+			sender:   "0x0000000000000000000000000000000000000000",
+			salt:     "0x0000000000000000000000000000000000000000000000000000000000000000",
+			initCode: "0x00",
+			want:     "0x4D1A2e2bB4F88F0250f26Ffff098B0b30B26BF38",
+		},
+		{
+			sender:   "0xdeadbeef00000000000000000000000000000000",
+			salt:     "0x0000000000000000000000000000000000000000000000000000000000000000",
+			initCode: "0x00",
+			want:     "0xB928f69Bb1D91Cd65274e3c79d8986362984fDA3",
+		},
+		{
+			sender:   "0xdeadbeef00000000000000000000000000000000",
+			salt:     "0x000000000000000000000000feed000000000000000000000000000000000000",
+			initCode: "0x00",
+			want:     "0xD04116cDd17beBE565EB2422F2497E06cC1C9833",
+		},
+		{
+			sender:   "0x00000000000000000000000000000000deadbeef",
+			salt:     "0x00000000000000000000000000000000000000000000000000000000cafebabe",
+			initCode: "0xdeadbeefdeadbeefdeadbeefdeadbeefdeadbeefdeadbeefdeadbeefdeadbeefdeadbeefdeadbeefdeadbeef",
+			want:     "0x1d8bfDC5D46DC4f61D6b6115972536eBE6A8854C",
+		},
+		{
+			sender:   "0x0000000000000000000000000000000000000000",
+			salt:     "0x0000000000000000000000000000000000000000000000000000000000000000",
+			initCode: "0x",
+			want:     "0xE33C0C7F7df4809055C3ebA6c09CFe4BaF1BD9e0",
+		},
+	}
+	for n, tt := range tests {
+		t.Run(fmt.Sprintf("case-%d", n+1), func(t *testing.T) {
+			initCodeHash := keccak256(MustBytesFromHex(tt.initCode))
+			got := Create2Address(MustAddressFromHex(tt.sender), MustHashFromHex(tt.salt, PadNone), initCodeHash)
+			assert.Equal(t, MustAddressFromHex(tt.want), got)
+		})
+	}
+}
+
 func Test_hashType_Unmarshal(t *testing.T) {
 	tests := []struct {
 		arg     string
@@ -155,6 +260,78 @@ func Test_hashType_Marshal(t *testing.T) {
 	}
 }
 
+func Test_hashType_Decoders(t *testing.T) {
+	tests := []struct {
+		hash        string
+		wantUint256 *big.Int
+		wantAddress Address
+		wantBool    bool
+	}{
+		{
+			hash:        "0x0000000000000000000000000000000000000000000000000000000000000000",
+			wantUint256: big.NewInt(0),
+			wantAddress: ZeroAddress,
+			wantBool:    false,
+		},
+		{
+			hash:        "0x0000000000000000000000000000000000000000000000000000000000000001",
+			wantUint256: big.NewInt(1),
+			wantAddress: MustAddressFromHex("0x0000000000000000000000000000000000000001"),
+			wantBool:    true,
+		},
+		{
+			hash:        "0xffffffffffffffffffffffff1111111111111111111111111111111111111111",
+			wantUint256: new(big.Int).SetBytes(hexutil.MustHexToBytes("0xffffffffffffffffffffffff1111111111111111111111111111111111111111")),
+			wantAddress: MustAddressFromHex("0x1111111111111111111111111111111111111111"),
+			wantBool:    true,
+		},
+	}
+	for n, tt := range tests {
+		t.Run(fmt.Sprintf("case-%d", n+1), func(t *testing.T) {
+			h := MustHashFromHex(tt.hash, PadNone)
+			assert.Equal(t, 0, tt.wantUint256.Cmp(h.Uint256()))
+			assert.Equal(t, tt.wantAddress, h.Address())
+			assert.Equal(t, tt.wantBool, h.Bool())
+		})
+	}
+}
+
+func Test_hashType_BigIntSigned(t *testing.T) {
+	tests := []struct {
+		hash string
+		bits int
+		want *big.Int
+	}{
+		{hash: "0x0000000000000000000000000000000000000000000000000000000000000000", bits: 256, want: big.NewInt(0)},
+		{hash: "0x000000000000000000000000000000000000000000000000000000000000002a", bits: 256, want: big.NewInt(42)},
+		{hash: "0xffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff", bits: 256, want: big.NewInt(-1)},
+		{hash: "0x8000000000000000000000000000000000000000000000000000000000000000", bits: 256, want: new(big.Int).Neg(new(big.Int).Lsh(big.NewInt(1), 255))},
+		{hash: "0x00000000000000000000000000000000000000000000000000000000000000ff", bits: 8, want: big.NewInt(-1)},
+		{hash: "0x000000000000000000000000000000000000000000000000000000000000007f", bits: 8, want: big.NewInt(127)},
+		{hash: "0x0000000000000000000000000000000000000000000000000000000000000080", bits: 8, want: big.NewInt(-128)},
+		{hash: "0x0000000000000000000000000000000000000000000000000000000000000080", bits: 16, want: big.NewInt(128)},
+		// Higher bits, e.g. other values packed in the same slot, are ignored.
+		{hash: "0x1111111111111111111111111111111111111111111111111111111111fffffe", bits: 24, want: big.NewInt(-2)},
+		{hash: "0x0000000000000000000000000000000000000000000000000000000000000003", bits: 2, want: big.NewInt(-1)},
+	}
+	for n, tt := range tests {
+		t.Run(fmt.Sprintf("case-%d", n+1), func(t *testing.T) {
+			assert.Equal(t, tt.want.String(), MustHashFromHex(tt.hash, PadNone).BigIntSigned(tt.bits).String())
+		})
+	}
+	t.Run("round-trip", func(t *testing.T) {
+		for _, x := range []int64{0, 1, -1, 42, -42, math.MinInt64, math.MaxInt64} {
+			h, err := HashFromBigInt(big.NewInt(x))
+			require.NoError(t, err)
+			assert.Equal(t, big.NewInt(x).String(), h.BigIntSigned(256).String())
+		}
+	})
+	t.Run("invalid-bits", func(t *testing.T) {
+		assert.Panics(t, func() { Hash{}.BigIntSigned(0) })
+		assert.Panics(t, func() { Hash{}.BigIntSigned(257) })
+	})
+}
+
 func Test_hashesType_Unmarshal(t *testing.T) {
 	tests := []struct {
 		arg     string
@@ -338,6 +515,8 @@ func Test_BlockNumberType_Unmarshal(t *testing.T) {
 		{arg: `"finalized"`, want: FinalizedBlockNumber, isTag: true, isFinalized: true},
 		{arg: `"foo"`, wantErr: true},
 		{arg: `"0xZ"`, wantErr: true},
+		{arg: `"-0x1"`, wantErr: true},
+		{arg: `"-1"`, wantErr: true},
 	}
 	for n, tt := range tests {
 		t.Run(fmt.Sprintf("case-%d", n+1), func(t *testing.T) {
@@ -359,6 +538,36 @@ func Test_BlockNumberType_Unmarshal(t *testing.T) {
 	}
 }
 
+func Test_BlockNumberFromHex_Negative(t *testing.T) {
+	_, err := BlockNumberFromHex("-0x1")
+	assert.Error(t, err)
+	assert.Nil(t, BlockNumberFromHexPtr("-0x1"))
+	assert.Panics(t, func() { MustBlockNumberFromHex("-0x1") })
+}
+
+func Test_NumberType_Negative(t *testing.T) {
+	tests := []struct {
+		arg  string
+		want *big.Int
+	}{
+		{arg: `"-0x1"`, want: big.NewInt(-1)},
+		{arg: `"-0xff"`, want: big.NewInt(-255)},
+		{arg: `"0x0"`, want: big.NewInt(0)},
+	}
+	for n, tt := range tests {
+		t.Run(fmt.Sprintf("case-%d", n+1), func(t *testing.T) {
+			v := &Number{}
+			require.NoError(t, v.UnmarshalJSON([]byte(tt.arg)))
+			assert.Equal(t, tt.want, v.Big())
+			j, err := v.MarshalJSON()
+			require.NoError(t, err)
+			assert.Equal(t, tt.arg, string(j))
+			h := MustNumberFromHex(string(naiveUnquote([]byte(tt.arg))))
+			assert.Equal(t, tt.want, h.Big())
+		})
+	}
+}
+
 func Test_BlockNumberType_Marshal(t *testing.T) {
 	tests := []struct {
 		arg  BlockNumber
@@ -381,6 +590,35 @@ func Test_BlockNumberType_Marshal(t *testing.T) {
 	}
 }
 
+func Test_BlockNumberType_Add(t *testing.T) {
+	tests := []struct {
+		arg     BlockNumber
+		n       int64
+		want    BlockNumber
+		wantErr bool
+	}{
+		{arg: BlockNumberFromUint64(10), n: 5, want: BlockNumberFromUint64(15)},
+		{arg: BlockNumberFromUint64(10), n: -10, want: BlockNumberFromUint64(0)},
+		{arg: BlockNumberFromUint64(10), n: -11, wantErr: true},
+		{arg: EarliestBlockNumber, n: 1, wantErr: true},
+		{arg: LatestBlockNumber, n: 1, wantErr: true},
+		{arg: PendingBlockNumber, n: -1, wantErr: true},
+		{arg: SafeBlockNumber, n: 0, wantErr: true},
+		{arg: FinalizedBlockNumber, n: 1, wantErr: true},
+	}
+	for n, tt := range tests {
+		t.Run(fmt.Sprintf("case-%d", n+1), func(t *testing.T) {
+			got, err := tt.arg.Add(tt.n)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want.String(), got.String())
+			}
+		})
+	}
+}
+
 func Test_SignatureType_Unmarshal(t *testing.T) {
 	tests := []struct {
 		arg     string
@@ -528,6 +766,86 @@ func Test_SignatureType_Equal(t *testing.T) {
 	}
 }
 
+func Test_SignatureType_Validate(t *testing.T) {
+	n, _ := new(big.Int).SetString("fffffffffffffffffffffffffffffffebaaedce6af48a03bbfd25e8cd0364141", 16)
+	halfN := new(big.Int).Rsh(n, 1)
+	tests := []struct {
+		sig     Signature
+		wantErr bool
+	}{
+		{sig: SignatureFromVRS(big.NewInt(27), big.NewInt(1), big.NewInt(1))},
+		{sig: SignatureFromVRS(big.NewInt(28), big.NewInt(1), big.NewInt(1))},
+		{sig: SignatureFromVRS(big.NewInt(0), big.NewInt(1), big.NewInt(1))},
+		{sig: SignatureFromVRS(big.NewInt(1), big.NewInt(1), big.NewInt(1))},
+		{sig: SignatureFromVRS(big.NewInt(37), big.NewInt(1), big.NewInt(1))},
+		{sig: SignatureFromVRS(big.NewInt(27), new(big.Int).Sub(n, big.NewInt(1)), halfN)},
+		{sig: SignatureFromVRS(big.NewInt(27), big.NewInt(0), big.NewInt(1)), wantErr: true},
+		{sig: SignatureFromVRS(big.NewInt(27), big.NewInt(1), big.NewInt(0)), wantErr: true},
+		{sig: SignatureFromVRS(big.NewInt(27), n, big.NewInt(1)), wantErr: true},
+		{sig: SignatureFromVRS(big.NewInt(27), big.NewInt(1), new(big.Int).Add(halfN, big.NewInt(1))), wantErr: true},
+		{sig: SignatureFromVRS(big.NewInt(27), big.NewInt(-1), big.NewInt(1)), wantErr: true},
+		{sig: SignatureFromVRS(big.NewInt(2), big.NewInt(1), big.NewInt(1)), wantErr: true},
+		{sig: SignatureFromVRS(big.NewInt(29), big.NewInt(1), big.NewInt(1)), wantErr: true},
+		{sig: SignatureFromVRS(nil, big.NewInt(1), big.NewInt(1)), wantErr: true},
+	}
+	for n, tt := range tests {
+		t.Run(fmt.Sprintf("case-%d", n+1), func(t *testing.T) {
+			err := tt.sig.Validate()
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func Test_SignatureType_VRS(t *testing.T) {
+	r := big.NewInt(1)
+	s := big.NewInt(2)
+	tests := []struct {
+		v     *big.Int
+		wantV uint64
+	}{
+		{v: big.NewInt(0), wantV: 27},
+		{v: big.NewInt(1), wantV: 28},
+		{v: big.NewInt(27), wantV: 27},
+		{v: big.NewInt(28), wantV: 28},
+		{v: big.NewInt(37), wantV: 27},   // chain ID 1
+		{v: big.NewInt(38), wantV: 28},   // chain ID 1
+		{v: big.NewInt(2709), wantV: 27}, // chain ID 1337
+		{v: nil, wantV: 27},
+	}
+	for n, tt := range tests {
+		t.Run(fmt.Sprintf("case-%d", n+1), func(t *testing.T) {
+			v, gotR, gotS := SignatureFromVRS(tt.v, r, s).VRS()
+			assert.Equal(t, tt.wantV, v)
+			assert.Equal(t, "0x0000000000000000000000000000000000000000000000000000000000000001", gotR.String())
+			assert.Equal(t, "0x0000000000000000000000000000000000000000000000000000000000000002", gotS.String())
+		})
+	}
+}
+
+func Test_SignatureType_EIP155V(t *testing.T) {
+	tests := []struct {
+		v       *big.Int
+		chainID uint64
+		want    *big.Int
+	}{
+		{v: big.NewInt(0), chainID: 1, want: big.NewInt(37)},
+		{v: big.NewInt(1), chainID: 1, want: big.NewInt(38)},
+		{v: big.NewInt(27), chainID: 1337, want: big.NewInt(2709)},
+		{v: big.NewInt(28), chainID: 1337, want: big.NewInt(2710)},
+		{v: big.NewInt(38), chainID: 5, want: big.NewInt(46)},
+	}
+	for n, tt := range tests {
+		t.Run(fmt.Sprintf("case-%d", n+1), func(t *testing.T) {
+			got := SignatureFromVRS(tt.v, big.NewInt(1), big.NewInt(1)).EIP155V(tt.chainID)
+			assert.Equal(t, tt.want.String(), got.String())
+		})
+	}
+}
+
 func Test_BytesType_Unmarshal(t *testing.T) {
 	tests := []struct {
 		arg     string
@@ -556,6 +874,37 @@ func Test_BytesType_Unmarshal(t *testing.T) {
 	}
 }
 
+func Test_BytesFromHex(t *testing.T) {
+	tests := []struct {
+		arg     string
+		want    Bytes
+		wantErr bool
+	}{
+		{arg: "0xDEADBEEF", want: Bytes{0xDE, 0xAD, 0xBE, 0xEF}},
+		{arg: "0Xdeadbeef", want: Bytes{0xDE, 0xAD, 0xBE, 0xEF}},
+		{arg: "0x", want: Bytes{}},
+		{arg: "", want: Bytes{}},
+		{arg: "DEADBEEF", want: Bytes{0xDE, 0xAD, 0xBE, 0xEF}},
+		{arg: "0x0", want: Bytes{0}},
+		{arg: "0xABC", wantErr: true},
+		{arg: "ABC", wantErr: true},
+		{arg: "0xZZ", wantErr: true},
+		{arg: "ZZ", wantErr: true},
+	}
+	for n, tt := range tests {
+		t.Run(fmt.Sprintf("case-%d", n+1), func(t *testing.T) {
+			b, err := BytesFromHex(tt.arg)
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.Panics(t, func() { MustBytesFromHex(tt.arg) })
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want, b)
+			}
+		})
+	}
+}
+
 func Test_BytesType_Marshal(t *testing.T) {
 	tests := []struct {
 		arg  Bytes
@@ -573,6 +922,34 @@ func Test_BytesType_Marshal(t *testing.T) {
 	}
 }
 
+func Test_HashType_Cmp(t *testing.T) {
+	h1 := MustHashFromHex("0x01", PadLeft)
+	h2 := MustHashFromHex("0x02", PadLeft)
+	h3 := MustHashFromHex("0x01", PadRight)
+	assert.Equal(t, 0, h1.Cmp(h1))
+	assert.Equal(t, -1, h1.Cmp(h2))
+	assert.Equal(t, 1, h2.Cmp(h1))
+	assert.Equal(t, 1, h3.Cmp(h2))
+
+	hashes := HashSlice{h3, h2, h1}
+	sort.Sort(hashes)
+	assert.Equal(t, HashSlice{h1, h2, h3}, hashes)
+}
+
+func Test_AddressType_Cmp(t *testing.T) {
+	a1 := MustAddressFromHex("0x0000000000000000000000000000000000000001")
+	a2 := MustAddressFromHex("0x0000000000000000000000000000000000000002")
+	a3 := MustAddressFromHex("0x1000000000000000000000000000000000000000")
+	assert.Equal(t, 0, a1.Cmp(a1))
+	assert.Equal(t, -1, a1.Cmp(a2))
+	assert.Equal(t, 1, a2.Cmp(a1))
+	assert.Equal(t, 1, a3.Cmp(a2))
+
+	addrs := AddressSlice{a3, a1, a2}
+	sort.Sort(addrs)
+	assert.Equal(t, AddressSlice{a1, a2, a3}, addrs)
+}
+
 func Test_HashFromBigInt(t *testing.T) {
 	tests := []struct {
 		i       *big.Int
@@ -623,11 +1000,3 @@ func Test_HashFromBigInt(t *testing.T) {
 		})
 	}
 }
-
-func keccak256(data ...[]byte) Hash {
-	h := sha3.NewLegacyKeccak256()
-	for _, i := range data {
-		h.Write(i)
-	}
-	return MustHashFromBytes(h.Sum(nil), PadNone)
-}
@@ -0,0 +1,145 @@
+package types
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// Wei returns one wei expressed in wei.
+func Wei() *big.Int {
+	return big.NewInt(1)
+}
+
+// Gwei returns one gwei expressed in wei.
+func Gwei() *big.Int {
+	return big.NewInt(1e9)
+}
+
+// Ether returns one ether expressed in wei.
+func Ether() *big.Int {
+	return big.NewInt(1e18)
+}
+
+// Number of decimals of the gwei and ether units.
+const (
+	GweiDecimals  = 9
+	EtherDecimals = 18
+)
+
+// FormatUnits formats the given value as a decimal string with the given
+// number of decimals. Trailing zeros in the fractional part are removed.
+//
+// For example, FormatUnits(big.NewInt(1500000000000000000), 18) returns
+// "1.5". If the value has no fractional part, the result is an integer, e.g.
+// FormatUnits(big.NewInt(1000000000000000000), 18) returns "1".
+//
+// It panics if decimals is negative.
+func FormatUnits(value *big.Int, decimals int) string {
+	if decimals < 0 {
+		panic("types: negative decimals")
+	}
+	if value == nil {
+		return "0"
+	}
+	abs := new(big.Int).Abs(value).String()
+	if len(abs) <= decimals {
+		abs = strings.Repeat("0", decimals-len(abs)+1) + abs
+	}
+	intPart := abs[:len(abs)-decimals]
+	fracPart := strings.TrimRight(abs[len(abs)-decimals:], "0")
+	var sb strings.Builder
+	if value.Sign() < 0 {
+		sb.WriteByte('-')
+	}
+	sb.WriteString(intPart)
+	if len(fracPart) > 0 {
+		sb.WriteByte('.')
+		sb.WriteString(fracPart)
+	}
+	return sb.String()
+}
+
+// ParseUnits parses a decimal string and returns the value multiplied by
+// 10^decimals. It is the inverse of FormatUnits.
+//
+// For example, ParseUnits("1.5", 18) returns 1500000000000000000.
+//
+// It returns an error if the string is not a valid decimal number or if it
+// has more fractional digits than decimals.
+func ParseUnits(s string, decimals int) (*big.Int, error) {
+	if decimals < 0 {
+		return nil, errors.New("negative decimals")
+	}
+	str := strings.TrimSpace(s)
+	neg := false
+	switch {
+	case strings.HasPrefix(str, "-"):
+		neg = true
+		str = str[1:]
+	case strings.HasPrefix(str, "+"):
+		str = str[1:]
+	}
+	intPart, fracPart, _ := strings.Cut(str, ".")
+	if len(intPart) == 0 && len(fracPart) == 0 {
+		return nil, fmt.Errorf("invalid decimal number: %q", s)
+	}
+	if !isDigits(intPart) || !isDigits(fracPart) {
+		return nil, fmt.Errorf("invalid decimal number: %q", s)
+	}
+	trimmedFrac := strings.TrimRight(fracPart, "0")
+	if len(trimmedFrac) > decimals {
+		return nil, fmt.Errorf("too many decimal places in %q: maximum is %d", s, decimals)
+	}
+	digits := intPart + trimmedFrac + strings.Repeat("0", decimals-len(trimmedFrac))
+	x, ok := new(big.Int).SetString(digits, 10)
+	if !ok {
+		return nil, fmt.Errorf("invalid decimal number: %q", s)
+	}
+	if neg {
+		x.Neg(x)
+	}
+	return x, nil
+}
+
+// MustParseUnits is like ParseUnits but panics on error.
+func MustParseUnits(s string, decimals int) *big.Int {
+	x, err := ParseUnits(s, decimals)
+	if err != nil {
+		panic(err)
+	}
+	return x
+}
+
+// EtherToWei parses a decimal amount of ether, e.g. "1.5", and returns it in
+// wei. It returns an error if the amount has more than 18 decimal places.
+func EtherToWei(s string) (*big.Int, error) {
+	return ParseUnits(s, EtherDecimals)
+}
+
+// GweiToWei parses a decimal amount of gwei, e.g. "1.5", and returns it in
+// wei. It returns an error if the amount has more than 9 decimal places.
+func GweiToWei(s string) (*big.Int, error) {
+	return ParseUnits(s, GweiDecimals)
+}
+
+// WeiToEther formats the given amount of wei as a decimal amount of ether.
+func WeiToEther(wei *big.Int) string {
+	return FormatUnits(wei, EtherDecimals)
+}
+
+// WeiToGwei formats the given amount of wei as a decimal amount of gwei.
+func WeiToGwei(wei *big.Int) string {
+	return FormatUnits(wei, GweiDecimals)
+}
+
+// isDigits returns true if the string contains only decimal digits.
+func isDigits(s string) bool {
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
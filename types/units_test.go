@@ -0,0 +1,115 @@
+package types
+
+import (
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_FormatUnits(t *testing.T) {
+	tests := []struct {
+		value    *big.Int
+		decimals int
+		want     string
+	}{
+		{value: big.NewInt(0), decimals: 18, want: "0"},
+		{value: nil, decimals: 18, want: "0"},
+		{value: big.NewInt(1), decimals: 0, want: "1"},
+		{value: big.NewInt(1), decimals: 18, want: "0.000000000000000001"},
+		{value: big.NewInt(1500000000000000000), decimals: 18, want: "1.5"},
+		{value: big.NewInt(1000000000000000000), decimals: 18, want: "1"},
+		{value: big.NewInt(-1500000000000000000), decimals: 18, want: "-1.5"},
+		{value: big.NewInt(-1), decimals: 6, want: "-0.000001"},
+		{value: big.NewInt(123456789), decimals: 6, want: "123.456789"},
+		{value: big.NewInt(123000000), decimals: 6, want: "123"},
+		{value: new(big.Int).Lsh(big.NewInt(1), 256), decimals: 18, want: "115792089237316195423570985008687907853269984665640564039457.584007913129639936"},
+	}
+	for n, tt := range tests {
+		t.Run(fmt.Sprintf("case-%d", n+1), func(t *testing.T) {
+			assert.Equal(t, tt.want, FormatUnits(tt.value, tt.decimals))
+		})
+	}
+	assert.Panics(t, func() { FormatUnits(big.NewInt(1), -1) })
+}
+
+func Test_ParseUnits(t *testing.T) {
+	tests := []struct {
+		arg      string
+		decimals int
+		want     *big.Int
+		wantErr  bool
+	}{
+		{arg: "0", decimals: 18, want: big.NewInt(0)},
+		{arg: "1", decimals: 0, want: big.NewInt(1)},
+		{arg: "1.5", decimals: 18, want: big.NewInt(1500000000000000000)},
+		{arg: "-1.5", decimals: 18, want: big.NewInt(-1500000000000000000)},
+		{arg: "+1.5", decimals: 18, want: big.NewInt(1500000000000000000)},
+		{arg: ".5", decimals: 1, want: big.NewInt(5)},
+		{arg: "5.", decimals: 1, want: big.NewInt(50)},
+		{arg: "0.000001", decimals: 6, want: big.NewInt(1)},
+		{arg: "1.100000", decimals: 1, want: big.NewInt(11)},
+		{arg: "115792089237316195423570985008687907853269984665640564039457.584007913129639936", decimals: 18, want: new(big.Int).Lsh(big.NewInt(1), 256)},
+		{arg: "0.0000001", decimals: 6, wantErr: true},
+		{arg: "", decimals: 18, wantErr: true},
+		{arg: ".", decimals: 18, wantErr: true},
+		{arg: "-", decimals: 18, wantErr: true},
+		{arg: "1.2.3", decimals: 18, wantErr: true},
+		{arg: "1e18", decimals: 18, wantErr: true},
+		{arg: "0x10", decimals: 18, wantErr: true},
+		{arg: "--1", decimals: 18, wantErr: true},
+	}
+	for n, tt := range tests {
+		t.Run(fmt.Sprintf("case-%d", n+1), func(t *testing.T) {
+			v, err := ParseUnits(tt.arg, tt.decimals)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tt.want.String(), v.String())
+			}
+		})
+	}
+}
+
+func Test_FormatParseUnits_RoundTrip(t *testing.T) {
+	values := []string{"0", "1", "-1", "123456789012345678901234567890", "-987654321"}
+	for _, s := range values {
+		for _, decimals := range []int{0, 1, 6, 18, 36} {
+			x, _ := new(big.Int).SetString(s, 10)
+			v, err := ParseUnits(FormatUnits(x, decimals), decimals)
+			require.NoError(t, err)
+			assert.Equal(t, x.String(), v.String())
+		}
+	}
+}
+
+func Test_EtherUnits(t *testing.T) {
+	assert.Equal(t, "1", Wei().String())
+	assert.Equal(t, "1000000000", Gwei().String())
+	assert.Equal(t, "1000000000000000000", Ether().String())
+
+	// Each call returns a new value.
+	x := Ether()
+	x.Mul(x, big.NewInt(2))
+	assert.Equal(t, "1000000000000000000", Ether().String())
+
+	wei, err := EtherToWei("1.5")
+	require.NoError(t, err)
+	assert.Equal(t, "1500000000000000000", wei.String())
+	assert.Equal(t, "1.5", WeiToEther(wei))
+	assert.Equal(t, "1500000000", WeiToGwei(wei))
+
+	wei, err = GweiToWei("2.5")
+	require.NoError(t, err)
+	assert.Equal(t, "2500000000", wei.String())
+	assert.Equal(t, "2.5", WeiToGwei(wei))
+	assert.Equal(t, "0.0000000025", WeiToEther(wei))
+
+	_, err = EtherToWei("0.0000000000000000001")
+	assert.Error(t, err)
+	_, err = GweiToWei("0.0000000001")
+	assert.Error(t, err)
+}
@@ -4,10 +4,50 @@ import (
 	"bytes"
 	"fmt"
 	"math/big"
+	"strings"
+
+	"golang.org/x/crypto/sha3"
 
 	"github.com/defiweb/go-eth/hexutil"
 )
 
+// keccak256 calculates the Keccak256 hash of the given data.
+//
+// The types package cannot depend on the crypto package, so this function
+// is used where a hash is required by the Ethereum protocol itself.
+func keccak256(data ...[]byte) Hash {
+	h := sha3.NewLegacyKeccak256()
+	for _, i := range data {
+		h.Write(i)
+	}
+	return MustHashFromBytes(h.Sum(nil), PadNone)
+}
+
+// isMixedCase returns true if the given hex string, ignoring the "0x"
+// prefix, contains both lowercase and uppercase letters.
+func isMixedCase(h string) bool {
+	if hexutil.Has0xPrefix(h) {
+		h = h[2:]
+	}
+	return strings.ToLower(h) != h && strings.ToUpper(h) != h
+}
+
+// bigToHash converts the absolute value of the given big integer to a Hash.
+// A nil value is treated as zero. If the value does not fit in 32 bytes, only
+// the least significant 32 bytes are used.
+func bigToHash(x *big.Int) Hash {
+	var h Hash
+	if x == nil {
+		return h
+	}
+	b := x.Bytes()
+	if len(b) > HashLength {
+		b = b[len(b)-HashLength:]
+	}
+	copy(h[HashLength-len(b):], b)
+	return h
+}
+
 // bytesMarshalJSON encodes the given bytes as a JSON string where each byte is
 // represented by a two-digit hex number. The hex string is always even-length
 // and prefixed with "0x".
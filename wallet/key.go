@@ -2,7 +2,10 @@ package wallet
 
 import (
 	"context"
+	"fmt"
+	"math/big"
 
+	"github.com/defiweb/go-eth/crypto"
 	"github.com/defiweb/go-eth/types"
 )
 
@@ -33,3 +36,58 @@ type KeyWithHashSigner interface {
 	// EIP-191 message prefix.
 	VerifyHash(ctx context.Context, hash types.Hash, sig types.Signature) bool
 }
+
+// Signer is the minimal interface required to sign data and transactions on
+// behalf of an address. It allows to use external signers, such as KMS, HSM
+// or remote signing services, that only expose raw hash signing.
+//
+// Every KeyWithHashSigner is also a Signer.
+type Signer interface {
+	// Address returns the address of the signer.
+	Address() types.Address
+
+	// SignHash signs the given hash without the EIP-191 message prefix.
+	SignHash(ctx context.Context, hash types.Hash) (*types.Signature, error)
+
+	// SignTransaction signs the given transaction.
+	SignTransaction(ctx context.Context, tx *types.Transaction) error
+}
+
+// RecoverCompact recovers the address that signed the given hash from a
+// signature in the 65-byte [R || S || V] format, as returned by
+// PrivateKey.SignCompact. V may be either 27 or 28, or 0 or 1.
+func RecoverCompact(hash types.Hash, sig []byte) (types.Address, error) {
+	if len(sig) != 65 {
+		return types.Address{}, fmt.Errorf("invalid signature length: %d", len(sig))
+	}
+	switch sig[64] {
+	case 0, 1, 27, 28:
+	default:
+		return types.Address{}, fmt.Errorf("invalid signature V value: %d", sig[64])
+	}
+	addr, err := crypto.ECRecoverer.RecoverHash(hash, types.MustSignatureFromBytes(sig))
+	if err != nil {
+		return types.Address{}, err
+	}
+	return *addr, nil
+}
+
+// RecoverValidatorData recovers the address that signed the given data using
+// the EIP-191 version 0x00 format, as returned by PrivateKey.SignValidatorData.
+// V may be either 27 or 28, or 0 or 1.
+func RecoverValidatorData(validator types.Address, data []byte, sig types.Signature) (types.Address, error) {
+	if sig.V == nil {
+		return types.Address{}, fmt.Errorf("invalid signature V value: nil")
+	}
+	if sig.V.Cmp(big.NewInt(27)) >= 0 {
+		sig.V = new(big.Int).Sub(sig.V, big.NewInt(27))
+	}
+	if sig.V.Sign() < 0 || sig.V.Cmp(big.NewInt(1)) > 0 {
+		return types.Address{}, fmt.Errorf("invalid signature V value: %s", sig.V)
+	}
+	addr, err := crypto.ECRecoverer.RecoverHash(crypto.Keccak256(crypto.AddValidatorPrefix(validator, data)), sig)
+	if err != nil {
+		return types.Address{}, err
+	}
+	return *addr, nil
+}
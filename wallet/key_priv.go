@@ -5,6 +5,7 @@ import (
 	"crypto/ecdsa"
 	"crypto/rand"
 	"encoding/json"
+	"math/big"
 
 	"github.com/btcsuite/btcd/btcec/v2"
 
@@ -48,6 +49,20 @@ func NewRandomKey() *PrivateKey {
 	return NewKeyFromECDSA(key)
 }
 
+// WithEIP155 returns a copy of the key that signs legacy transactions with or
+// without EIP-155 replay protection. By default, EIP-155 is used if the
+// transaction chain ID is set. If disabled, legacy transactions are signed
+// with V equal to 27 or 28, even if the chain ID is set.
+func (k *PrivateKey) WithEIP155(enabled bool) *PrivateKey {
+	cpy := *k
+	if enabled {
+		cpy.sign = crypto.ECSigner(k.private)
+	} else {
+		cpy.sign = crypto.ECSignerWithoutEIP155(k.private)
+	}
+	return &cpy
+}
+
 // PublicKey returns the ECDSA public key.
 func (k *PrivateKey) PublicKey() *ecdsa.PublicKey {
 	return k.public
@@ -77,6 +92,30 @@ func (k *PrivateKey) SignHash(_ context.Context, hash types.Hash) (*types.Signat
 	return k.sign.SignHash(hash)
 }
 
+// SignCompact signs the given hash without the EIP-191 message prefix and
+// returns the signature in the 65-byte [R || S || V] format, where V is 27
+// or 28. The signature can be verified using RecoverCompact.
+func (k *PrivateKey) SignCompact(ctx context.Context, hash types.Hash) ([]byte, error) {
+	sig, err := k.SignHash(ctx, hash)
+	if err != nil {
+		return nil, err
+	}
+	sig.V = new(big.Int).Add(sig.V, big.NewInt(27))
+	return sig.Bytes(), nil
+}
+
+// SignValidatorData signs the given data using the EIP-191 version 0x00
+// format, with the intended validator address. The V value of the signature
+// is 27 or 28. The signature can be verified using RecoverValidatorData.
+func (k *PrivateKey) SignValidatorData(ctx context.Context, validator types.Address, data []byte) (*types.Signature, error) {
+	sig, err := k.SignHash(ctx, crypto.Keccak256(crypto.AddValidatorPrefix(validator, data)))
+	if err != nil {
+		return nil, err
+	}
+	sig.V = new(big.Int).Add(sig.V, big.NewInt(27))
+	return sig, nil
+}
+
 // SignMessage implements the Key interface.
 func (k *PrivateKey) SignMessage(_ context.Context, data []byte) (*types.Signature, error) {
 	return k.sign.SignMessage(data)
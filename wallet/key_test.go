@@ -0,0 +1,79 @@
+package wallet
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/defiweb/go-eth/crypto"
+	"github.com/defiweb/go-eth/types"
+)
+
+func TestPrivateKey_SignCompact(t *testing.T) {
+	key := NewKeyFromBytes(bytes.Repeat([]byte{0x01}, 32))
+	hash := types.MustHashFromBytes(bytes.Repeat([]byte{0x02}, 32), types.PadNone)
+
+	sig, err := key.SignCompact(context.Background(), hash)
+	require.NoError(t, err)
+	require.Len(t, sig, 65)
+	assert.Contains(t, []byte{27, 28}, sig[64])
+
+	addr, err := RecoverCompact(hash, sig)
+	require.NoError(t, err)
+	assert.Equal(t, key.Address(), addr)
+
+	// V in the {0, 1} range is also accepted.
+	sig[64] -= 27
+	addr, err = RecoverCompact(hash, sig)
+	require.NoError(t, err)
+	assert.Equal(t, key.Address(), addr)
+
+	_, err = RecoverCompact(hash, sig[:64])
+	assert.Error(t, err)
+
+	sig[64] = 2
+	_, err = RecoverCompact(hash, sig)
+	assert.Error(t, err)
+}
+
+func TestPrivateKey_SignValidatorData(t *testing.T) {
+	key := NewKeyFromBytes(bytes.Repeat([]byte{0x01}, 32))
+	validator := types.MustAddressFromHex("0x1111111111111111111111111111111111111111")
+	data := []byte("hello")
+
+	sig, err := key.SignValidatorData(context.Background(), validator, data)
+	require.NoError(t, err)
+	assert.Contains(t, []int64{27, 28}, sig.V.Int64())
+
+	// The signed hash is keccak256(0x19 || 0x00 || validator || data).
+	hash := crypto.Keccak256(append(append([]byte{0x19, 0x00}, validator.Bytes()...), data...))
+	compact := sig.Bytes()
+	addr, err := RecoverCompact(hash, compact)
+	require.NoError(t, err)
+	assert.Equal(t, key.Address(), addr)
+
+	addr, err = RecoverValidatorData(validator, data, *sig)
+	require.NoError(t, err)
+	assert.Equal(t, key.Address(), addr)
+
+	// V in the {0, 1} range is also accepted.
+	compact[64] -= 27
+	addr, err = RecoverValidatorData(validator, data, types.MustSignatureFromBytes(compact))
+	require.NoError(t, err)
+	assert.Equal(t, key.Address(), addr)
+
+	// Different validator or data recovers a different address.
+	addr, err = RecoverValidatorData(types.MustAddressFromHex("0x2222222222222222222222222222222222222222"), data, *sig)
+	require.NoError(t, err)
+	assert.NotEqual(t, key.Address(), addr)
+	addr, err = RecoverValidatorData(validator, []byte("world"), *sig)
+	require.NoError(t, err)
+	assert.NotEqual(t, key.Address(), addr)
+
+	compact[64] = 2
+	_, err = RecoverValidatorData(validator, data, types.MustSignatureFromBytes(compact))
+	assert.Error(t, err)
+}